@@ -1,6 +1,8 @@
 package api
 
 import (
+	"crypto/tls"
+
 	"github.com/enbility/ship-go/logging"
 
 	shipapi "github.com/enbility/ship-go/api"
@@ -27,6 +29,10 @@ type ServiceInterface interface {
 	// set logging interface
 	SetLogging(logger logging.LoggingInterface)
 
+	// set the trust store used to persist trusted remote services
+	// has to be invoked before Setup
+	SetTrustStore(store TrustStoreInterface)
+
 	// return the configuration
 	Configuration() *Configuration
 
@@ -36,6 +42,10 @@ type ServiceInterface interface {
 	// return the local device
 	LocalDevice() spineapi.DeviceLocalInterface
 
+	// add a use case to the local device
+	// has to be invoked after Setup and before Start
+	AddUseCase(usecase UseCaseInterface)
+
 	// Passthough functions to HubInterface
 
 	// Provide the current pairing state for a SKI
@@ -74,8 +84,7 @@ type ServiceReaderInterface interface {
 	VisibleRemoteServicesUpdated(service ServiceInterface, entries []shipapi.RemoteService)
 
 	// Provides the SHIP ID the remote service reported during the handshake process
-	// This needs to be persisted and passed on for future remote service connections
-	// when using `PairRemoteService`
+	// For trusted remote services this is persisted in the trust store automatically
 	ServiceShipIDUpdate(ski string, shipdID string)
 
 	// Provides the current pairing state for the remote service
@@ -86,3 +95,38 @@ type ServiceReaderInterface interface {
 	// return if the user is still able to trust the connection
 	AllowWaitingForTrust(ski string) bool
 }
+
+/* Trust Store */
+
+// a remote service the local service trusts and should reconnect to
+type TrustedService struct {
+	// The SKI of the remote service
+	SKI string `json:"ski"`
+
+	// The SHIP ID the remote service reported during the last handshake, optional
+	ShipID string `json:"shipId,omitempty"`
+}
+
+// interface for persisting trusted remote services
+//
+// implemented by the trust stores in service, used by service
+type TrustStoreInterface interface {
+	// return all trusted remote services
+	TrustedServices() ([]TrustedService, error)
+
+	// add or update a trusted remote service
+	SetTrustedService(service TrustedService) error
+
+	// remove a trusted remote service for a given SKI
+	RemoveTrustedService(ski string) error
+}
+
+/* Certificate */
+
+// interface for providing the certificate of the local service
+//
+// implemented by service.CertificateManager, used by Configuration
+type CertificateProviderInterface interface {
+	// return the currently used certificate
+	Certificate() tls.Certificate
+}
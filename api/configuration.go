@@ -62,8 +62,13 @@ type Configuration struct {
 	port int
 
 	// The certificate used for the service and its connections, required
+	// unless a certificateProvider is set
 	certificate tls.Certificate
 
+	// Optional provider of the certificate, e.g. service.CertificateManager
+	// If set, the provided certificate is used instead of certificate
+	certificateProvider CertificateProviderInterface
+
 	// Wether remote devices should be automatically accepted
 	// If enabled will automatically search for other services with
 	// the same setting and automatically connect to them.
@@ -245,7 +250,15 @@ func (s *Configuration) MdnsServiceName() string {
 	return s.generateIdentifier()
 }
 
+// return the certificate to be used for the service
+// returns in this order:
+// - the certificate of the certificateProvider
+// - the certificate
 func (s *Configuration) Certificate() tls.Certificate {
+	if s.certificateProvider != nil {
+		return s.certificateProvider.Certificate()
+	}
+
 	return s.certificate
 }
 
@@ -257,6 +270,16 @@ func (s *Configuration) SetCertificate(cert tls.Certificate) {
 	s.certificate = cert
 }
 
+// define a provider for the certificate, e.g. service.CertificateManager
+// the provided certificate is used instead of the one set via SetCertificate
+func (s *Configuration) SetCertificateProvider(provider CertificateProviderInterface) {
+	s.certificateProvider = provider
+}
+
+func (s *Configuration) CertificateProvider() CertificateProviderInterface {
+	return s.certificateProvider
+}
+
 func (s *Configuration) RegisterAutoAccept() bool {
 	return s.registerAutoAccept
 }
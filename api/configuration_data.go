@@ -0,0 +1,391 @@
+package api
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enbility/ship-go/mdns"
+	"github.com/enbility/spine-go/model"
+	"gopkg.in/yaml.v3"
+)
+
+// the default prefix of environment variables overriding ConfigurationData values
+const DefaultEnvironmentPrefix = "EEBUS"
+
+// the default heartbeat timeout, if none is provided
+const defaultHeartbeatTimeout = time.Second * 4
+
+// the supported values for the mDNS provider selection
+const (
+	MdnsProviderAll      = "all"
+	MdnsProviderAvahi    = "avahi"
+	MdnsProviderZeroconf = "zeroconf"
+)
+
+// the supported SPINE device types
+var deviceTypes = []model.DeviceTypeType{
+	model.DeviceTypeTypeDishwasher,
+	model.DeviceTypeTypeDryer,
+	model.DeviceTypeTypeEnvironmentSensor,
+	model.DeviceTypeTypeGeneric,
+	model.DeviceTypeTypeHeatgenerationSystem,
+	model.DeviceTypeTypeHeatsinkSystem,
+	model.DeviceTypeTypeHeatstorageSystem,
+	model.DeviceTypeTypeHVACController,
+	model.DeviceTypeTypeSubmeter,
+	model.DeviceTypeTypeWasher,
+	model.DeviceTypeTypeElectricitySupplySystem,
+	model.DeviceTypeTypeEnergyManagementSystem,
+	model.DeviceTypeTypeInverter,
+	model.DeviceTypeTypeChargingStation,
+}
+
+// declarative definition of a Configuration
+//
+// This can be loaded from YAML or JSON files using LoadConfigurationData
+// and overridden with environment variables using ApplyEnvironment
+type ConfigurationData struct {
+	// The vendors IANA PEN, optional
+	VendorCode string `json:"vendorCode,omitempty" yaml:"vendorCode,omitempty"`
+
+	// The brand of the device, required
+	DeviceBrand string `json:"deviceBrand" yaml:"deviceBrand"`
+
+	// The device model, required
+	DeviceModel string `json:"deviceModel" yaml:"deviceModel"`
+
+	// Serial number of the device, required
+	SerialNumber string `json:"serialNumber" yaml:"serialNumber"`
+
+	// SPINE device type of the device model, e.g. "EnergyManagementSystem", required
+	DeviceType string `json:"deviceType" yaml:"deviceType"`
+
+	// SPINE entity types for each entity that should be created, required
+	EntityTypes []string `json:"entityTypes" yaml:"entityTypes"`
+
+	// The port address of the websocket server, optional, default is 4711
+	Port int `json:"port,omitempty" yaml:"port,omitempty"`
+
+	// The sites grid voltage, optional
+	Voltage float64 `json:"voltage,omitempty" yaml:"voltage,omitempty"`
+
+	// The timeout to be used for sending heartbeats, e.g. "4s", optional, default is 4s
+	HeartbeatTimeout string `json:"heartbeatTimeout,omitempty" yaml:"heartbeatTimeout,omitempty"`
+
+	// An alternate mDNS service and SHIP identifier, optional
+	AlternateIdentifier string `json:"alternateIdentifier,omitempty" yaml:"alternateIdentifier,omitempty"`
+
+	// An alternate mDNS service name, optional
+	AlternateMdnsServiceName string `json:"alternateMdnsServiceName,omitempty" yaml:"alternateMdnsServiceName,omitempty"`
+
+	// Network interface names to use for the service, optional
+	Interfaces []string `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
+
+	// Which mDNS providers should be used: "all", "avahi" or "zeroconf", optional, default is "all"
+	MdnsProviderSelection string `json:"mdnsProviderSelection,omitempty" yaml:"mdnsProviderSelection,omitempty"`
+
+	// Wether remote devices should be automatically accepted, optional
+	RegisterAutoAccept bool `json:"registerAutoAccept,omitempty" yaml:"registerAutoAccept,omitempty"`
+
+	// Path of the PEM encoded certificate file
+	// Required together with KeyFile, unless a certificate is provided to Configuration
+	CertificateFile string `json:"certificateFile,omitempty" yaml:"certificateFile,omitempty"`
+
+	// Path of the PEM encoded private key file
+	// Required together with CertificateFile, unless a certificate is provided to Configuration
+	KeyFile string `json:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+}
+
+// load the configuration data from a YAML or JSON file
+//
+// the format is detected using the file extension:
+// ".yaml" and ".yml" for YAML, ".json" for JSON
+func LoadConfigurationData(path string) (*ConfigurationData, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ConfigurationData{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, result)
+	case ".json":
+		err = json.Unmarshal(data, result)
+	default:
+		return nil, fmt.Errorf("unsupported configuration file format: %s", path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// override the values with environment variables
+//
+// the variables are named using the prefix and the field name, e.g. with the
+// prefix "EEBUS": EEBUS_DEVICE_BRAND, EEBUS_PORT, EEBUS_ENTITY_TYPES
+// lists like entity types and interfaces are comma separated
+//
+// returns an error listing all variables with invalid values
+func (c *ConfigurationData) ApplyEnvironment(prefix string) error {
+	var errs []error
+
+	lookup := func(name string) (string, bool) {
+		return os.LookupEnv(fmt.Sprintf("%s_%s", prefix, name))
+	}
+
+	stringValues := map[string]*string{
+		"VENDOR_CODE":                 &c.VendorCode,
+		"DEVICE_BRAND":                &c.DeviceBrand,
+		"DEVICE_MODEL":                &c.DeviceModel,
+		"SERIAL_NUMBER":               &c.SerialNumber,
+		"DEVICE_TYPE":                 &c.DeviceType,
+		"HEARTBEAT_TIMEOUT":           &c.HeartbeatTimeout,
+		"ALTERNATE_IDENTIFIER":        &c.AlternateIdentifier,
+		"ALTERNATE_MDNS_SERVICE_NAME": &c.AlternateMdnsServiceName,
+		"MDNS_PROVIDER_SELECTION":     &c.MdnsProviderSelection,
+		"CERTIFICATE_FILE":            &c.CertificateFile,
+		"KEY_FILE":                    &c.KeyFile,
+	}
+	for name, field := range stringValues {
+		if value, ok := lookup(name); ok {
+			*field = value
+		}
+	}
+
+	listValues := map[string]*[]string{
+		"ENTITY_TYPES": &c.EntityTypes,
+		"INTERFACES":   &c.Interfaces,
+	}
+	for name, field := range listValues {
+		if value, ok := lookup(name); ok {
+			*field = splitList(value)
+		}
+	}
+
+	if value, ok := lookup("PORT"); ok {
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_PORT is invalid: %w", prefix, err))
+		} else {
+			c.Port = port
+		}
+	}
+
+	if value, ok := lookup("VOLTAGE"); ok {
+		voltage, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_VOLTAGE is invalid: %w", prefix, err))
+		} else {
+			c.Voltage = voltage
+		}
+	}
+
+	if value, ok := lookup("REGISTER_AUTO_ACCEPT"); ok {
+		autoAccept, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_REGISTER_AUTO_ACCEPT is invalid: %w", prefix, err))
+		} else {
+			c.RegisterAutoAccept = autoAccept
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// check all values
+//
+// returns an error listing every invalid field,
+// the certificate and key files are required
+func (c *ConfigurationData) Validate() error {
+	return c.validate(false)
+}
+
+// check all values, the certificate and key files are
+// only required if no certificate is provided
+func (c *ConfigurationData) validate(hasCertificate bool) error {
+	var errs []error
+
+	isRequired := "is required"
+
+	if len(c.DeviceBrand) == 0 {
+		errs = append(errs, fmt.Errorf("deviceBrand %s", isRequired))
+	}
+
+	if len(c.DeviceModel) == 0 {
+		errs = append(errs, fmt.Errorf("deviceModel %s", isRequired))
+	}
+
+	if len(c.SerialNumber) == 0 {
+		errs = append(errs, fmt.Errorf("serialNumber %s", isRequired))
+	}
+
+	if len(c.DeviceType) == 0 {
+		errs = append(errs, fmt.Errorf("deviceType %s", isRequired))
+	} else if !slices.Contains(deviceTypes, model.DeviceTypeType(c.DeviceType)) {
+		errs = append(errs, fmt.Errorf("deviceType %s is not supported", c.DeviceType))
+	}
+
+	if len(c.EntityTypes) == 0 {
+		errs = append(errs, fmt.Errorf("entityTypes %s", isRequired))
+	}
+	for i, item := range c.EntityTypes {
+		if len(item) == 0 {
+			errs = append(errs, fmt.Errorf("entityTypes[%d] may not be empty", i))
+			continue
+		}
+		if slices.Contains(c.EntityTypes[:i], item) {
+			errs = append(errs, fmt.Errorf("entityTypes[%d] %s is used multiple times", i, item))
+		}
+	}
+
+	if c.Port < 0 || c.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d is not a valid port", c.Port))
+	}
+
+	if c.Voltage < 0 {
+		errs = append(errs, fmt.Errorf("voltage %v may not be negative", c.Voltage))
+	}
+
+	if _, err := c.heartbeatTimeout(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if _, err := c.mdnsProviderSelection(); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i, item := range c.Interfaces {
+		if len(item) == 0 {
+			errs = append(errs, fmt.Errorf("interfaces[%d] may not be empty", i))
+		}
+	}
+
+	if len(c.CertificateFile) > 0 && len(c.KeyFile) == 0 {
+		errs = append(errs, fmt.Errorf("keyFile %s if certificateFile is set", isRequired))
+	}
+
+	if len(c.KeyFile) > 0 && len(c.CertificateFile) == 0 {
+		errs = append(errs, fmt.Errorf("certificateFile %s if keyFile is set", isRequired))
+	}
+
+	if !hasCertificate && len(c.CertificateFile) == 0 && len(c.KeyFile) == 0 {
+		errs = append(errs, errors.New("certificateFile and keyFile are required if no certificate is provided"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// create a Configuration using the provided certificate
+//
+// returns the validation errors if the data is invalid, the certificate and
+// key files are not required if a certificate is provided.
+// use service.NewConfigurationFromData for using the certificate and key files
+func (c *ConfigurationData) Configuration(certificate tls.Certificate) (*Configuration, error) {
+	if err := c.validate(len(certificate.Certificate) > 0); err != nil {
+		return nil, err
+	}
+
+	// the values are already validated
+	heartbeatTimeout, _ := c.heartbeatTimeout()
+	providerSelection, _ := c.mdnsProviderSelection()
+
+	// the vendor code is optional, the brand is used instead as described in Configuration
+	vendorCode := c.VendorCode
+	if len(vendorCode) == 0 {
+		vendorCode = c.DeviceBrand
+	}
+
+	entityTypes := make([]model.EntityTypeType, 0, len(c.EntityTypes))
+	for _, item := range c.EntityTypes {
+		entityTypes = append(entityTypes, model.EntityTypeType(item))
+	}
+
+	configuration, err := NewConfiguration(
+		vendorCode,
+		c.DeviceBrand,
+		c.DeviceModel,
+		c.SerialNumber,
+		model.DeviceTypeType(c.DeviceType),
+		entityTypes,
+		c.Port,
+		certificate,
+		c.Voltage,
+		heartbeatTimeout,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.AlternateIdentifier) > 0 {
+		configuration.SetAlternateIdentifier(c.AlternateIdentifier)
+	}
+	if len(c.AlternateMdnsServiceName) > 0 {
+		configuration.SetAlternateMdnsServiceName(c.AlternateMdnsServiceName)
+	}
+	if len(c.Interfaces) > 0 {
+		configuration.SetInterfaces(c.Interfaces)
+	}
+	configuration.SetMdnsProviderSelection(providerSelection)
+	configuration.SetRegisterAutoAccept(c.RegisterAutoAccept)
+
+	return configuration, nil
+}
+
+// return the parsed heartbeat timeout or the default one if none is set
+func (c *ConfigurationData) heartbeatTimeout() (time.Duration, error) {
+	if len(c.HeartbeatTimeout) == 0 {
+		return defaultHeartbeatTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(c.HeartbeatTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("heartbeatTimeout %s is invalid: %w", c.HeartbeatTimeout, err)
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("heartbeatTimeout %s has to be positive", c.HeartbeatTimeout)
+	}
+
+	return timeout, nil
+}
+
+// return the mDNS provider selection, default is all providers
+func (c *ConfigurationData) mdnsProviderSelection() (mdns.MdnsProviderSelection, error) {
+	switch c.MdnsProviderSelection {
+	case "", MdnsProviderAll:
+		return mdns.MdnsProviderSelectionAll, nil
+	case MdnsProviderAvahi:
+		return mdns.MdnsProviderSelectionAvahiOnly, nil
+	case MdnsProviderZeroconf:
+		return mdns.MdnsProviderSelectionGoZeroConfOnly, nil
+	default:
+		return 0, fmt.Errorf("mdnsProviderSelection %s is not supported", c.MdnsProviderSelection)
+	}
+}
+
+// split a comma separated list and trim all items
+func splitList(value string) []string {
+	if len(strings.TrimSpace(value)) == 0 {
+		return nil
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		result = append(result, strings.TrimSpace(item))
+	}
+
+	return result
+}
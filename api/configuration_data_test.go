@@ -0,0 +1,294 @@
+package api
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/enbility/ship-go/cert"
+	"github.com/enbility/ship-go/mdns"
+	spinemodel "github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestConfigurationDataSuite(t *testing.T) {
+	suite.Run(t, new(ConfigurationDataSuite))
+}
+
+type ConfigurationDataSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func (s *ConfigurationDataSuite) BeforeTest(suiteName, testName string) {
+	s.dir = s.T().TempDir()
+}
+
+func (s *ConfigurationDataSuite) writeFile(name, content string) string {
+	path := filepath.Join(s.dir, name)
+	err := os.WriteFile(path, []byte(content), 0600)
+	assert.Nil(s.T(), err)
+
+	return path
+}
+
+func (s *ConfigurationDataSuite) Test_LoadYAML() {
+	path := s.writeFile("config.yaml", `
+vendorCode: vendor
+deviceBrand: brand
+deviceModel: model
+serialNumber: serial
+deviceType: EnergyManagementSystem
+entityTypes:
+  - CEM
+port: 4712
+voltage: 230
+heartbeatTimeout: 5s
+alternateIdentifier: alternate
+alternateMdnsServiceName: mdnsname
+interfaces:
+  - eth0
+mdnsProviderSelection: avahi
+registerAutoAccept: true
+certificateFile: service.crt
+keyFile: service.key
+`)
+
+	data, err := LoadConfigurationData(path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), &ConfigurationData{
+		VendorCode:               "vendor",
+		DeviceBrand:              "brand",
+		DeviceModel:              "model",
+		SerialNumber:             "serial",
+		DeviceType:               "EnergyManagementSystem",
+		EntityTypes:              []string{"CEM"},
+		Port:                     4712,
+		Voltage:                  230,
+		HeartbeatTimeout:         "5s",
+		AlternateIdentifier:      "alternate",
+		AlternateMdnsServiceName: "mdnsname",
+		Interfaces:               []string{"eth0"},
+		MdnsProviderSelection:    "avahi",
+		RegisterAutoAccept:       true,
+		CertificateFile:          "service.crt",
+		KeyFile:                  "service.key",
+	}, data)
+
+	assert.Nil(s.T(), data.Validate())
+
+	config, err := data.Configuration(tls.Certificate{})
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), config)
+
+	assert.Equal(s.T(), "vendor", config.VendorCode())
+	assert.Equal(s.T(), "brand", config.DeviceBrand())
+	assert.Equal(s.T(), "model", config.DeviceModel())
+	assert.Equal(s.T(), "serial", config.DeviceSerialNumber())
+	assert.Equal(s.T(), spinemodel.DeviceTypeTypeEnergyManagementSystem, config.DeviceType())
+	assert.Equal(s.T(), []spinemodel.EntityTypeType{spinemodel.EntityTypeTypeCEM}, config.EntityTypes())
+	assert.Equal(s.T(), 4712, config.Port())
+	assert.Equal(s.T(), 230.0, config.Voltage())
+	assert.Equal(s.T(), time.Second*5, config.HeartbeatTimeout())
+	assert.Equal(s.T(), "alternate", config.Identifier())
+	assert.Equal(s.T(), "mdnsname", config.MdnsServiceName())
+	assert.Equal(s.T(), []string{"eth0"}, config.Interfaces())
+	assert.Equal(s.T(), mdns.MdnsProviderSelectionAvahiOnly, config.MdnsProviderSelection())
+	assert.Equal(s.T(), true, config.RegisterAutoAccept())
+}
+
+func (s *ConfigurationDataSuite) Test_LoadJSON() {
+	path := s.writeFile("config.json", `{
+	"deviceBrand": "brand",
+	"deviceModel": "model",
+	"serialNumber": "serial",
+	"deviceType": "ChargingStation",
+	"entityTypes": ["EVSE"],
+	"mdnsProviderSelection": "zeroconf"
+}`)
+
+	data, err := LoadConfigurationData(path)
+	assert.Nil(s.T(), err)
+
+	// no certificate and key files are defined, so a certificate has to be provided
+	config, err := data.Configuration(tls.Certificate{})
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), config)
+
+	certificate, err := cert.CreateCertificate("unit", "org", "DE", "CN")
+	assert.Nil(s.T(), err)
+	config, err = data.Configuration(certificate)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), config)
+
+	// defaults
+	assert.Equal(s.T(), "brand", config.VendorCode())
+	assert.Equal(s.T(), defaultPort, config.Port())
+	assert.Equal(s.T(), defaultHeartbeatTimeout, config.HeartbeatTimeout())
+	assert.Equal(s.T(), mdns.MdnsProviderSelectionGoZeroConfOnly, config.MdnsProviderSelection())
+	assert.Nil(s.T(), config.Interfaces())
+	assert.Equal(s.T(), "brand-model-serial", config.Identifier())
+}
+
+func (s *ConfigurationDataSuite) Test_LoadErrors() {
+	data, err := LoadConfigurationData(filepath.Join(s.dir, "missing.yaml"))
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), data)
+
+	path := s.writeFile("config.txt", "")
+	data, err = LoadConfigurationData(path)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), data)
+
+	path = s.writeFile("config.json", "invalid")
+	data, err = LoadConfigurationData(path)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), data)
+
+	path = s.writeFile("config.yml", "port: invalid")
+	data, err = LoadConfigurationData(path)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), data)
+}
+
+func (s *ConfigurationDataSuite) Test_ApplyEnvironment() {
+	data := &ConfigurationData{
+		DeviceBrand: "brand",
+		Port:        4711,
+	}
+
+	s.T().Setenv("TEST_DEVICE_BRAND", "envbrand")
+	s.T().Setenv("TEST_ENTITY_TYPES", "CEM, EVSE")
+	s.T().Setenv("TEST_INTERFACES", "")
+	s.T().Setenv("TEST_PORT", "4800")
+	s.T().Setenv("TEST_VOLTAGE", "400")
+	s.T().Setenv("TEST_REGISTER_AUTO_ACCEPT", "true")
+	s.T().Setenv("TEST_KEY_FILE", "env.key")
+
+	err := data.ApplyEnvironment("TEST")
+	assert.Nil(s.T(), err)
+
+	assert.Equal(s.T(), "envbrand", data.DeviceBrand)
+	assert.Equal(s.T(), []string{"CEM", "EVSE"}, data.EntityTypes)
+	assert.Nil(s.T(), data.Interfaces)
+	assert.Equal(s.T(), 4800, data.Port)
+	assert.Equal(s.T(), 400.0, data.Voltage)
+	assert.Equal(s.T(), true, data.RegisterAutoAccept)
+	assert.Equal(s.T(), "env.key", data.KeyFile)
+
+	s.T().Setenv("TEST_PORT", "invalid")
+	s.T().Setenv("TEST_VOLTAGE", "invalid")
+	s.T().Setenv("TEST_REGISTER_AUTO_ACCEPT", "invalid")
+
+	err = data.ApplyEnvironment("TEST")
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), 3, len(strings.Split(err.Error(), "\n")))
+	assert.Equal(s.T(), 4800, data.Port)
+}
+
+func (s *ConfigurationDataSuite) Test_Validate() {
+	data := &ConfigurationData{
+		DeviceType:            "invalid",
+		EntityTypes:           []string{"CEM", "", "CEM"},
+		Port:                  70000,
+		Voltage:               -1,
+		HeartbeatTimeout:      "invalid",
+		MdnsProviderSelection: "invalid",
+		Interfaces:            []string{""},
+		CertificateFile:       "service.crt",
+	}
+
+	err := data.Validate()
+	assert.NotNil(s.T(), err)
+
+	// every invalid field is reported
+	messages := strings.Split(err.Error(), "\n")
+	assert.Equal(s.T(), 12, len(messages))
+	for _, field := range []string{
+		"deviceBrand", "deviceModel", "serialNumber", "deviceType",
+		"entityTypes[1]", "entityTypes[2]", "port", "voltage",
+		"heartbeatTimeout", "mdnsProviderSelection", "interfaces[0]", "keyFile",
+	} {
+		assert.Contains(s.T(), err.Error(), field)
+	}
+
+	config, err := data.Configuration(tls.Certificate{})
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), config)
+
+	data = &ConfigurationData{
+		HeartbeatTimeout: "-1s",
+		KeyFile:          "service.key",
+	}
+	err = data.Validate()
+	assert.NotNil(s.T(), err)
+	assert.Contains(s.T(), err.Error(), "entityTypes is required")
+	assert.Contains(s.T(), err.Error(), "deviceType is required")
+	assert.Contains(s.T(), err.Error(), "heartbeatTimeout -1s has to be positive")
+	assert.Contains(s.T(), err.Error(), "certificateFile is required")
+}
+
+func (s *ConfigurationDataSuite) Test_ValidateCertificate() {
+	certificate, err := cert.CreateCertificate("unit", "org", "DE", "CN")
+	assert.Nil(s.T(), err)
+
+	tests := []struct {
+		name            string
+		certificateFile string
+		keyFile         string
+		certificate     tls.Certificate
+		err             string
+	}{
+		{
+			name: "no certificate",
+			err:  "certificateFile and keyFile are required if no certificate is provided",
+		},
+		{
+			name:        "provided certificate",
+			certificate: certificate,
+		},
+		{
+			name:            "certificate and key files",
+			certificateFile: "service.crt",
+			keyFile:         "service.key",
+		},
+		{
+			name:            "certificate file without key file",
+			certificateFile: "service.crt",
+			err:             "keyFile is required if certificateFile is set",
+		},
+		{
+			name:    "key file without certificate file",
+			keyFile: "service.key",
+			err:     "certificateFile is required if keyFile is set",
+		},
+	}
+
+	for _, tc := range tests {
+		data := &ConfigurationData{
+			DeviceBrand:     "brand",
+			DeviceModel:     "model",
+			SerialNumber:    "serial",
+			DeviceType:      "EnergyManagementSystem",
+			EntityTypes:     []string{"CEM"},
+			CertificateFile: tc.certificateFile,
+			KeyFile:         tc.keyFile,
+		}
+
+		config, err := data.Configuration(tc.certificate)
+		if len(tc.err) == 0 {
+			assert.Nil(s.T(), err, tc.name)
+			assert.NotNil(s.T(), config, tc.name)
+			continue
+		}
+
+		assert.NotNil(s.T(), err, tc.name)
+		assert.Nil(s.T(), config, tc.name)
+		assert.EqualError(s.T(), err, tc.err, tc.name)
+	}
+}
@@ -148,4 +148,20 @@ func (s *ConfigurationSuite) Test_Configuration() {
 	config.SetCertificate(testCert)
 	certValue := config.Certificate()
 	assert.Equal(s.T(), testCert, certValue)
+
+	assert.Nil(s.T(), config.CertificateProvider())
+
+	provider := &testCertificateProvider{certificate: certificate}
+	config.SetCertificateProvider(provider)
+	assert.Equal(s.T(), provider, config.CertificateProvider())
+	certValue = config.Certificate()
+	assert.Equal(s.T(), certificate, certValue)
+}
+
+type testCertificateProvider struct {
+	certificate tls.Certificate
+}
+
+func (t *testCertificateProvider) Certificate() tls.Certificate {
+	return t.certificate
 }
@@ -1,6 +1,11 @@
 package api
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/enbility/spine-go/model"
+)
 
 // ErrMetadataNotAvailable indicates that the meta data information is not available
 // e.g. decsriptions, constraints, ...
@@ -23,3 +28,109 @@ var ErrFunctionNotSupported = errors.New("function is not supported")
 var ErrOperationOnFunctionNotSupported = errors.New("operation is not supported on function")
 
 var ErrMissingData = errors.New("missing data")
+
+// ErrConstraintsViolated indicates that data does not meet the constraints
+// announced by the remote entity, e.g. the slot count of a time series
+var ErrConstraintsViolated = errors.New("constraints violated")
+
+// ErrValueTypeMismatch indicates that a value does not match the value type
+// announced in its description
+var ErrValueTypeMismatch = errors.New("value type mismatch")
+
+// ErrCommandRejected indicates that the remote entity rejected a command,
+// e.g. a write with invalid data
+var ErrCommandRejected = errors.New("command rejected")
+
+// ErrTimeout indicates that no response arrived within the maximum response delay
+var ErrTimeout = errors.New("timeout")
+
+// ErrBindingRequired indicates that the remote entity requires a binding for a command
+var ErrBindingRequired = errors.New("binding required")
+
+// SpineOperation is the operation a SpineError occurred for
+type SpineOperation string
+
+const (
+	SpineOperationRead        SpineOperation = "read"
+	SpineOperationWrite       SpineOperation = "write"
+	SpineOperationSubscribe   SpineOperation = "subscribe"
+	SpineOperationUnsubscribe SpineOperation = "unsubscribe"
+	SpineOperationBind        SpineOperation = "bind"
+	SpineOperationUnbind      SpineOperation = "unbind"
+)
+
+// SpineError is returned if a SPINE operation failed, e.g. the remote entity
+// responded with an error result or the request timed out
+//
+// errors.Is reports a match for the sentinel error of the error number,
+// e.g. ErrCommandRejected, ErrNotSupported or ErrTimeout, and for a
+// SpineError with the same error number
+type SpineError struct {
+	ErrorNumber model.ErrorNumberType
+	Description string
+
+	// the address of the remote feature, may be nil
+	Address   *model.FeatureAddressType
+	Operation SpineOperation
+}
+
+// return a SpineError for a SPINE error, nil if there is no error
+func NewSpineError(err *model.ErrorType, operation SpineOperation, address *model.FeatureAddressType) error {
+	if err == nil || err.ErrorNumber == model.ErrorNumberTypeNoError {
+		return nil
+	}
+
+	result := &SpineError{
+		ErrorNumber: err.ErrorNumber,
+		Address:     address,
+		Operation:   operation,
+	}
+	if err.Description != nil {
+		result.Description = string(*err.Description)
+	}
+
+	return result
+}
+
+func (e *SpineError) Error() string {
+	result := fmt.Sprintf("spine error %d", e.ErrorNumber)
+	if e.Operation != "" {
+		result += fmt.Sprintf(" on %s", e.Operation)
+	}
+	if e.Address != nil {
+		result += fmt.Sprintf(" of %s", e.Address)
+	}
+	if e.Description != "" {
+		result += fmt.Sprintf(": %s", e.Description)
+	}
+
+	return result
+}
+
+func (e *SpineError) Is(target error) bool {
+	if t, ok := target.(*SpineError); ok {
+		return t.ErrorNumber == e.ErrorNumber
+	}
+
+	sentinel := e.sentinel()
+	return sentinel != nil && target == sentinel
+}
+
+// return the sentinel error of the error number
+func (e *SpineError) sentinel() error {
+	switch e.ErrorNumber {
+	case model.ErrorNumberTypeTimeout:
+		return ErrTimeout
+	case model.ErrorNumberTypeDestinationUnknown:
+		return ErrEntityNotFound
+	case model.ErrorNumberTypeCommandNotSupported,
+		model.ErrorNumberTypeRestrictedFunctionExchangeCombinationNotSupported:
+		return ErrNotSupported
+	case model.ErrorNumberTypeCommandRejected:
+		return ErrCommandRejected
+	case model.ErrorNumberTypeBindingIsNecessaryForThisCommand:
+		return ErrBindingRequired
+	default:
+		return nil
+	}
+}
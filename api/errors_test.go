@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestErrorsSuite(t *testing.T) {
+	suite.Run(t, new(ErrorsSuite))
+}
+
+type ErrorsSuite struct {
+	suite.Suite
+}
+
+func (s *ErrorsSuite) Test_NewSpineError() {
+	address := &model.FeatureAddressType{
+		Device:  util.Ptr(model.AddressDeviceType("device")),
+		Entity:  []model.AddressEntityType{1},
+		Feature: util.Ptr(model.AddressFeatureType(2)),
+	}
+
+	err := NewSpineError(nil, SpineOperationWrite, address)
+	assert.Nil(s.T(), err)
+
+	err = NewSpineError(model.NewErrorTypeFromNumber(model.ErrorNumberTypeNoError), SpineOperationWrite, address)
+	assert.Nil(s.T(), err)
+
+	err = NewSpineError(model.NewErrorType(model.ErrorNumberTypeCommandRejected, "invalid value"), SpineOperationWrite, address)
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), "spine error 7 on write of device:[1]:2: invalid value", err.Error())
+
+	var spineErr *SpineError
+	assert.True(s.T(), errors.As(err, &spineErr))
+	assert.Equal(s.T(), model.ErrorNumberTypeCommandRejected, spineErr.ErrorNumber)
+	assert.Equal(s.T(), "invalid value", spineErr.Description)
+	assert.Equal(s.T(), address, spineErr.Address)
+	assert.Equal(s.T(), SpineOperationWrite, spineErr.Operation)
+
+	err = NewSpineError(model.NewErrorTypeFromNumber(model.ErrorNumberTypeTimeout), "", nil)
+	assert.Equal(s.T(), "spine error 2", err.Error())
+}
+
+func (s *ErrorsSuite) Test_SpineError_Is() {
+	tests := []struct {
+		errorNumber model.ErrorNumberType
+		sentinel    error
+	}{
+		{model.ErrorNumberTypeGeneralError, nil},
+		{model.ErrorNumberTypeTimeout, ErrTimeout},
+		{model.ErrorNumberTypeOverload, nil},
+		{model.ErrorNumberTypeDestinationUnknown, ErrEntityNotFound},
+		{model.ErrorNumberTypeDestinationUnreachable, nil},
+		{model.ErrorNumberTypeCommandNotSupported, ErrNotSupported},
+		{model.ErrorNumberTypeCommandRejected, ErrCommandRejected},
+		{model.ErrorNumberTypeRestrictedFunctionExchangeCombinationNotSupported, ErrNotSupported},
+		{model.ErrorNumberTypeBindingIsNecessaryForThisCommand, ErrBindingRequired},
+	}
+
+	sentinels := []error{ErrTimeout, ErrEntityNotFound, ErrNotSupported, ErrCommandRejected, ErrBindingRequired}
+
+	for _, tc := range tests {
+		err := NewSpineError(model.NewErrorTypeFromNumber(tc.errorNumber), SpineOperationRead, nil)
+		wrapped := fmt.Errorf("wrapped: %w", err)
+
+		for _, sentinel := range sentinels {
+			assert.Equal(s.T(), sentinel == tc.sentinel, errors.Is(wrapped, sentinel), "%d %s", tc.errorNumber, sentinel)
+		}
+
+		assert.True(s.T(), errors.Is(wrapped, &SpineError{ErrorNumber: tc.errorNumber}))
+		assert.False(s.T(), errors.Is(wrapped, &SpineError{ErrorNumber: model.ErrorNumberTypeNoError}))
+	}
+}
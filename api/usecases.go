@@ -0,0 +1,54 @@
+package api
+
+import (
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+/* UseCases */
+
+// type of the events a use case reports for a remote entity
+type EventType string
+
+// common events reported by all use cases
+const (
+	// A remote entity started or stopped supporting the use case
+	//
+	// Use `IsUseCaseSupported` or `RemoteEntities` to get the current state
+	UseCaseSupportUpdate EventType = "UseCaseSupportUpdate"
+)
+
+// callback function for use case specific events of a remote entity
+//
+// ski is the SKI of the remote device, device and entity the remote
+// device and entity the event is reported for
+type EntityEventCallback func(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event EventType)
+
+// interface for a use case implementation
+//
+// implemented by the use cases in usecases, used by service
+type UseCaseInterface interface {
+	// return the actor of the local use case
+	UseCaseActor() model.UseCaseActorType
+
+	// return the name of the use case
+	UseCaseName() model.UseCaseNameType
+
+	// add the features required by the use case to the local entity
+	AddFeatures()
+
+	// add the use case support to the local entity
+	AddUseCase()
+
+	// set the availability of the use case
+	UpdateUseCaseAvailability(available bool)
+
+	// return if the remote entity supports the use case
+	IsUseCaseSupported(remoteEntity spineapi.EntityRemoteInterface) bool
+
+	// return all remote entities currently supporting the use case
+	RemoteEntities() []spineapi.EntityRemoteInterface
+
+	// handle SPINE events, invoked by service for every SPINE event
+	HandleEvent(payload spineapi.EventPayload)
+}
@@ -1,10 +1,7 @@
 package main
 
 import (
-	"crypto/ecdsa"
 	"crypto/tls"
-	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 	"log"
 	"os"
@@ -16,7 +13,6 @@ import (
 	"github.com/enbility/eebus-go/api"
 	"github.com/enbility/eebus-go/service"
 	shipapi "github.com/enbility/ship-go/api"
-	"github.com/enbility/ship-go/cert"
 	"github.com/enbility/spine-go/model"
 )
 
@@ -27,65 +23,29 @@ type evse struct {
 }
 
 func (h *evse) run() {
-	var err error
-	var certificate tls.Certificate
+	certFile := "eeprom/evse.crt"
+	keyFile := "eeprom/evse.key"
 
 	if len(os.Args) == 5 {
 		remoteSki = os.Args[2]
+		certFile = os.Args[3]
+		keyFile = os.Args[4]
+	}
 
-		certificate, err = tls.LoadX509KeyPair(os.Args[3], os.Args[4])
-		if err != nil {
-			usage()
-			log.Fatal(err)
-		}
-	} else {
-		certificate, err = cert.CreateCertificate("Demo", "Demo", "DE", "Demo-Unit-02")
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		pemdata := pem.EncodeToMemory(&pem.Block{
-			Type:  "CERTIFICATE",
-			Bytes: certificate.Certificate[0],
-		})
-		fmt.Println(string(pemdata))
-
-		//--------------------------- ADDED CODE ---------------------------
-		//write the certificate to a file
-		crtFile, err := os.Create("../../eeprom/evse.crt")
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		defer crtFile.Close()
-		_, err = crtFile.Write(pemdata)
-		if err != nil {
-			log.Fatal(err)
-		}
-		//--------------------------- ADDED CODE ---------------------------
-
-		b, err := x509.MarshalECPrivateKey(certificate.PrivateKey.(*ecdsa.PrivateKey))
-		if err != nil {
-			log.Fatal(err)
-		}
-		pemdata = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b})
-		fmt.Println(string(pemdata))
-
-		//--------------------------- ADDED CODE ---------------------------
-		//write the private key to a file
-		keyFile, err := os.Create("../../eeprom/evse.key")
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		defer keyFile.Close()
-		_, err = keyFile.Write(pemdata)
-		if err != nil {
-			log.Fatal(err)
-		}
-		//--------------------------- ADDED CODE ---------------------------
+	certManager := service.NewCertificateManager(certFile, keyFile, service.CertificateSubject{
+		OrganizationalUnit: "Demo",
+		Organization:       "Demo",
+		Country:            "DE",
+		CommonName:         "Demo-Unit-02",
+	})
+	if err := certManager.LoadOrCreate(); err != nil {
+		usage()
+		log.Fatal(err)
 	}
 
+	fmt.Println("Certificate:", certFile)
+	fmt.Println("Key:", keyFile)
+
 	port, err := strconv.Atoi(os.Args[1])
 	if err != nil {
 		usage()
@@ -96,10 +56,11 @@ func (h *evse) run() {
 		"Demo", "Demo", "EVSE", "234567890",
 		model.DeviceTypeTypeChargingStation,
 		[]model.EntityTypeType{model.EntityTypeTypeEVSE},
-		port, certificate, 230, time.Second*4)
+		port, tls.Certificate{}, 230, time.Second*4)
 	if err != nil {
 		log.Fatal(err)
 	}
+	configuration.SetCertificateProvider(certManager)
 	configuration.SetAlternateIdentifier("Demo-EVSE-234567890")
 
 	h.myService = service.NewService(configuration, h)
@@ -1,10 +1,7 @@
 package main
 
 import (
-	"crypto/ecdsa"
 	"crypto/tls"
-	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 	"log"
 	"os"
@@ -15,8 +12,10 @@ import (
 
 	"github.com/enbility/eebus-go/api"
 	"github.com/enbility/eebus-go/service"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/cem/evsecc"
 	shipapi "github.com/enbility/ship-go/api"
-	"github.com/enbility/ship-go/cert"
+	spineapi "github.com/enbility/spine-go/api"
 	"github.com/enbility/spine-go/model"
 )
 
@@ -27,65 +26,29 @@ type hems struct {
 }
 
 func (h *hems) run() {
-	var err error
-	var certificate tls.Certificate
+	certFile := "eeprom/hems.crt"
+	keyFile := "eeprom/hems.key"
 
 	if len(os.Args) == 5 {
 		remoteSki = os.Args[2]
+		certFile = os.Args[3]
+		keyFile = os.Args[4]
+	}
 
-		certificate, err = tls.LoadX509KeyPair(os.Args[3], os.Args[4])
-		if err != nil {
-			usage()
-			log.Fatal(err)
-		}
-	} else {
-		certificate, err = cert.CreateCertificate("Demo", "Demo", "DE", "Demo-Unit-01")
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		pemdata := pem.EncodeToMemory(&pem.Block{
-			Type:  "CERTIFICATE",
-			Bytes: certificate.Certificate[0],
-		})
-		fmt.Println(string(pemdata))
-
-		//--------------------------- ADDED CODE ---------------------------
-		//write the certificate to a file
-		crtFile, err := os.Create("../../eeprom/hems.crt")
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		defer crtFile.Close()
-		_, err = crtFile.Write(pemdata)
-		if err != nil {
-			log.Fatal(err)
-		}
-		//--------------------------- ADDED CODE ---------------------------
-
-		b, err := x509.MarshalECPrivateKey(certificate.PrivateKey.(*ecdsa.PrivateKey))
-		if err != nil {
-			log.Fatal(err)
-		}
-		pemdata = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b})
-		fmt.Println(string(pemdata))
-
-		//--------------------------- ADDED CODE ---------------------------
-		//write the private key to a file
-		keyFile, err := os.Create("../../eeprom/hems.key")
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		defer keyFile.Close()
-		_, err = keyFile.Write(pemdata)
-		if err != nil {
-			log.Fatal(err)
-		}
-		//--------------------------- ADDED CODE ---------------------------
+	certManager := service.NewCertificateManager(certFile, keyFile, service.CertificateSubject{
+		OrganizationalUnit: "Demo",
+		Organization:       "Demo",
+		Country:            "DE",
+		CommonName:         "Demo-Unit-01",
+	})
+	if err := certManager.LoadOrCreate(); err != nil {
+		usage()
+		log.Fatal(err)
 	}
 
+	fmt.Println("Certificate:", certFile)
+	fmt.Println("Key:", keyFile)
+
 	port, err := strconv.Atoi(os.Args[1])
 	if err != nil {
 		usage()
@@ -96,10 +59,11 @@ func (h *hems) run() {
 		"Demo", "Demo", "HEMS", "123456789",
 		model.DeviceTypeTypeEnergyManagementSystem,
 		[]model.EntityTypeType{model.EntityTypeTypeCEM},
-		port, certificate, 230, time.Second*4)
+		port, tls.Certificate{}, 230, time.Second*4)
 	if err != nil {
 		log.Fatal(err)
 	}
+	configuration.SetCertificateProvider(certManager)
 	configuration.SetAlternateIdentifier("Demo-HEMS-123456789")
 
 	h.myService = service.NewService(configuration, h)
@@ -110,6 +74,9 @@ func (h *hems) run() {
 		return
 	}
 
+	localEntity := h.myService.LocalDevice().EntityForType(model.EntityTypeTypeCEM)
+	h.myService.AddUseCase(evsecc.NewEVSECC(localEntity, nil, h))
+
 	if len(remoteSki) == 0 {
 		os.Exit(0)
 	}
@@ -145,10 +112,17 @@ func (h *hems) AllowWaitingForTrust(ski string) bool {
 	return ski == remoteSki
 }
 
-// UCEvseCommisioningConfigurationCemDelegate
+// EVSECCHandlerInterface
+
+var _ evsecc.EVSECCHandlerInterface = (*hems)(nil)
+
+// handle manufacturer data updates from the remote EVSE device
+func (h *hems) HandleEVSEManufacturerData(ski string, entity spineapi.EntityRemoteInterface, data usecases.ManufacturerData) {
+	fmt.Println("EVSE Manufacturer:", data.BrandName, data.DeviceName, data.SerialNumber)
+}
 
 // handle device state updates from the remote EVSE device
-func (h *hems) HandleEVSEDeviceState(ski string, failure bool, errorCode string) {
+func (h *hems) HandleEVSEDeviceState(ski string, entity spineapi.EntityRemoteInterface, failure bool, errorCode string) {
 	fmt.Println("EVSE Error State:", failure, errorCode)
 }
 
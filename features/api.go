@@ -1,6 +1,8 @@
 package features
 
 import (
+	"context"
+
 	"github.com/enbility/spine-go/api"
 	"github.com/enbility/spine-go/model"
 )
@@ -19,6 +21,18 @@ type FeatureInterface interface {
 	// bind to the feature of the entity
 	Bind() (*model.MsgCounterType, error)
 
+	// check if the remote feature announces partial reads of a function
+	SupportsPartialRead(function model.FunctionType) bool
+
+	// check if the remote feature announces partial writes of a function
+	SupportsPartialWrite(function model.FunctionType) bool
+
+	// wait for the reply to a read request of this feature and return the received data
+	WaitForReply(ctx context.Context, msgCounter *model.MsgCounterType) (any, error)
+
+	// wait for the result of a message sent by this feature, e.g. a write
+	WaitForResult(ctx context.Context, msgCounter *model.MsgCounterType) error
+
 	// add a callback function to be invoked once a result to a msgCounter came in
 	AddResultCallback(msgCounterReference model.MsgCounterType, function func(msg api.ResultMessage))
 }
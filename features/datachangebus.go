@@ -0,0 +1,406 @@
+package features
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/util"
+)
+
+// the identifying values of a data item, e.g. {"measurementId": model.MeasurementIdType(1)}
+//
+// the map keys are the JSON names of the key fields of the item type, unset
+// key fields have a nil value. The key is empty for data without key fields
+type DataItemKey map[string]any
+
+// the change of a single data item of a function
+type DataItemChange[T any] struct {
+	// the identifying values of the item
+	Key DataItemKey
+
+	// the item before the change, nil if the item was added
+	Old *T
+
+	// the item after the change, nil if the item was removed
+	New *T
+}
+
+// the change of the data of a function of a remote feature
+type DataChangeEvent[T any] struct {
+	Ski      string
+	Entity   spineapi.EntityRemoteInterface
+	Feature  spineapi.FeatureRemoteInterface
+	Function model.FunctionType
+
+	// the complete data of the function after the change,
+	// of the list data type of the function, e.g. *model.MeasurementListDataType
+	Data any
+
+	// the changed items
+	//
+	// T is the item type of the list data type, e.g. model.MeasurementDataType.
+	// For functions without list data or with items without key fields, T is
+	// the data type of the function and there is a single change of the whole data
+	Changes []DataItemChange[T]
+}
+
+// defines which data changes are reported to a handler
+//
+// empty fields match everything
+type DataChangeFilter struct {
+	Ski         string
+	Entity      spineapi.EntityRemoteInterface
+	FeatureType model.FeatureTypeType
+	Function    model.FunctionType
+}
+
+// return if an event matches the filter
+func (f DataChangeFilter) matches(event DataChangeEvent[any]) bool {
+	if f.Ski != "" && f.Ski != event.Ski {
+		return false
+	}
+	if f.Entity != nil && f.Entity != event.Entity {
+		return false
+	}
+	if f.FeatureType != "" && f.FeatureType != event.Feature.Type() {
+		return false
+	}
+	if f.Function != "" && f.Function != event.Function {
+		return false
+	}
+
+	return true
+}
+
+type dataChangeHandler struct {
+	filter  DataChangeFilter
+	handler func(DataChangeEvent[any])
+}
+
+// DataChangeBus reports changes of the data of remote server features
+//
+// SPINE applies received data before publishing a data change event, so the
+// bus keeps a copy of the last reported data of every remote function to
+// report the old and new values of the changed items. The first data received
+// for a function is reported as added items.
+//
+// SPINE events have to be passed to HandleEvent, either by subscribing the
+// bus with spine.Events.Subscribe or by forwarding them from an existing
+// event handler
+type DataChangeBus struct {
+	handlers  map[uint64]dataChangeHandler
+	handlerId uint64
+
+	// the last reported data per remote feature and function
+	data map[spineapi.FeatureRemoteInterface]map[model.FunctionType]any
+
+	mux sync.Mutex
+}
+
+var _ spineapi.EventHandlerInterface = (*DataChangeBus)(nil)
+
+// Get a new data change bus
+func NewDataChangeBus() *DataChangeBus {
+	return &DataChangeBus{
+		handlers: make(map[uint64]dataChangeHandler),
+		data:     make(map[spineapi.FeatureRemoteInterface]map[model.FunctionType]any),
+	}
+}
+
+// add a handler for data changes matching the filter
+//
+// the items of the changes are of the item type of the function,
+// e.g. model.MeasurementDataType. Returns a function to remove the handler
+func (b *DataChangeBus) Subscribe(filter DataChangeFilter, handler func(DataChangeEvent[any])) func() {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	id := b.handlerId
+	b.handlerId++
+	b.handlers[id] = dataChangeHandler{filter: filter, handler: handler}
+
+	return func() {
+		b.mux.Lock()
+		defer b.mux.Unlock()
+
+		delete(b.handlers, id)
+	}
+}
+
+// add a handler for data changes matching the filter, where the changed items are of type T
+//
+// T is the item type of the function, e.g. model.MeasurementDataType for
+// model.FunctionTypeMeasurementListData. Changes of other types are not
+// reported to the handler. Returns a function to remove the handler
+func SubscribeDataChanges[T any](bus *DataChangeBus, filter DataChangeFilter, handler func(DataChangeEvent[T])) func() {
+	return bus.Subscribe(filter, func(event DataChangeEvent[any]) {
+		typed := DataChangeEvent[T]{
+			Ski:      event.Ski,
+			Entity:   event.Entity,
+			Feature:  event.Feature,
+			Function: event.Function,
+			Data:     event.Data,
+		}
+
+		for _, change := range event.Changes {
+			item := DataItemChange[T]{Key: change.Key}
+
+			var ok bool
+			if item.Old, ok = typedItem[T](change.Old); !ok {
+				return
+			}
+			if item.New, ok = typedItem[T](change.New); !ok {
+				return
+			}
+
+			typed.Changes = append(typed.Changes, item)
+		}
+
+		handler(typed)
+	})
+}
+
+// return the item as type T, false if it has a different type
+func typedItem[T any](item *any) (*T, bool) {
+	if item == nil {
+		return nil, true
+	}
+
+	value, ok := (*item).(T)
+	if !ok {
+		return nil, false
+	}
+
+	return &value, true
+}
+
+// handle SPINE events to report the data changes of remote features
+func (b *DataChangeBus) HandleEvent(payload spineapi.EventPayload) {
+	switch payload.EventType {
+	case spineapi.EventTypeDataChange:
+		// local feature data written by a remote feature is not reported
+		if payload.Feature == nil || payload.LocalFeature != nil {
+			return
+		}
+
+		b.handleDataChange(payload)
+
+	case spineapi.EventTypeEntityChange:
+		if payload.ChangeType == spineapi.ElementChangeRemove && payload.Entity != nil {
+			b.removeData(func(feature spineapi.FeatureRemoteInterface) bool {
+				return feature.Entity() == payload.Entity
+			})
+		}
+
+	case spineapi.EventTypeDeviceChange:
+		if payload.ChangeType == spineapi.ElementChangeRemove {
+			b.removeData(func(feature spineapi.FeatureRemoteInterface) bool {
+				return feature.Device() != nil && feature.Device().Ski() == payload.Ski
+			})
+		}
+	}
+}
+
+// report the changes of the function data of a data change event
+func (b *DataChangeBus) handleDataChange(payload spineapi.EventPayload) {
+	function, ok := functionOfData(payload.Data)
+	if !ok {
+		return
+	}
+
+	current := payload.Feature.DataCopy(function)
+	if current == nil || reflect.ValueOf(current).IsNil() {
+		return
+	}
+
+	// the copy of spine shares slices with the feature data
+	data := reflect.New(reflect.TypeOf(current).Elem()).Interface()
+	util.DeepCopy(current, data)
+
+	b.mux.Lock()
+	functions, ok := b.data[payload.Feature]
+	if !ok {
+		functions = make(map[model.FunctionType]any)
+		b.data[payload.Feature] = functions
+	}
+	old := functions[function]
+	functions[function] = data
+
+	var handlers []dataChangeHandler
+	for _, handler := range b.handlers {
+		handlers = append(handlers, handler)
+	}
+	b.mux.Unlock()
+
+	changes := dataChanges(old, data)
+	if len(changes) == 0 {
+		return
+	}
+
+	event := DataChangeEvent[any]{
+		Ski:      payload.Ski,
+		Entity:   payload.Feature.Entity(),
+		Feature:  payload.Feature,
+		Function: function,
+		Data:     data,
+		Changes:  changes,
+	}
+
+	for _, handler := range handlers {
+		if handler.filter.matches(event) {
+			handler.handler(event)
+		}
+	}
+}
+
+// remove the data of all remote features matching remove
+func (b *DataChangeBus) removeData(remove func(feature spineapi.FeatureRemoteInterface) bool) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+
+	for feature := range b.data {
+		if remove(feature) {
+			delete(b.data, feature)
+		}
+	}
+}
+
+var (
+	functionTypes     map[reflect.Type]model.FunctionType
+	functionTypesOnce sync.Once
+)
+
+// return the function of the data of a command, e.g.
+// model.FunctionTypeMeasurementListData for *model.MeasurementListDataType
+func functionOfData(data any) (model.FunctionType, bool) {
+	functionTypesOnce.Do(func() {
+		functionTypes = make(map[reflect.Type]model.FunctionType)
+
+		cmdType := reflect.TypeOf(model.CmdType{})
+		for i := 0; i < cmdType.NumField(); i++ {
+			field := cmdType.Field(i)
+			if function, ok := strings.CutPrefix(field.Tag.Get("eebus"), "fct:"); ok && function != "" {
+				functionTypes[field.Type] = model.FunctionType(function)
+			}
+		}
+	})
+
+	if data == nil {
+		return "", false
+	}
+
+	function, ok := functionTypes[reflect.TypeOf(data)]
+
+	return function, ok
+}
+
+type dataItem struct {
+	key   DataItemKey
+	id    string
+	value any
+}
+
+// return the changed items between the old and new data of a function
+//
+// oldData is nil if no data was reported before
+func dataChanges(oldData, newData any) []DataItemChange[any] {
+	oldItems := dataItems(oldData)
+	newItems := dataItems(newData)
+
+	var changes []DataItemChange[any]
+	for _, item := range newItems {
+		newValue := item.value
+		index := slices.IndexFunc(oldItems, func(o dataItem) bool { return o.id == item.id })
+		if index < 0 {
+			changes = append(changes, DataItemChange[any]{Key: item.key, New: &newValue})
+			continue
+		}
+
+		oldValue := oldItems[index].value
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, DataItemChange[any]{Key: item.key, Old: &oldValue, New: &newValue})
+		}
+	}
+
+	for _, item := range oldItems {
+		oldValue := item.value
+		if !slices.ContainsFunc(newItems, func(n dataItem) bool { return n.id == item.id }) {
+			changes = append(changes, DataItemChange[any]{Key: item.key, Old: &oldValue})
+		}
+	}
+
+	return changes
+}
+
+// return the items of the data of a function
+//
+// the items of list data are identified by their key fields, all other data
+// is returned as a single item with an empty key
+func dataItems(data any) []dataItem {
+	if data == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(data)
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if list, keyFields, ok := listItems(value); ok {
+		result := make([]dataItem, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			item := list.Index(i)
+
+			key := make(DataItemKey)
+			for _, field := range keyFields {
+				fieldValue := item.FieldByIndex(field.Index)
+				name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if fieldValue.Kind() == reflect.Pointer {
+					if fieldValue.IsNil() {
+						key[name] = nil
+						continue
+					}
+					fieldValue = fieldValue.Elem()
+				}
+				key[name] = fieldValue.Interface()
+			}
+
+			result = append(result, dataItem{key: key, id: fmt.Sprint(key), value: item.Interface()})
+		}
+
+		return result
+	}
+
+	return []dataItem{{key: DataItemKey{}, value: value.Interface()}}
+}
+
+// return the item list of list data and the key fields of its items,
+// false if the data has no list of items with key fields
+func listItems(value reflect.Value) (reflect.Value, []reflect.StructField, bool) {
+	if value.Kind() != reflect.Struct || value.NumField() != 1 {
+		return reflect.Value{}, nil, false
+	}
+
+	list := value.Field(0)
+	if list.Kind() != reflect.Slice || list.Type().Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, nil, false
+	}
+
+	itemType := list.Type().Elem()
+	var keyFields []reflect.StructField
+	for i := 0; i < itemType.NumField(); i++ {
+		if itemType.Field(i).Tag.Get("eebus") == "key" {
+			keyFields = append(keyFields, itemType.Field(i))
+		}
+	}
+
+	return list, keyFields, len(keyFields) > 0
+}
@@ -0,0 +1,192 @@
+package features_test
+
+import (
+	"testing"
+
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/util"
+	shipapi "github.com/enbility/ship-go/api"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDataChangeBusSuite(t *testing.T) {
+	suite.Run(t, new(DataChangeBusSuite))
+}
+
+type DataChangeBusSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	sut *features.DataChangeBus
+}
+
+var _ shipapi.ShipConnectionDataWriterInterface = (*DataChangeBusSuite)(nil)
+
+func (s *DataChangeBusSuite) WriteShipMessageWithPayload([]byte) {}
+
+func (s *DataChangeBusSuite) BeforeTest(suiteName, testName string) {
+	s.localEntity, s.remoteEntity = setupFeatures(
+		s.T(),
+		s,
+		[]featureFunctions{
+			{
+				featureType: model.FeatureTypeTypeMeasurement,
+				functions: []model.FunctionType{
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				featureType: model.FeatureTypeTypeDeviceDiagnosis,
+				functions: []model.FunctionType{
+					model.FunctionTypeDeviceDiagnosisStateData,
+				},
+			},
+		},
+	)
+
+	s.sut = features.NewDataChangeBus()
+	_ = spine.Events.Subscribe(s.sut)
+}
+
+func (s *DataChangeBusSuite) AfterTest(suiteName, testName string) {
+	_ = spine.Events.Unsubscribe(s.sut)
+}
+
+func (s *DataChangeBusSuite) notify(featureType model.FeatureTypeType, cmd model.CmdType) {
+	receiveCmd(s.T(), s.localEntity, s.remoteEntity, featureType,
+		model.CmdClassifierTypeNotify, util.Ptr(model.MsgCounterType(1)), cmd)
+}
+
+func (s *DataChangeBusSuite) measurements(partial bool, values ...model.MeasurementDataType) model.CmdType {
+	cmd := model.CmdType{
+		MeasurementListData: &model.MeasurementListDataType{
+			MeasurementData: values,
+		},
+	}
+	if partial {
+		cmd.Function = util.Ptr(model.FunctionTypeMeasurementListData)
+		cmd.Filter = []model.FilterType{{CmdControl: &model.CmdControlType{Partial: &model.ElementTagType{}}}}
+	}
+
+	return cmd
+}
+
+func measurementValue(id uint, value float64) model.MeasurementDataType {
+	return model.MeasurementDataType{
+		MeasurementId: util.Ptr(model.MeasurementIdType(id)),
+		ValueType:     util.Ptr(model.MeasurementValueTypeTypeValue),
+		Value:         model.NewScaledNumberType(value),
+	}
+}
+
+func (s *DataChangeBusSuite) Test_Measurement() {
+	var events []features.DataChangeEvent[model.MeasurementDataType]
+	unsubscribe := features.SubscribeDataChanges(s.sut, features.DataChangeFilter{
+		Ski:         "test",
+		Entity:      s.remoteEntity,
+		FeatureType: model.FeatureTypeTypeMeasurement,
+		Function:    model.FunctionTypeMeasurementListData,
+	}, func(event features.DataChangeEvent[model.MeasurementDataType]) {
+		events = append(events, event)
+	})
+
+	var otherEvents []features.DataChangeEvent[any]
+	s.sut.Subscribe(features.DataChangeFilter{Ski: "other"}, func(event features.DataChangeEvent[any]) {
+		otherEvents = append(otherEvents, event)
+	})
+
+	s.notify(model.FeatureTypeTypeMeasurement, s.measurements(false, measurementValue(0, 1), measurementValue(1, 2)))
+	assert.Equal(s.T(), 1, len(events))
+	event := events[0]
+	assert.Equal(s.T(), "test", event.Ski)
+	assert.Equal(s.T(), s.remoteEntity, event.Entity)
+	assert.Equal(s.T(), model.FeatureTypeTypeMeasurement, event.Feature.Type())
+	assert.Equal(s.T(), model.FunctionTypeMeasurementListData, event.Function)
+	assert.Equal(s.T(), 2, len(event.Data.(*model.MeasurementListDataType).MeasurementData))
+	assert.Equal(s.T(), 2, len(event.Changes))
+	assert.Equal(s.T(), features.DataItemKey{
+		"measurementId": model.MeasurementIdType(0),
+		"valueType":     model.MeasurementValueTypeTypeValue,
+	}, event.Changes[0].Key)
+	assert.Nil(s.T(), event.Changes[0].Old)
+	assert.Equal(s.T(), 1.0, event.Changes[0].New.Value.GetValue())
+	assert.Equal(s.T(), 2.0, event.Changes[1].New.Value.GetValue())
+
+	// a partial update of one measurement
+	s.notify(model.FeatureTypeTypeMeasurement, s.measurements(true, measurementValue(1, 3)))
+	assert.Equal(s.T(), 2, len(events))
+	event = events[1]
+	assert.Equal(s.T(), 2, len(event.Data.(*model.MeasurementListDataType).MeasurementData))
+	assert.Equal(s.T(), 1, len(event.Changes))
+	assert.Equal(s.T(), model.MeasurementIdType(1), event.Changes[0].Key["measurementId"])
+	assert.Equal(s.T(), 2.0, event.Changes[0].Old.Value.GetValue())
+	assert.Equal(s.T(), 3.0, event.Changes[0].New.Value.GetValue())
+
+	// unchanged data is not reported
+	s.notify(model.FeatureTypeTypeMeasurement, s.measurements(true, measurementValue(1, 3)))
+	assert.Equal(s.T(), 2, len(events))
+
+	// a full update without a measurement
+	s.notify(model.FeatureTypeTypeMeasurement, s.measurements(false, measurementValue(1, 3)))
+	assert.Equal(s.T(), 3, len(events))
+	event = events[2]
+	assert.Equal(s.T(), 1, len(event.Changes))
+	assert.Equal(s.T(), model.MeasurementIdType(0), event.Changes[0].Key["measurementId"])
+	assert.Equal(s.T(), 1.0, event.Changes[0].Old.Value.GetValue())
+	assert.Nil(s.T(), event.Changes[0].New)
+
+	// the data of a removed device is forgotten
+	s.sut.HandleEvent(spineapi.EventPayload{
+		Ski:        "test",
+		EventType:  spineapi.EventTypeDeviceChange,
+		ChangeType: spineapi.ElementChangeRemove,
+	})
+	s.notify(model.FeatureTypeTypeMeasurement, s.measurements(false, measurementValue(1, 3)))
+	assert.Equal(s.T(), 4, len(events))
+	assert.Nil(s.T(), events[3].Changes[0].Old)
+
+	unsubscribe()
+	s.notify(model.FeatureTypeTypeMeasurement, s.measurements(false, measurementValue(1, 4)))
+	assert.Equal(s.T(), 4, len(events))
+
+	assert.Equal(s.T(), 0, len(otherEvents))
+}
+
+func (s *DataChangeBusSuite) Test_DeviceDiagnosis() {
+	var measurementEvents []features.DataChangeEvent[model.MeasurementDataType]
+	features.SubscribeDataChanges(s.sut, features.DataChangeFilter{},
+		func(event features.DataChangeEvent[model.MeasurementDataType]) {
+			measurementEvents = append(measurementEvents, event)
+		})
+
+	var events []features.DataChangeEvent[model.DeviceDiagnosisStateDataType]
+	features.SubscribeDataChanges(s.sut, features.DataChangeFilter{
+		Function: model.FunctionTypeDeviceDiagnosisStateData,
+	}, func(event features.DataChangeEvent[model.DeviceDiagnosisStateDataType]) {
+		events = append(events, event)
+	})
+
+	s.notify(model.FeatureTypeTypeDeviceDiagnosis, model.CmdType{
+		DeviceDiagnosisStateData: &model.DeviceDiagnosisStateDataType{
+			OperatingState: util.Ptr(model.DeviceDiagnosisOperatingStateTypeNormalOperation),
+		},
+	})
+	s.notify(model.FeatureTypeTypeDeviceDiagnosis, model.CmdType{
+		DeviceDiagnosisStateData: &model.DeviceDiagnosisStateDataType{
+			OperatingState: util.Ptr(model.DeviceDiagnosisOperatingStateTypeFailure),
+		},
+	})
+
+	assert.Equal(s.T(), 0, len(measurementEvents))
+	assert.Equal(s.T(), 2, len(events))
+	assert.Equal(s.T(), 1, len(events[1].Changes))
+	assert.Equal(s.T(), features.DataItemKey{}, events[1].Changes[0].Key)
+	assert.Equal(s.T(), model.DeviceDiagnosisOperatingStateTypeNormalOperation, *events[1].Changes[0].Old.OperatingState)
+	assert.Equal(s.T(), model.DeviceDiagnosisOperatingStateTypeFailure, *events[1].Changes[0].New.OperatingState)
+}
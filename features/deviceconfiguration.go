@@ -1,6 +1,8 @@
 package features
 
 import (
+	"time"
+
 	"github.com/enbility/eebus-go/api"
 	spineapi "github.com/enbility/spine-go/api"
 	"github.com/enbility/spine-go/model"
@@ -40,6 +42,25 @@ func (d *DeviceConfiguration) RequestKeyValues() (*model.MsgCounterType, error)
 	return d.requestData(model.FunctionTypeDeviceConfigurationKeyValueListData, nil, nil)
 }
 
+// request the DeviceConfigurationKeyValueListDataType of a key from a remote entity
+//
+// if the remote entity does not announce partial reads, all key values are requested
+func (d *DeviceConfiguration) RequestKeyValueForKeyId(keyId model.DeviceConfigurationKeyIdType) (*model.MsgCounterType, error) {
+	function := model.FunctionTypeDeviceConfigurationKeyValueListData
+	if !d.SupportsPartialRead(function) {
+		return d.requestData(function, nil, nil)
+	}
+
+	filter := model.NewFilterTypePartial()
+	filter.DeviceConfigurationKeyValueListDataSelectors = &model.DeviceConfigurationKeyValueListDataSelectorsType{
+		KeyId: &keyId,
+	}
+	cmd := model.CmdType{
+		DeviceConfigurationKeyValueListData: &model.DeviceConfigurationKeyValueListDataType{},
+	}
+	return d.requestDataWithFilter(function, filter, cmd)
+}
+
 // return current descriptions for Device Configuration
 func (d *DeviceConfiguration) GetDescriptions() ([]model.DeviceConfigurationKeyValueDescriptionDataType, error) {
 	data, err := spine.RemoteFeatureDataCopyOfType[*model.DeviceConfigurationKeyValueDescriptionListDataType](d.featureRemote, model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData)
@@ -96,6 +117,8 @@ func (d *DeviceConfiguration) GetKeyValues() ([]model.DeviceConfigurationKeyValu
 }
 
 // return a pointer value for a given key and value type
+//
+// Deprecated: use the typed getters, e.g. GetKeyValueScaledNumberForKeyName
 func (d *DeviceConfiguration) GetKeyValueForKeyName(keyname model.DeviceConfigurationKeyNameType, valueType model.DeviceConfigurationKeyValueTypeType) (any, error) {
 	values, err := d.GetKeyValues()
 	if err != nil {
@@ -136,3 +159,326 @@ func (d *DeviceConfiguration) GetKeyValueForKeyName(keyname model.DeviceConfigur
 
 	return nil, api.ErrDataNotAvailable
 }
+
+// return the boolean value of a key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is not described as a boolean
+//   - ErrDataNotAvailable if no value for the key is available
+func (d *DeviceConfiguration) GetKeyValueBooleanForKeyName(keyName model.DeviceConfigurationKeyNameType) (bool, error) {
+	value, err := d.keyValueOfType(keyName, model.DeviceConfigurationKeyValueTypeTypeBoolean)
+	if err != nil {
+		return false, err
+	}
+
+	return *value.Boolean, nil
+}
+
+// return the scaled number value of a key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is not described as a scaled number
+//   - ErrDataNotAvailable if no value for the key is available
+func (d *DeviceConfiguration) GetKeyValueScaledNumberForKeyName(keyName model.DeviceConfigurationKeyNameType) (float64, error) {
+	value, err := d.keyValueOfType(keyName, model.DeviceConfigurationKeyValueTypeTypeScaledNumber)
+	if err != nil {
+		return 0, err
+	}
+
+	return value.ScaledNumber.GetValue(), nil
+}
+
+// return the integer value of a key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is not described as an integer
+//   - ErrDataNotAvailable if no value for the key is available
+func (d *DeviceConfiguration) GetKeyValueIntegerForKeyName(keyName model.DeviceConfigurationKeyNameType) (int64, error) {
+	value, err := d.keyValueOfType(keyName, model.DeviceConfigurationKeyValueTypeTypeInteger)
+	if err != nil {
+		return 0, err
+	}
+
+	return *value.Integer, nil
+}
+
+// return the string value of a key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is not described as a string
+//   - ErrDataNotAvailable if no value for the key is available
+func (d *DeviceConfiguration) GetKeyValueStringForKeyName(keyName model.DeviceConfigurationKeyNameType) (string, error) {
+	value, err := d.keyValueOfType(keyName, model.DeviceConfigurationKeyValueTypeTypeString)
+	if err != nil {
+		return "", err
+	}
+
+	return string(*value.String), nil
+}
+
+// return the duration value of a key, converted from ISO 8601
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is not described as a duration
+//   - ErrDataNotAvailable if no value for the key is available
+//   - an error if the value is no valid ISO 8601 duration
+func (d *DeviceConfiguration) GetKeyValueDurationForKeyName(keyName model.DeviceConfigurationKeyNameType) (time.Duration, error) {
+	value, err := d.keyValueOfType(keyName, model.DeviceConfigurationKeyValueTypeTypeDuration)
+	if err != nil {
+		return 0, err
+	}
+
+	return value.Duration.GetTimeDuration()
+}
+
+// return the date time value of a key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is not described as a date time
+//   - ErrDataNotAvailable if no value for the key is available
+//   - an error if the value is no valid date time
+func (d *DeviceConfiguration) GetKeyValueDateTimeForKeyName(keyName model.DeviceConfigurationKeyNameType) (time.Time, error) {
+	value, err := d.keyValueOfType(keyName, model.DeviceConfigurationKeyValueTypeTypeDateTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return value.DateTime.GetTime()
+}
+
+// return the date value of a key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is not described as a date
+//   - ErrDataNotAvailable if no value for the key is available
+//   - an error if the value is no valid date
+func (d *DeviceConfiguration) GetKeyValueDateForKeyName(keyName model.DeviceConfigurationKeyNameType) (time.Time, error) {
+	value, err := d.keyValueOfType(keyName, model.DeviceConfigurationKeyValueTypeTypeDate)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return value.Date.GetTime()
+}
+
+// return the time value of a key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is not described as a time
+//   - ErrDataNotAvailable if no value for the key is available
+//   - an error if the value is no valid time
+func (d *DeviceConfiguration) GetKeyValueTimeForKeyName(keyName model.DeviceConfigurationKeyNameType) (time.Time, error) {
+	value, err := d.keyValueOfType(keyName, model.DeviceConfigurationKeyValueTypeTypeTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return value.Time.GetTime()
+}
+
+// return the value of a key, which has to be described with the value type
+//
+// the field of the value type is always set in the returned value
+func (d *DeviceConfiguration) keyValueOfType(
+	keyName model.DeviceConfigurationKeyNameType,
+	valueType model.DeviceConfigurationKeyValueTypeType) (*model.DeviceConfigurationKeyValueValueType, error) {
+	description, err := d.GetDescriptionForKeyName(keyName)
+	if err != nil {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	if description.ValueType == nil || *description.ValueType != valueType {
+		return nil, api.ErrValueTypeMismatch
+	}
+
+	values, err := d.GetKeyValues()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range values {
+		if item.KeyId == nil || *item.KeyId != *description.KeyId {
+			continue
+		}
+
+		if item.Value == nil {
+			return nil, api.ErrDataNotAvailable
+		}
+
+		if itemType, ok := keyValueValueType(*item.Value); !ok || itemType != valueType {
+			return nil, api.ErrValueTypeMismatch
+		}
+
+		return item.Value, nil
+	}
+
+	return nil, api.ErrDataNotAvailable
+}
+
+// write key values partially, the other key values of the remote entity are kept
+//
+// if the remote entity does not announce partial writes, the key values are
+// merged into the current key values and the full list is written
+//
+// possible errors:
+//   - ErrMissingData if no key values are provided or a key value has no key id
+//   - ErrFunctionNotSupported if the remote entity does not support the key value list data
+//   - ErrOperationOnFunctionNotSupported if the key value list data is not writeable
+//   - and others
+func (d *DeviceConfiguration) WriteKeyValuesPartial(data []model.DeviceConfigurationKeyValueDataType) (*model.MsgCounterType, error) {
+	if len(data) == 0 {
+		return nil, api.ErrMissingData
+	}
+
+	for _, item := range data {
+		if item.KeyId == nil {
+			return nil, api.ErrMissingData
+		}
+	}
+
+	return d.writeKeyValuesPartial(data, nil)
+}
+
+// return if the value of a key may be written
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrDataNotAvailable if no value for the key is available
+func (d *DeviceConfiguration) IsChangeable(keyName model.DeviceConfigurationKeyNameType) (bool, error) {
+	description, err := d.GetDescriptionForKeyName(keyName)
+	if err != nil {
+		return false, api.ErrMetadataNotAvailable
+	}
+
+	values, err := d.GetKeyValues()
+	if err != nil {
+		return false, err
+	}
+
+	for _, item := range values {
+		if item.KeyId != nil && *item.KeyId == *description.KeyId {
+			return item.IsValueChangeable != nil && *item.IsValueChangeable, nil
+		}
+	}
+
+	return false, api.ErrDataNotAvailable
+}
+
+// write the value of a key partially, the other key values of the remote entity are kept
+//
+// if the remote entity does not announce partial writes, the value is
+// merged into the current key values and the full list is written
+//
+// the value has to match the value type of the key description. If the
+// remote entity announced the current value as not changeable, it is not written
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the value does not match the value type of the description
+//   - ErrNotSupported if the value is not changeable
+//   - ErrFunctionNotSupported if the remote entity does not support the key value list data
+//   - ErrOperationOnFunctionNotSupported if the key value list data is not writeable
+//   - and others
+func (d *DeviceConfiguration) WriteKeyValueForKeyName(
+	keyName model.DeviceConfigurationKeyNameType,
+	value model.DeviceConfigurationKeyValueValueType) (*model.MsgCounterType, error) {
+	description, err := d.GetDescriptionForKeyName(keyName)
+	if err != nil {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	if valueType, ok := keyValueValueType(value); !ok ||
+		description.ValueType == nil || valueType != *description.ValueType {
+		return nil, api.ErrValueTypeMismatch
+	}
+
+	// an unknown changeability is decided by the remote entity
+	if changeable, err := d.IsChangeable(keyName); err == nil && !changeable {
+		return nil, api.ErrNotSupported
+	}
+
+	data := []model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: description.KeyId,
+			Value: &value,
+		},
+	}
+	selector := &model.DeviceConfigurationKeyValueListDataSelectorsType{
+		KeyId: description.KeyId,
+	}
+
+	return d.writeKeyValuesPartial(data, selector)
+}
+
+// write key values with a partial filter and an optional selector
+// or merged into the current key values, if partial writes are not supported
+func (d *DeviceConfiguration) writeKeyValuesPartial(
+	data []model.DeviceConfigurationKeyValueDataType,
+	selector *model.DeviceConfigurationKeyValueListDataSelectorsType) (*model.MsgCounterType, error) {
+	function := model.FunctionTypeDeviceConfigurationKeyValueListData
+
+	var filter *model.FilterType
+	if d.SupportsPartialWrite(function) {
+		filter = model.NewFilterTypePartial()
+		filter.DeviceConfigurationKeyValueListDataSelectors = selector
+	} else {
+		current, _ := d.GetKeyValues()
+		data = mergeItems(current, data, func(a, b model.DeviceConfigurationKeyValueDataType) bool {
+			return equalPtr(a.KeyId, b.KeyId)
+		})
+	}
+
+	cmd := model.CmdType{
+		DeviceConfigurationKeyValueListData: &model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: data,
+		},
+	}
+
+	return d.writeData(function, filter, cmd)
+}
+
+// return the value type of a key value
+//
+// returns false if no value or multiple values are set
+func keyValueValueType(value model.DeviceConfigurationKeyValueValueType) (model.DeviceConfigurationKeyValueTypeType, bool) {
+	var result []model.DeviceConfigurationKeyValueTypeType
+
+	if value.Boolean != nil {
+		result = append(result, model.DeviceConfigurationKeyValueTypeTypeBoolean)
+	}
+	if value.Date != nil {
+		result = append(result, model.DeviceConfigurationKeyValueTypeTypeDate)
+	}
+	if value.DateTime != nil {
+		result = append(result, model.DeviceConfigurationKeyValueTypeTypeDateTime)
+	}
+	if value.Duration != nil {
+		result = append(result, model.DeviceConfigurationKeyValueTypeTypeDuration)
+	}
+	if value.String != nil {
+		result = append(result, model.DeviceConfigurationKeyValueTypeTypeString)
+	}
+	if value.Time != nil {
+		result = append(result, model.DeviceConfigurationKeyValueTypeTypeTime)
+	}
+	if value.ScaledNumber != nil {
+		result = append(result, model.DeviceConfigurationKeyValueTypeTypeScaledNumber)
+	}
+	if value.Integer != nil {
+		result = append(result, model.DeviceConfigurationKeyValueTypeTypeInteger)
+	}
+
+	if len(result) != 1 {
+		return "", false
+	}
+
+	return result[0], true
+}
@@ -4,13 +4,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/enbility/eebus-go/api"
 	"github.com/enbility/eebus-go/features"
 	"github.com/enbility/eebus-go/util"
-	shipmocks "github.com/enbility/ship-go/mocks"
 	spineapi "github.com/enbility/spine-go/api"
 	"github.com/enbility/spine-go/model"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -25,15 +24,15 @@ type DeviceConfigurationSuite struct {
 	remoteEntity spineapi.EntityRemoteInterface
 
 	deviceConfiguration *features.DeviceConfiguration
+	writeHandler        *WriteMessageHandler
 }
 
 func (s *DeviceConfigurationSuite) BeforeTest(suiteName, testName string) {
-	mockWriter := shipmocks.NewShipConnectionDataWriterInterface(s.T())
-	mockWriter.EXPECT().WriteShipMessageWithPayload(mock.Anything).Return().Maybe()
+	s.writeHandler = &WriteMessageHandler{}
 
 	s.localEntity, s.remoteEntity = setupFeatures(
 		s.T(),
-		mockWriter,
+		s.writeHandler,
 		[]featureFunctions{
 			{
 				featureType: model.FeatureTypeTypeDeviceConfiguration,
@@ -63,6 +62,31 @@ func (s *DeviceConfigurationSuite) Test_RequestKeyValueList() {
 	assert.NotNil(s.T(), counter)
 }
 
+func (s *DeviceConfigurationSuite) Test_RequestKeyValueForKeyId() {
+	keyId := model.DeviceConfigurationKeyIdType(2)
+
+	counter, err := s.deviceConfiguration.RequestKeyValueForKeyId(keyId)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+
+	cmd := sentCmd(s.T(), s.writeHandler, counter)
+	assert.Equal(s.T(), 1, len(cmd.Filter))
+	assert.NotNil(s.T(), cmd.Filter[0].CmdControl.Partial)
+	assert.Equal(s.T(), keyId, *cmd.Filter[0].DeviceConfigurationKeyValueListDataSelectors.KeyId)
+
+	// partial reads are not announced, so all key values are requested
+	nonPartial, err := features.NewDeviceConfiguration(s.localEntity, nonPartialRemoteEntity(s.remoteEntity))
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), nonPartial.SupportsPartialRead(model.FunctionTypeDeviceConfigurationKeyValueListData))
+
+	counter, err = nonPartial.RequestKeyValueForKeyId(keyId)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+
+	cmd = sentCmd(s.T(), s.writeHandler, counter)
+	assert.Equal(s.T(), 0, len(cmd.Filter))
+}
+
 func (s *DeviceConfigurationSuite) Test_GetDescriptionForKeyId() {
 	keyId := model.DeviceConfigurationKeyIdType(0)
 	desc, err := s.deviceConfiguration.GetDescriptionForKeyId(keyId)
@@ -141,6 +165,72 @@ func (s *DeviceConfigurationSuite) Test_GetValueForKey() {
 	assert.Nil(s.T(), value)
 }
 
+func (s *DeviceConfigurationSuite) Test_GetKeyValueTyped() {
+	stringKey := model.DeviceConfigurationKeyNameTypeCommunicationsStandard
+	booleanKey := model.DeviceConfigurationKeyNameTypeAsymmetricChargingSupported
+	scaledNumberKey := model.DeviceConfigurationKeyNameTypePvCurtailmentLimitFactor
+	dateTimeKey := model.DeviceConfigurationKeyNameTypeBatteryType
+	durationKey := model.DeviceConfigurationKeyNameTypeTimeToAcDischargePowerMax
+	timeKey := model.DeviceConfigurationKeyNameTypeIncentivesWaitIncentiveWriteable
+
+	_, err := s.deviceConfiguration.GetKeyValueScaledNumberForKeyName(scaledNumberKey)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.addDescription()
+
+	_, err = s.deviceConfiguration.GetKeyValueScaledNumberForKeyName(scaledNumberKey)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+	_, err = s.deviceConfiguration.GetKeyValueBooleanForKeyName(scaledNumberKey)
+	assert.Equal(s.T(), api.ErrValueTypeMismatch, err)
+	_, err = s.deviceConfiguration.GetKeyValueIntegerForKeyName(scaledNumberKey)
+	assert.Equal(s.T(), api.ErrValueTypeMismatch, err)
+
+	s.addData()
+
+	scaledNumber, err := s.deviceConfiguration.GetKeyValueScaledNumberForKeyName(scaledNumberKey)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 50.0, scaledNumber)
+
+	stringValue, err := s.deviceConfiguration.GetKeyValueStringForKeyName(stringKey)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "test", stringValue)
+
+	boolean, err := s.deviceConfiguration.GetKeyValueBooleanForKeyName(booleanKey)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), boolean)
+
+	dateTime, err := s.deviceConfiguration.GetKeyValueDateTimeForKeyName(dateTimeKey)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), dateTime.IsZero())
+
+	duration, err := s.deviceConfiguration.GetKeyValueDurationForKeyName(durationKey)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Second*4, duration)
+
+	// the time value is no valid SPINE time
+	_, err = s.deviceConfiguration.GetKeyValueTimeForKeyName(timeKey)
+	assert.NotNil(s.T(), err)
+
+	// a value with a different type than the description
+	rF := s.remoteEntity.FeatureOfAddress(util.Ptr(model.AddressFeatureType(1)))
+	fData := &model.DeviceConfigurationKeyValueListDataType{
+		DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+			{
+				KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(2)),
+				Value: &model.DeviceConfigurationKeyValueValueType{
+					Boolean: util.Ptr(true),
+				},
+			},
+		},
+	}
+	rF.UpdateData(model.FunctionTypeDeviceConfigurationKeyValueListData, fData, nil, nil)
+
+	_, err = s.deviceConfiguration.GetKeyValueScaledNumberForKeyName(scaledNumberKey)
+	assert.Equal(s.T(), api.ErrValueTypeMismatch, err)
+	_, err = s.deviceConfiguration.GetKeyValueStringForKeyName(stringKey)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+}
+
 func (s *DeviceConfigurationSuite) Test_GetValues() {
 	data, err := s.deviceConfiguration.GetKeyValues()
 	assert.NotNil(s.T(), err)
@@ -159,8 +249,180 @@ func (s *DeviceConfigurationSuite) Test_GetValues() {
 	assert.NotNil(s.T(), data)
 }
 
+func (s *DeviceConfigurationSuite) Test_WriteKeyValuesPartial() {
+	counter, err := s.deviceConfiguration.WriteKeyValuesPartial(nil)
+	assert.Equal(s.T(), api.ErrMissingData, err)
+	assert.Nil(s.T(), counter)
+
+	data := []model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(2)),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				ScaledNumber: model.NewScaledNumberType(40),
+			},
+		},
+	}
+	counter, err = s.deviceConfiguration.WriteKeyValuesPartial([]model.DeviceConfigurationKeyValueDataType{{}})
+	assert.Equal(s.T(), api.ErrMissingData, err)
+	assert.Nil(s.T(), counter)
+
+	s.addData()
+
+	// only the provided key values are written
+	counter, err = s.deviceConfiguration.WriteKeyValuesPartial(data)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+
+	cmd := sentCmd(s.T(), s.writeHandler, counter)
+	assert.Equal(s.T(), 1, len(cmd.Filter))
+	assert.NotNil(s.T(), cmd.Filter[0].CmdControl.Partial)
+	assert.Nil(s.T(), cmd.Filter[0].DeviceConfigurationKeyValueListDataSelectors)
+	keyValues := cmd.DeviceConfigurationKeyValueListData.DeviceConfigurationKeyValueData
+	assert.Equal(s.T(), 1, len(keyValues))
+	assert.Equal(s.T(), 40.0, keyValues[0].Value.ScaledNumber.GetValue())
+
+	// partial writes are not announced, so the full list is written
+	nonPartial, err := features.NewDeviceConfiguration(s.localEntity, nonPartialRemoteEntity(s.remoteEntity))
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), nonPartial.SupportsPartialWrite(model.FunctionTypeDeviceConfigurationKeyValueListData))
+
+	counter, err = nonPartial.WriteKeyValuesPartial(data)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+
+	cmd = sentCmd(s.T(), s.writeHandler, counter)
+	assert.Equal(s.T(), 0, len(cmd.Filter))
+	keyValues = cmd.DeviceConfigurationKeyValueListData.DeviceConfigurationKeyValueData
+	assert.Equal(s.T(), 7, len(keyValues))
+	assert.Equal(s.T(), 40.0, keyValues[2].Value.ScaledNumber.GetValue())
+}
+
+func (s *DeviceConfigurationSuite) Test_IsChangeable() {
+	keyName := model.DeviceConfigurationKeyNameTypePvCurtailmentLimitFactor
+
+	changeable, err := s.deviceConfiguration.IsChangeable(keyName)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+	assert.False(s.T(), changeable)
+
+	s.addDescription()
+
+	changeable, err = s.deviceConfiguration.IsChangeable(keyName)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+	assert.False(s.T(), changeable)
+
+	s.addData()
+
+	changeable, err = s.deviceConfiguration.IsChangeable(keyName)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), changeable)
+
+	s.setChangeable(2, true)
+
+	changeable, err = s.deviceConfiguration.IsChangeable(keyName)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), changeable)
+}
+
+func (s *DeviceConfigurationSuite) Test_WriteKeyValueForKeyName() {
+	keyName := model.DeviceConfigurationKeyNameTypePvCurtailmentLimitFactor
+	value := model.DeviceConfigurationKeyValueValueType{
+		ScaledNumber: model.NewScaledNumberType(40),
+	}
+
+	counter, err := s.deviceConfiguration.WriteKeyValueForKeyName(keyName, value)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+	assert.Nil(s.T(), counter)
+
+	s.addDescription()
+
+	// a value type different to the description
+	counter, err = s.deviceConfiguration.WriteKeyValueForKeyName(keyName, model.DeviceConfigurationKeyValueValueType{
+		Boolean: util.Ptr(true),
+	})
+	assert.Equal(s.T(), api.ErrValueTypeMismatch, err)
+	assert.Nil(s.T(), counter)
+
+	// multiple values
+	counter, err = s.deviceConfiguration.WriteKeyValueForKeyName(keyName, model.DeviceConfigurationKeyValueValueType{
+		Boolean:      util.Ptr(true),
+		ScaledNumber: model.NewScaledNumberType(40),
+	})
+	assert.Equal(s.T(), api.ErrValueTypeMismatch, err)
+	assert.Nil(s.T(), counter)
+
+	// no value is known yet
+	counter, err = s.deviceConfiguration.WriteKeyValueForKeyName(keyName, value)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+
+	s.addData()
+	s.setChangeable(2, false)
+
+	counter, err = s.deviceConfiguration.WriteKeyValueForKeyName(keyName, value)
+	assert.Equal(s.T(), api.ErrNotSupported, err)
+	assert.Nil(s.T(), counter)
+
+	s.setChangeable(2, true)
+
+	counter, err = s.deviceConfiguration.WriteKeyValueForKeyName(keyName, value)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+
+	cmd := sentCmd(s.T(), s.writeHandler, counter)
+	assert.Equal(s.T(), 1, len(cmd.Filter))
+	assert.NotNil(s.T(), cmd.Filter[0].CmdControl.Partial)
+	selector := cmd.Filter[0].DeviceConfigurationKeyValueListDataSelectors
+	assert.NotNil(s.T(), selector)
+	assert.Equal(s.T(), model.DeviceConfigurationKeyIdType(2), *selector.KeyId)
+	data := cmd.DeviceConfigurationKeyValueListData.DeviceConfigurationKeyValueData
+	assert.Equal(s.T(), 1, len(data))
+	assert.Equal(s.T(), 40.0, data[0].Value.ScaledNumber.GetValue())
+}
+
+func (s *DeviceConfigurationSuite) Test_WriteKeyValueForKeyName_NotWriteable() {
+	s.localEntity, s.remoteEntity = setupFeatures(
+		s.T(),
+		s.writeHandler,
+		[]featureFunctions{
+			{
+				featureType: model.FeatureTypeTypeDeviceConfiguration,
+				functions: []model.FunctionType{
+					model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+				},
+			},
+		},
+	)
+
+	var err error
+	s.deviceConfiguration, err = features.NewDeviceConfiguration(s.localEntity, s.remoteEntity)
+	assert.Nil(s.T(), err)
+
+	s.addDescription()
+
+	counter, err := s.deviceConfiguration.WriteKeyValueForKeyName(
+		model.DeviceConfigurationKeyNameTypePvCurtailmentLimitFactor,
+		model.DeviceConfigurationKeyValueValueType{
+			ScaledNumber: model.NewScaledNumberType(40),
+		})
+	assert.Equal(s.T(), api.ErrFunctionNotSupported, err)
+	assert.Nil(s.T(), counter)
+}
+
 // helper
 
+func (s *DeviceConfigurationSuite) setChangeable(keyId model.DeviceConfigurationKeyIdType, changeable bool) {
+	rF := s.remoteEntity.FeatureOfAddress(util.Ptr(model.AddressFeatureType(1)))
+	fData := &model.DeviceConfigurationKeyValueListDataType{
+		DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+			{
+				KeyId:             util.Ptr(keyId),
+				IsValueChangeable: util.Ptr(changeable),
+			},
+		},
+	}
+	rF.UpdateData(model.FunctionTypeDeviceConfigurationKeyValueListData, fData, model.NewFilterTypePartial(), nil)
+}
+
 func (s *DeviceConfigurationSuite) addDescription() {
 	rF := s.remoteEntity.FeatureOfAddress(util.Ptr(model.AddressFeatureType(1)))
 	fData := &model.DeviceConfigurationKeyValueDescriptionListDataType{
@@ -0,0 +1,273 @@
+package features
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+type DeviceConfigurationServer struct {
+	*FeatureServer
+}
+
+// Get a new DeviceConfiguration server features helper
+//
+// - The feature on the local entity has the role server and is added if needed
+// - The key value list data may be written by remote clients. A write is
+// rejected if it adds or removes key values, removes a value, changes key
+// values which are not changeable or is not approved by an approval added
+// with AddWriteApproval.
+func NewDeviceConfigurationServer(localEntity spineapi.EntityLocalInterface) (*DeviceConfigurationServer, error) {
+	feature, err := NewFeatureServer(model.FeatureTypeTypeDeviceConfiguration, localEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DeviceConfigurationServer{
+		FeatureServer: feature,
+	}
+	d.addFunctionTypes(false, model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData)
+	d.addFunctionTypes(true, model.FunctionTypeDeviceConfigurationKeyValueListData)
+	d.featureLocal.setWriteCheck(model.FunctionTypeDeviceConfigurationKeyValueListData, d.checkWrite)
+
+	return d, nil
+}
+
+// add a key value description and return the assigned key id
+//
+// a key id set in the description is ignored
+//
+// possible errors:
+//   - ErrMissingData if the description has no key name or value type
+//   - an error if the key name is already described
+func (d *DeviceConfigurationServer) AddKeyValueDescription(
+	description model.DeviceConfigurationKeyValueDescriptionDataType) (model.DeviceConfigurationKeyIdType, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if description.KeyName == nil || description.ValueType == nil {
+		return 0, api.ErrMissingData
+	}
+
+	data := d.descriptions()
+	if slices.ContainsFunc(data.DeviceConfigurationKeyValueDescriptionData,
+		func(item model.DeviceConfigurationKeyValueDescriptionDataType) bool {
+			return equalPtr(item.KeyName, description.KeyName)
+		}) {
+		return 0, errors.New("key name is already described")
+	}
+
+	var keyIds []model.DeviceConfigurationKeyIdType
+	for _, item := range data.DeviceConfigurationKeyValueDescriptionData {
+		if item.KeyId != nil {
+			keyIds = append(keyIds, *item.KeyId)
+		}
+	}
+
+	keyId := nextId(keyIds)
+	description.KeyId = util.Ptr(keyId)
+	data.DeviceConfigurationKeyValueDescriptionData = append(data.DeviceConfigurationKeyValueDescriptionData, description)
+	d.featureLocal.SetData(model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData, data)
+
+	return keyId, nil
+}
+
+// update the key values of described keys and notify subscribers
+//
+// only the values of the given keys are replaced,
+// the values of all other keys are kept
+//
+// possible errors:
+//   - ErrMissingData if a key value has no key id
+//   - ErrMetadataNotAvailable if a key is not described
+func (d *DeviceConfigurationServer) UpdateKeyValues(keyValues ...model.DeviceConfigurationKeyValueDataType) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	for _, item := range keyValues {
+		if item.KeyId == nil {
+			return api.ErrMissingData
+		}
+		if _, ok := d.descriptionForKeyId(*item.KeyId); !ok {
+			return api.ErrMetadataNotAvailable
+		}
+	}
+
+	data := d.keyValues()
+	data.DeviceConfigurationKeyValueData = mergeItems(data.DeviceConfigurationKeyValueData, keyValues, d.matches)
+	d.featureLocal.SetData(model.FunctionTypeDeviceConfigurationKeyValueListData, data)
+
+	return nil
+}
+
+// update the value of a described key and notify subscribers
+//
+// changeable defines if remote clients may write the value
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if the key is not described
+func (d *DeviceConfigurationServer) UpdateKeyValueForKeyName(
+	keyName model.DeviceConfigurationKeyNameType,
+	value model.DeviceConfigurationKeyValueValueType,
+	changeable bool) error {
+	description, ok := d.descriptionForKeyName(keyName)
+	if !ok {
+		return api.ErrMetadataNotAvailable
+	}
+
+	return d.UpdateKeyValues(model.DeviceConfigurationKeyValueDataType{
+		KeyId:             description.KeyId,
+		Value:             util.Ptr(value),
+		IsValueChangeable: util.Ptr(changeable),
+	})
+}
+
+// return the description of a key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if the key is not described
+func (d *DeviceConfigurationServer) GetKeyValueDescriptionForKeyName(
+	keyName model.DeviceConfigurationKeyNameType) (*model.DeviceConfigurationKeyValueDescriptionDataType, error) {
+	description, ok := d.descriptionForKeyName(keyName)
+	if !ok {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	return &description, nil
+}
+
+// return the key value of a described key
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if the key is not described
+//   - ErrDataNotAvailable if no value for the key is set
+func (d *DeviceConfigurationServer) GetKeyValueForKeyName(
+	keyName model.DeviceConfigurationKeyNameType) (*model.DeviceConfigurationKeyValueDataType, error) {
+	description, ok := d.descriptionForKeyName(keyName)
+	if !ok {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	for _, item := range d.keyValues().DeviceConfigurationKeyValueData {
+		if item.KeyId != nil && *item.KeyId == *description.KeyId {
+			return &item, nil
+		}
+	}
+
+	return nil, api.ErrDataNotAvailable
+}
+
+// add a function approving remote writes of a key value
+//
+// approve is invoked with the write message and the data of the key value
+// resulting from a write, which is rejected if any approval returns an
+// error. The approvals are kept on the local feature and are invoked
+// without the data being locked.
+func (d *DeviceConfigurationServer) AddWriteApproval(
+	keyId model.DeviceConfigurationKeyIdType,
+	approve func(message *spineapi.Message, keyValue model.DeviceConfigurationKeyValueDataType) error) {
+	d.featureLocal.addWriteApproval(model.FunctionTypeDeviceConfigurationKeyValueListData, uint(keyId),
+		func(message *spineapi.Message, item any) error {
+			return approve(message, item.(model.DeviceConfigurationKeyValueDataType))
+		})
+}
+
+// check a remote write of the key value list data
+//
+// only the values of existing keys which are changeable may be written and
+// have to match the value type of the key description, the changeability
+// is defined by the server only and is kept
+//
+// possible errors:
+//   - ErrDataNotAvailable if a written key has no value
+//   - ErrNotSupported if a key value would be removed, is not changeable
+//     or does not match the value type
+func (d *DeviceConfigurationServer) checkWrite(message *spineapi.Message, written any) (any, map[uint]any, error) {
+	current, result, err := writeResult[model.DeviceConfigurationKeyValueListDataType](
+		d.featureLocal, model.FunctionTypeDeviceConfigurationKeyValueListData, message, written)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, item := range current.DeviceConfigurationKeyValueData {
+		if !slices.ContainsFunc(result.DeviceConfigurationKeyValueData, func(a model.DeviceConfigurationKeyValueDataType) bool {
+			return a.KeyId != nil && d.matches(a, item)
+		}) {
+			return nil, nil, api.ErrNotSupported
+		}
+	}
+
+	items := make(map[uint]any)
+	for index, item := range result.DeviceConfigurationKeyValueData {
+		i := slices.IndexFunc(current.DeviceConfigurationKeyValueData, func(a model.DeviceConfigurationKeyValueDataType) bool {
+			return item.KeyId != nil && d.matches(a, item)
+		})
+		if i < 0 || item.Value == nil {
+			return nil, nil, api.ErrDataNotAvailable
+		}
+
+		existing := current.DeviceConfigurationKeyValueData[i]
+		item.IsValueChangeable = existing.IsValueChangeable
+		result.DeviceConfigurationKeyValueData[index] = item
+		if reflect.DeepEqual(item, existing) {
+			continue
+		}
+
+		if existing.IsValueChangeable != nil && !*existing.IsValueChangeable {
+			return nil, nil, api.ErrNotSupported
+		}
+
+		description, ok := d.descriptionForKeyId(*item.KeyId)
+		valueType, valid := keyValueValueType(*item.Value)
+		if !ok || description.ValueType == nil || !valid || valueType != *description.ValueType {
+			return nil, nil, api.ErrNotSupported
+		}
+
+		items[uint(*item.KeyId)] = item
+	}
+
+	return result, items, nil
+}
+
+func (d *DeviceConfigurationServer) matches(a, b model.DeviceConfigurationKeyValueDataType) bool {
+	return *a.KeyId == *b.KeyId
+}
+
+func (d *DeviceConfigurationServer) keyValues() *model.DeviceConfigurationKeyValueListDataType {
+	return localDataCopy[model.DeviceConfigurationKeyValueListDataType](
+		d.featureLocal, model.FunctionTypeDeviceConfigurationKeyValueListData)
+}
+
+func (d *DeviceConfigurationServer) descriptions() *model.DeviceConfigurationKeyValueDescriptionListDataType {
+	return localDataCopy[model.DeviceConfigurationKeyValueDescriptionListDataType](
+		d.featureLocal, model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData)
+}
+
+// return the description of a key id
+func (d *DeviceConfigurationServer) descriptionForKeyId(
+	keyId model.DeviceConfigurationKeyIdType) (model.DeviceConfigurationKeyValueDescriptionDataType, bool) {
+	for _, item := range d.descriptions().DeviceConfigurationKeyValueDescriptionData {
+		if item.KeyId != nil && *item.KeyId == keyId {
+			return item, true
+		}
+	}
+
+	return model.DeviceConfigurationKeyValueDescriptionDataType{}, false
+}
+
+// return the description of a key name
+func (d *DeviceConfigurationServer) descriptionForKeyName(
+	keyName model.DeviceConfigurationKeyNameType) (model.DeviceConfigurationKeyValueDescriptionDataType, bool) {
+	for _, item := range d.descriptions().DeviceConfigurationKeyValueDescriptionData {
+		if item.KeyId != nil && item.KeyName != nil && *item.KeyName == keyName {
+			return item, true
+		}
+	}
+
+	return model.DeviceConfigurationKeyValueDescriptionDataType{}, false
+}
@@ -0,0 +1,176 @@
+package features_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDeviceConfigurationServerSuite(t *testing.T) {
+	suite.Run(t, new(DeviceConfigurationServerSuite))
+}
+
+type DeviceConfigurationServerSuite struct {
+	suite.Suite
+
+	deviceConfiguration *features.DeviceConfigurationServer
+	keyId               model.DeviceConfigurationKeyIdType
+}
+
+const serverKeyName = model.DeviceConfigurationKeyNameTypeFailsafeConsumptionActivePowerLimit
+
+func (s *DeviceConfigurationServerSuite) BeforeTest(suiteName, testName string) {
+	var err error
+	s.deviceConfiguration, err = features.NewDeviceConfigurationServer(setupLocalEntity())
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), s.deviceConfiguration)
+
+	s.keyId, err = s.deviceConfiguration.AddKeyValueDescription(model.DeviceConfigurationKeyValueDescriptionDataType{
+		KeyName:   util.Ptr(serverKeyName),
+		ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+		Unit:      util.Ptr(model.UnitOfMeasurementTypeW),
+	})
+	assert.Nil(s.T(), err)
+}
+
+// let the local feature receive a partial remote write of the key value
+func (s *DeviceConfigurationServerSuite) write(value *model.DeviceConfigurationKeyValueValueType) *model.ErrorType {
+	return s.deviceConfiguration.LocalFeature().HandleMessage(&spineapi.Message{
+		RequestHeader: &model.HeaderType{},
+		CmdClassifier: model.CmdClassifierTypeWrite,
+		Cmd: model.CmdType{
+			DeviceConfigurationKeyValueListData: &model.DeviceConfigurationKeyValueListDataType{
+				DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+					{
+						KeyId: util.Ptr(s.keyId),
+						Value: value,
+					},
+				},
+			},
+		},
+		FilterPartial: model.NewFilterTypePartial(),
+	})
+}
+
+func (s *DeviceConfigurationServerSuite) value() float64 {
+	keyValue, err := s.deviceConfiguration.GetKeyValueForKeyName(serverKeyName)
+	assert.Nil(s.T(), err)
+
+	return keyValue.Value.ScaledNumber.GetValue()
+}
+
+func (s *DeviceConfigurationServerSuite) Test_AddKeyValueDescription() {
+	_, err := s.deviceConfiguration.AddKeyValueDescription(model.DeviceConfigurationKeyValueDescriptionDataType{})
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	// the key name is already described
+	_, err = s.deviceConfiguration.AddKeyValueDescription(model.DeviceConfigurationKeyValueDescriptionDataType{
+		KeyName:   util.Ptr(serverKeyName),
+		ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+	})
+	assert.NotNil(s.T(), err)
+
+	keyId, err := s.deviceConfiguration.AddKeyValueDescription(model.DeviceConfigurationKeyValueDescriptionDataType{
+		KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum),
+		ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+	})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), s.keyId+1, keyId)
+
+	description, err := s.deviceConfiguration.GetKeyValueDescriptionForKeyName(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), keyId, *description.KeyId)
+
+	_, err = s.deviceConfiguration.GetKeyValueDescriptionForKeyName(model.DeviceConfigurationKeyNameTypeCommunicationsStandard)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+}
+
+func (s *DeviceConfigurationServerSuite) Test_UpdateKeyValues() {
+	_, err := s.deviceConfiguration.GetKeyValueForKeyName(serverKeyName)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	_, err = s.deviceConfiguration.GetKeyValueForKeyName(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	err = s.deviceConfiguration.UpdateKeyValues(model.DeviceConfigurationKeyValueDataType{})
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	err = s.deviceConfiguration.UpdateKeyValues(model.DeviceConfigurationKeyValueDataType{
+		KeyId: util.Ptr(s.keyId + 1),
+	})
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	err = s.deviceConfiguration.UpdateKeyValueForKeyName(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum,
+		model.DeviceConfigurationKeyValueValueType{}, true)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	err = s.deviceConfiguration.UpdateKeyValueForKeyName(serverKeyName,
+		model.DeviceConfigurationKeyValueValueType{ScaledNumber: model.NewScaledNumberType(4200)}, true)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, s.value())
+}
+
+func (s *DeviceConfigurationServerSuite) Test_Write() {
+	err := s.deviceConfiguration.UpdateKeyValueForKeyName(serverKeyName,
+		model.DeviceConfigurationKeyValueValueType{ScaledNumber: model.NewScaledNumberType(4200)}, true)
+	assert.Nil(s.T(), err)
+
+	var approved []model.DeviceConfigurationKeyValueDataType
+	s.deviceConfiguration.AddWriteApproval(s.keyId,
+		func(message *spineapi.Message, keyValue model.DeviceConfigurationKeyValueDataType) error {
+			approved = append(approved, keyValue)
+			if keyValue.Value.ScaledNumber.GetValue() < 0 {
+				return errors.New("invalid value")
+			}
+			return nil
+		})
+
+	// a valid write
+	writeErr := s.write(&model.DeviceConfigurationKeyValueValueType{ScaledNumber: model.NewScaledNumberType(1000)})
+	assert.Nil(s.T(), writeErr)
+	assert.Equal(s.T(), 1000.0, s.value())
+	assert.Equal(s.T(), 1, len(approved))
+
+	// a write removing the value is not applied
+	writeErr = s.write(nil)
+	assert.NotNil(s.T(), writeErr)
+	assert.Equal(s.T(), 1000.0, s.value())
+	assert.Equal(s.T(), 1, len(approved))
+
+	// a write not matching the value type of the key is not applied
+	writeErr = s.write(&model.DeviceConfigurationKeyValueValueType{Boolean: util.Ptr(true)})
+	assert.NotNil(s.T(), writeErr)
+	assert.Equal(s.T(), 1000.0, s.value())
+	assert.Equal(s.T(), 1, len(approved))
+
+	writeErr = s.write(&model.DeviceConfigurationKeyValueValueType{
+		Boolean:      util.Ptr(true),
+		ScaledNumber: model.NewScaledNumberType(2000),
+	})
+	assert.NotNil(s.T(), writeErr)
+	assert.Equal(s.T(), 1000.0, s.value())
+	assert.Equal(s.T(), 1, len(approved))
+
+	// a write rejected by the approval is not applied
+	writeErr = s.write(&model.DeviceConfigurationKeyValueValueType{ScaledNumber: model.NewScaledNumberType(-1)})
+	assert.NotNil(s.T(), writeErr)
+	assert.Equal(s.T(), model.ErrorNumberTypeCommandRejected, writeErr.ErrorNumber)
+	assert.Equal(s.T(), 1000.0, s.value())
+
+	// a write of a non changeable key is not applied
+	err = s.deviceConfiguration.UpdateKeyValueForKeyName(serverKeyName,
+		model.DeviceConfigurationKeyValueValueType{ScaledNumber: model.NewScaledNumberType(1000)}, false)
+	assert.Nil(s.T(), err)
+
+	writeErr = s.write(&model.DeviceConfigurationKeyValueValueType{ScaledNumber: model.NewScaledNumberType(2000)})
+	assert.NotNil(s.T(), writeErr)
+	assert.Equal(s.T(), 1000.0, s.value())
+	assert.Equal(s.T(), 2, len(approved))
+}
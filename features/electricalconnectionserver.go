@@ -0,0 +1,276 @@
+package features
+
+import (
+	"slices"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+type ElectricalConnectionServer struct {
+	*FeatureServer
+}
+
+// Get a new ElectricalConnection server features helper
+//
+// - The feature on the local entity has the role server and is added if needed
+// - All functions are read only for remote clients
+func NewElectricalConnectionServer(localEntity spineapi.EntityLocalInterface) (*ElectricalConnectionServer, error) {
+	feature, err := NewFeatureServer(model.FeatureTypeTypeElectricalConnection, localEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &ElectricalConnectionServer{
+		FeatureServer: feature,
+	}
+	e.addFunctionTypes(false,
+		model.FunctionTypeElectricalConnectionDescriptionListData,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+		model.FunctionTypeElectricalConnectionPermittedValueSetListData,
+		model.FunctionTypeElectricalConnectionCharacteristicListData,
+	)
+
+	return e, nil
+}
+
+// add an electrical connection description and return the assigned electrical connection id
+//
+// an electrical connection id set in the description is ignored
+func (e *ElectricalConnectionServer) AddDescription(
+	description model.ElectricalConnectionDescriptionDataType) model.ElectricalConnectionIdType {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	data := localDataCopy[model.ElectricalConnectionDescriptionListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionDescriptionListData)
+
+	electricalConnectionId := nextId(e.electricalConnectionIds())
+	description.ElectricalConnectionId = util.Ptr(electricalConnectionId)
+	data.ElectricalConnectionDescriptionData = append(data.ElectricalConnectionDescriptionData, description)
+	e.featureLocal.SetData(model.FunctionTypeElectricalConnectionDescriptionListData, data)
+
+	return electricalConnectionId
+}
+
+// return the electrical connection descriptions
+func (e *ElectricalConnectionServer) GetDescriptions() []model.ElectricalConnectionDescriptionDataType {
+	data := localDataCopy[model.ElectricalConnectionDescriptionListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionDescriptionListData)
+
+	return data.ElectricalConnectionDescriptionData
+}
+
+// add a parameter description of a described electrical connection
+// and return the assigned parameter id
+//
+// a parameter id set in the description is ignored
+//
+// possible errors:
+//   - ErrMissingData if the description has no electrical connection id
+//   - ErrMetadataNotAvailable if the electrical connection is not described
+func (e *ElectricalConnectionServer) AddParameterDescription(
+	description model.ElectricalConnectionParameterDescriptionDataType) (model.ElectricalConnectionParameterIdType, error) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if err := e.checkElectricalConnectionId(description.ElectricalConnectionId); err != nil {
+		return 0, err
+	}
+
+	data := localDataCopy[model.ElectricalConnectionParameterDescriptionListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionParameterDescriptionListData)
+
+	var parameterIds []model.ElectricalConnectionParameterIdType
+	for _, item := range data.ElectricalConnectionParameterDescriptionData {
+		if item.ParameterId != nil {
+			parameterIds = append(parameterIds, *item.ParameterId)
+		}
+	}
+
+	parameterId := nextId(parameterIds)
+	description.ParameterId = util.Ptr(parameterId)
+	data.ElectricalConnectionParameterDescriptionData = append(data.ElectricalConnectionParameterDescriptionData, description)
+	e.featureLocal.SetData(model.FunctionTypeElectricalConnectionParameterDescriptionListData, data)
+
+	return parameterId, nil
+}
+
+// update the permitted value sets of described parameters and notify subscribers
+//
+// only the permitted value sets of the given parameters are replaced,
+// the permitted value sets of all other parameters are kept
+//
+// possible errors:
+//   - ErrMissingData if a permitted value set has no electrical connection or parameter id
+//   - ErrMetadataNotAvailable if a parameter is not described
+func (e *ElectricalConnectionServer) UpdatePermittedValueSets(
+	sets ...model.ElectricalConnectionPermittedValueSetDataType) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	for _, item := range sets {
+		if err := e.checkParameterId(item.ElectricalConnectionId, item.ParameterId); err != nil {
+			return err
+		}
+	}
+
+	data := localDataCopy[model.ElectricalConnectionPermittedValueSetListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionPermittedValueSetListData)
+	data.ElectricalConnectionPermittedValueSetData = mergeItems(data.ElectricalConnectionPermittedValueSetData, sets,
+		func(a, b model.ElectricalConnectionPermittedValueSetDataType) bool {
+			return *a.ElectricalConnectionId == *b.ElectricalConnectionId && *a.ParameterId == *b.ParameterId
+		})
+	e.featureLocal.SetData(model.FunctionTypeElectricalConnectionPermittedValueSetListData, data)
+
+	return nil
+}
+
+// add a characteristic of a described electrical connection
+// and return the assigned characteristic id
+//
+// a characteristic id set in the characteristic is ignored
+//
+// possible errors:
+//   - ErrMissingData if the characteristic has no electrical connection id
+//   - ErrMetadataNotAvailable if the electrical connection is not described
+func (e *ElectricalConnectionServer) AddCharacteristic(
+	characteristic model.ElectricalConnectionCharacteristicDataType) (model.ElectricalConnectionCharaceteristicIdType, error) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	if err := e.checkElectricalConnectionId(characteristic.ElectricalConnectionId); err != nil {
+		return 0, err
+	}
+
+	data := localDataCopy[model.ElectricalConnectionCharacteristicListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionCharacteristicListData)
+
+	characteristicId := nextId(e.characteristicIds(data))
+	characteristic.CharacteristicId = util.Ptr(characteristicId)
+	data.ElectricalConnectionCharacteristicListData = append(data.ElectricalConnectionCharacteristicListData, characteristic)
+	e.featureLocal.SetData(model.FunctionTypeElectricalConnectionCharacteristicListData, data)
+
+	return characteristicId, nil
+}
+
+// update added characteristics and notify subscribers
+//
+// only the given characteristics are replaced, all other characteristics are kept
+//
+// possible errors:
+//   - ErrMissingData if a characteristic has no characteristic id
+//   - ErrDataNotAvailable if a characteristic was not added
+func (e *ElectricalConnectionServer) UpdateCharacteristics(
+	characteristics ...model.ElectricalConnectionCharacteristicDataType) error {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	data := localDataCopy[model.ElectricalConnectionCharacteristicListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionCharacteristicListData)
+
+	characteristicIds := e.characteristicIds(data)
+	for _, item := range characteristics {
+		if item.CharacteristicId == nil {
+			return api.ErrMissingData
+		}
+		if !slices.Contains(characteristicIds, *item.CharacteristicId) {
+			return api.ErrDataNotAvailable
+		}
+	}
+
+	data.ElectricalConnectionCharacteristicListData = mergeItems(data.ElectricalConnectionCharacteristicListData, characteristics,
+		func(a, b model.ElectricalConnectionCharacteristicDataType) bool {
+			return *a.CharacteristicId == *b.CharacteristicId
+		})
+	e.featureLocal.SetData(model.FunctionTypeElectricalConnectionCharacteristicListData, data)
+
+	return nil
+}
+
+// return the characteristic of a context and type
+//
+// possible errors:
+//   - ErrDataNotAvailable if no such characteristic was added
+func (e *ElectricalConnectionServer) GetCharacteristicForContextType(
+	context model.ElectricalConnectionCharacteristicContextType,
+	cType model.ElectricalConnectionCharacteristicTypeType,
+) (*model.ElectricalConnectionCharacteristicDataType, error) {
+	data := localDataCopy[model.ElectricalConnectionCharacteristicListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionCharacteristicListData)
+
+	for _, item := range data.ElectricalConnectionCharacteristicListData {
+		if item.CharacteristicId != nil &&
+			item.CharacteristicContext != nil &&
+			*item.CharacteristicContext == context &&
+			item.CharacteristicType != nil &&
+			*item.CharacteristicType == cType {
+			return &item, nil
+		}
+	}
+
+	return nil, api.ErrDataNotAvailable
+}
+
+// return an error if the electrical connection id is missing or not described
+func (e *ElectricalConnectionServer) checkElectricalConnectionId(electricalConnectionId *model.ElectricalConnectionIdType) error {
+	if electricalConnectionId == nil {
+		return api.ErrMissingData
+	}
+
+	if !slices.Contains(e.electricalConnectionIds(), *electricalConnectionId) {
+		return api.ErrMetadataNotAvailable
+	}
+
+	return nil
+}
+
+// return an error if the parameter ids are missing or not described
+func (e *ElectricalConnectionServer) checkParameterId(
+	electricalConnectionId *model.ElectricalConnectionIdType,
+	parameterId *model.ElectricalConnectionParameterIdType) error {
+	if electricalConnectionId == nil || parameterId == nil {
+		return api.ErrMissingData
+	}
+
+	data := localDataCopy[model.ElectricalConnectionParameterDescriptionListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionParameterDescriptionListData)
+	if !slices.ContainsFunc(data.ElectricalConnectionParameterDescriptionData,
+		func(item model.ElectricalConnectionParameterDescriptionDataType) bool {
+			return equalPtr(item.ElectricalConnectionId, electricalConnectionId) && equalPtr(item.ParameterId, parameterId)
+		}) {
+		return api.ErrMetadataNotAvailable
+	}
+
+	return nil
+}
+
+// return the ids of the described electrical connections
+func (e *ElectricalConnectionServer) electricalConnectionIds() []model.ElectricalConnectionIdType {
+	data := localDataCopy[model.ElectricalConnectionDescriptionListDataType](
+		e.featureLocal, model.FunctionTypeElectricalConnectionDescriptionListData)
+
+	var result []model.ElectricalConnectionIdType
+	for _, item := range data.ElectricalConnectionDescriptionData {
+		if item.ElectricalConnectionId != nil {
+			result = append(result, *item.ElectricalConnectionId)
+		}
+	}
+
+	return result
+}
+
+// return the ids of the added characteristics
+func (e *ElectricalConnectionServer) characteristicIds(
+	data *model.ElectricalConnectionCharacteristicListDataType) []model.ElectricalConnectionCharaceteristicIdType {
+	var result []model.ElectricalConnectionCharaceteristicIdType
+	for _, item := range data.ElectricalConnectionCharacteristicListData {
+		if item.CharacteristicId != nil {
+			result = append(result, *item.CharacteristicId)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,151 @@
+package features_test
+
+import (
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/util"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestElectricalConnectionServerSuite(t *testing.T) {
+	suite.Run(t, new(ElectricalConnectionServerSuite))
+}
+
+type ElectricalConnectionServerSuite struct {
+	suite.Suite
+
+	electricalConnection *features.ElectricalConnectionServer
+}
+
+func (s *ElectricalConnectionServerSuite) BeforeTest(suiteName, testName string) {
+	var err error
+	s.electricalConnection, err = features.NewElectricalConnectionServer(setupLocalEntity())
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), s.electricalConnection)
+}
+
+func (s *ElectricalConnectionServerSuite) Test_NewElectricalConnectionServer() {
+	electricalConnection, err := features.NewElectricalConnectionServer(nil)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), electricalConnection)
+}
+
+func (s *ElectricalConnectionServerSuite) Test_PermittedValueSets() {
+	_, err := s.electricalConnection.AddParameterDescription(model.ElectricalConnectionParameterDescriptionDataType{})
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	_, err = s.electricalConnection.AddParameterDescription(model.ElectricalConnectionParameterDescriptionDataType{
+		ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+	})
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	assert.Equal(s.T(), 0, len(s.electricalConnection.GetDescriptions()))
+
+	ecId := s.electricalConnection.AddDescription(model.ElectricalConnectionDescriptionDataType{
+		PowerSupplyType: util.Ptr(model.ElectricalConnectionVoltageTypeTypeAc),
+	})
+	assert.Equal(s.T(), model.ElectricalConnectionIdType(0), ecId)
+	assert.Equal(s.T(), 1, len(s.electricalConnection.GetDescriptions()))
+
+	paramA, err := s.electricalConnection.AddParameterDescription(model.ElectricalConnectionParameterDescriptionDataType{
+		ElectricalConnectionId: util.Ptr(ecId),
+		AcMeasuredPhases:       util.Ptr(model.ElectricalConnectionPhaseNameTypeA),
+	})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), model.ElectricalConnectionParameterIdType(0), paramA)
+
+	paramB, err := s.electricalConnection.AddParameterDescription(model.ElectricalConnectionParameterDescriptionDataType{
+		ElectricalConnectionId: util.Ptr(ecId),
+		AcMeasuredPhases:       util.Ptr(model.ElectricalConnectionPhaseNameTypeB),
+	})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), model.ElectricalConnectionParameterIdType(1), paramB)
+
+	set := func(paramId model.ElectricalConnectionParameterIdType, max float64) model.ElectricalConnectionPermittedValueSetDataType {
+		return model.ElectricalConnectionPermittedValueSetDataType{
+			ElectricalConnectionId: util.Ptr(ecId),
+			ParameterId:            util.Ptr(paramId),
+			PermittedValueSet: []model.ScaledNumberSetType{
+				{
+					Range: []model.ScaledNumberRangeType{
+						{Min: model.NewScaledNumberType(6), Max: model.NewScaledNumberType(max)},
+					},
+				},
+			},
+		}
+	}
+
+	err = s.electricalConnection.UpdatePermittedValueSets(set(model.ElectricalConnectionParameterIdType(5), 16))
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	err = s.electricalConnection.UpdatePermittedValueSets(set(paramA, 16), set(paramB, 16))
+	assert.Nil(s.T(), err)
+
+	// partial update, the set of phase A is kept
+	err = s.electricalConnection.UpdatePermittedValueSets(set(paramB, 32))
+	assert.Nil(s.T(), err)
+
+	data, err := spine.LocalFeatureDataCopyOfType[*model.ElectricalConnectionPermittedValueSetListDataType](
+		s.electricalConnection.LocalFeature(), model.FunctionTypeElectricalConnectionPermittedValueSetListData)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, len(data.ElectricalConnectionPermittedValueSetData))
+	assert.Equal(s.T(), 16.0, data.ElectricalConnectionPermittedValueSetData[0].PermittedValueSet[0].Range[0].Max.GetValue())
+	assert.Equal(s.T(), 32.0, data.ElectricalConnectionPermittedValueSetData[1].PermittedValueSet[0].Range[0].Max.GetValue())
+}
+
+func (s *ElectricalConnectionServerSuite) Test_Characteristics() {
+	_, err := s.electricalConnection.AddCharacteristic(model.ElectricalConnectionCharacteristicDataType{
+		ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+	})
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	ecId := s.electricalConnection.AddDescription(model.ElectricalConnectionDescriptionDataType{})
+
+	id, err := s.electricalConnection.AddCharacteristic(model.ElectricalConnectionCharacteristicDataType{
+		ElectricalConnectionId: util.Ptr(ecId),
+		CharacteristicContext:  util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+		CharacteristicType:     util.Ptr(model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax),
+		Value:                  model.NewScaledNumberType(11000),
+	})
+	assert.Nil(s.T(), err)
+
+	err = s.electricalConnection.UpdateCharacteristics(model.ElectricalConnectionCharacteristicDataType{
+		CharacteristicId: util.Ptr(id + 1),
+	})
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	err = s.electricalConnection.UpdateCharacteristics(model.ElectricalConnectionCharacteristicDataType{})
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	err = s.electricalConnection.UpdateCharacteristics(model.ElectricalConnectionCharacteristicDataType{
+		ElectricalConnectionId: util.Ptr(ecId),
+		CharacteristicId:       util.Ptr(id),
+		CharacteristicContext:  util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+		CharacteristicType:     util.Ptr(model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax),
+		Value:                  model.NewScaledNumberType(22000),
+	})
+	assert.Nil(s.T(), err)
+
+	data, err := spine.LocalFeatureDataCopyOfType[*model.ElectricalConnectionCharacteristicListDataType](
+		s.electricalConnection.LocalFeature(), model.FunctionTypeElectricalConnectionCharacteristicListData)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(data.ElectricalConnectionCharacteristicListData))
+	assert.Equal(s.T(), 22000.0, data.ElectricalConnectionCharacteristicListData[0].Value.GetValue())
+
+	characteristic, err := s.electricalConnection.GetCharacteristicForContextType(
+		model.ElectricalConnectionCharacteristicContextTypeEntity,
+		model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), id, *characteristic.CharacteristicId)
+	assert.Equal(s.T(), 22000.0, characteristic.Value.GetValue())
+
+	_, err = s.electricalConnection.GetCharacteristicForContextType(
+		model.ElectricalConnectionCharacteristicContextTypeEntity,
+		model.ElectricalConnectionCharacteristicTypeTypePowerProductionNominalMax)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+}
@@ -1,11 +1,14 @@
 package features
 
 import (
+	"context"
 	"errors"
 
 	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/util"
 	spineapi "github.com/enbility/spine-go/api"
 	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
 )
 
 type Feature struct {
@@ -22,6 +25,8 @@ type Feature struct {
 
 	remoteDevice spineapi.DeviceRemoteInterface
 	remoteEntity spineapi.EntityRemoteInterface
+
+	results *resultDispatcher
 }
 
 var _ FeatureInterface = (*Feature)(nil)
@@ -51,6 +56,16 @@ func NewFeature(
 	var err error
 	f.featureLocal, f.featureRemote, err = f.getLocalAndRemoteFeatures()
 
+	// the results have to be dispatched before any message is sent,
+	// subscriptions and bindings are sent by the node management
+	f.results = resultDispatcherForDevice(f.spineLocalDevice)
+	if f.featureLocal != nil {
+		f.results.addFeature(f.featureLocal)
+	}
+	if nodeManagement := f.spineLocalDevice.NodeManagement(); nodeManagement != nil {
+		f.results.addFeature(nodeManagement)
+	}
+
 	return f, err
 }
 
@@ -62,23 +77,33 @@ func (f *Feature) HasSubscription() bool {
 
 // subscribe to the feature of the entity
 func (f *Feature) Subscribe() (*model.MsgCounterType, error) {
+	f.results.beginSend()
+	defer f.results.endSend()
+
 	msgCounter, fErr := f.featureLocal.SubscribeToRemote(f.featureRemote.Address())
 
 	if fErr != nil {
-		return nil, errors.New(fErr.String())
+		return nil, api.NewSpineError(fErr, api.SpineOperationSubscribe, f.featureRemote.Address())
 	}
 
+	f.results.addOperation(f.remoteDevice.Ski(), msgCounter, api.SpineOperationSubscribe)
+
 	return msgCounter, nil
 }
 
 // unssubscribe to the feature of the entity
 func (f *Feature) Unsubscribe() (*model.MsgCounterType, error) {
+	f.results.beginSend()
+	defer f.results.endSend()
+
 	msgCounter, fErr := f.featureLocal.RemoveRemoteSubscription(f.featureRemote.Address())
 
 	if fErr != nil {
-		return nil, errors.New(fErr.String())
+		return nil, api.NewSpineError(fErr, api.SpineOperationUnsubscribe, f.featureRemote.Address())
 	}
 
+	f.results.addOperation(f.remoteDevice.Ski(), msgCounter, api.SpineOperationUnsubscribe)
+
 	return msgCounter, nil
 }
 
@@ -90,30 +115,158 @@ func (f *Feature) HasBinding() bool {
 
 // bind to the feature of the entity
 func (f *Feature) Bind() (*model.MsgCounterType, error) {
+	f.results.beginSend()
+	defer f.results.endSend()
+
 	msgCounter, fErr := f.featureLocal.BindToRemote(f.featureRemote.Address())
 	if fErr != nil {
-		return nil, errors.New(fErr.String())
+		return nil, api.NewSpineError(fErr, api.SpineOperationBind, f.featureRemote.Address())
 	}
 
+	f.results.addOperation(f.remoteDevice.Ski(), msgCounter, api.SpineOperationBind)
+
 	return msgCounter, nil
 }
 
 // remove a binding to the feature of the entity
 func (f *Feature) Unbind() (*model.MsgCounterType, error) {
+	f.results.beginSend()
+	defer f.results.endSend()
+
 	msgCounter, fErr := f.featureLocal.RemoveRemoteBinding(f.featureRemote.Address())
 
 	if fErr != nil {
-		return nil, errors.New(fErr.String())
+		return nil, api.NewSpineError(fErr, api.SpineOperationUnbind, f.featureRemote.Address())
 	}
 
+	f.results.addOperation(f.remoteDevice.Ski(), msgCounter, api.SpineOperationUnbind)
+
 	return msgCounter, nil
 }
 
 // add a callback function to be invoked once a result to a msgCounter came in
+//
+// the callback is also invoked if the result of a message sent by a feature
+// helper arrived before it was added, other callbacks for the same msgCounter are kept
 func (f *Feature) AddResultCallback(
 	msgCounterReference model.MsgCounterType,
 	function func(msg spineapi.ResultMessage)) {
-	f.featureLocal.AddResultCallback(msgCounterReference, function)
+	f.results.addCallback(f.remoteDevice.Ski(), msgCounterReference, function)
+}
+
+// wait for the reply to a read request of this feature and return the received data
+//
+// the data is of the list data type of the requested function, e.g.
+// *model.MeasurementListDataType, and nil if the remote entity responded
+// with a result without an error
+//
+// possible errors:
+//   - ErrMissingData if no msg counter is provided
+//   - SpineError if the remote entity responded with an error or the request timed out
+//   - the error of the context if it is done before the reply arrived
+func (f *Feature) WaitForReply(ctx context.Context, msgCounter *model.MsgCounterType) (any, error) {
+	if msgCounter == nil {
+		return nil, api.ErrMissingData
+	}
+
+	type reply struct {
+		data any
+		err  *model.ErrorType
+	}
+
+	// spine blocks until the reply arrived or the max response delay of the
+	// remote feature passed, so the goroutine always ends
+	replyCh := make(chan reply, 1)
+	go func() {
+		data, err := f.featureLocal.FetchRequestRemoteData(*msgCounter, f.featureRemote)
+		replyCh <- reply{data: data, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-replyCh:
+		if err := api.NewSpineError(result.err, api.SpineOperationRead, f.featureRemote.Address()); err != nil {
+			return nil, err
+		}
+		return result.data, nil
+	}
+}
+
+// wait for the result of a message sent by this feature, e.g. a write
+//
+// the result is also reported if it arrived before this method was invoked,
+// as long as it is one of the last results received by the local device.
+// Result callbacks added for the msg counter with AddResultCallback are kept
+//
+// possible errors:
+//   - ErrMissingData if no msg counter is provided
+//   - SpineError with the operation of the sent message if the remote entity responded with an error
+//   - the error of the context if it is done before the result arrived
+func (f *Feature) WaitForResult(ctx context.Context, msgCounter *model.MsgCounterType) error {
+	if msgCounter == nil {
+		return api.ErrMissingData
+	}
+
+	resultCh := make(chan *model.ResultDataType, 1)
+	f.AddResultCallback(*msgCounter, func(msg spineapi.ResultMessage) {
+		resultCh <- msg.Result
+	})
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case result := <-resultCh:
+		if result == nil {
+			return nil
+		}
+		operation := f.results.operation(f.remoteDevice.Ski(), *msgCounter)
+		return api.NewSpineError(model.NewErrorTypeFromResult(result), operation, f.featureRemote.Address())
+	}
+}
+
+// check if the remote feature announces partial reads of a function
+//
+// see supportsPartial for the remote features created by SPINE
+func (f *Feature) SupportsPartialRead(function model.FunctionType) bool {
+	return f.supportsPartial(function, false)
+}
+
+// check if the remote feature announces partial writes of a function
+//
+// see supportsPartial for the remote features created by SPINE
+func (f *Feature) SupportsPartialWrite(function model.FunctionType) bool {
+	return f.supportsPartial(function, true)
+}
+
+// check if the remote feature announces partial reads or writes of a function
+//
+// the partial flags are taken from the information of the remote operations.
+// The operations of the remote features created by the used SPINE version only
+// keep if a function can be read and written and drop the announced partial
+// flags, so partial reads and writes are assumed for them
+func (f *Feature) supportsPartial(function model.FunctionType, write bool) bool {
+	if f.featureRemote == nil {
+		return false
+	}
+
+	operations, exists := f.featureRemote.Operations()[function]
+	if !exists || (write && !operations.Write()) || (!write && !operations.Read()) {
+		return false
+	}
+
+	if _, ok := operations.(*spine.Operations); ok {
+		return true
+	}
+
+	info := operations.Information()
+	if info == nil {
+		return false
+	}
+	if write {
+		return info.Write != nil && info.Write.Partial != nil
+	}
+	return info.Read != nil && info.Read.Partial != nil
 }
 
 // helper method which adds checking if the feature is available and the operation is allowed
@@ -121,27 +274,98 @@ func (f *Feature) AddResultCallback(
 // model.FilterType DataSelectors (selectors) and/or DataElements (elements)
 // both should use the proper data types for the used function
 func (f *Feature) requestData(function model.FunctionType, selectors any, elements any) (*model.MsgCounterType, error) {
-	if f.featureRemote == nil {
-		return nil, api.ErrDataNotAvailable
+	if err := f.checkOperation(function, false); err != nil {
+		return nil, err
 	}
 
-	fTypes := f.featureRemote.Operations()
-	if _, exists := fTypes[function]; !exists {
-		return nil, api.ErrFunctionNotSupported
+	f.results.beginSend()
+	defer f.results.endSend()
+
+	msgCounter, fErr := f.featureLocal.RequestRemoteData(function, selectors, elements, f.featureRemote)
+	if fErr != nil {
+		return nil, api.NewSpineError(fErr, api.SpineOperationRead, f.featureRemote.Address())
 	}
 
-	if !fTypes[function].Read() {
-		return nil, api.ErrOperationOnFunctionNotSupported
+	f.results.addOperation(f.remoteDevice.Ski(), msgCounter, api.SpineOperationRead)
+
+	return msgCounter, nil
+}
+
+// helper method which adds checking if the feature is available and the read operation is allowed
+// the cmd has to contain the empty data of the function and the filter is added to the cmd,
+// e.g. a partial filter with the selectors of the function
+func (f *Feature) requestDataWithFilter(function model.FunctionType, filter *model.FilterType, cmd model.CmdType) (*model.MsgCounterType, error) {
+	if err := f.checkOperation(function, false); err != nil {
+		return nil, err
 	}
 
-	msgCounter, fErr := f.featureLocal.RequestRemoteData(function, selectors, elements, f.featureRemote)
+	cmd.Function = util.Ptr(function)
+	cmd.Filter = []model.FilterType{*filter}
+
+	f.results.beginSend()
+	defer f.results.endSend()
+
+	remoteDevice := f.featureRemote.Device()
+	msgCounter, fErr := f.featureLocal.RequestRemoteDataBySenderAddress(
+		cmd, remoteDevice.Sender(), remoteDevice.Ski(), f.featureRemote.Address(), f.featureRemote.MaxResponseDelayDuration())
 	if fErr != nil {
-		return nil, errors.New(fErr.String())
+		return nil, api.NewSpineError(fErr, api.SpineOperationRead, f.featureRemote.Address())
+	}
+
+	f.results.addOperation(f.remoteDevice.Ski(), msgCounter, api.SpineOperationRead)
+
+	return msgCounter, nil
+}
+
+// helper method which adds checking if the feature is available and the write operation is allowed
+// if a filter is provided, e.g. a partial filter with selectors, it is added to the cmd
+func (f *Feature) writeData(function model.FunctionType, filter *model.FilterType, cmd model.CmdType) (*model.MsgCounterType, error) {
+	if err := f.checkOperation(function, true); err != nil {
+		return nil, err
+	}
+
+	if filter != nil {
+		cmd.Function = util.Ptr(function)
+		cmd.Filter = []model.FilterType{*filter}
 	}
 
+	return f.sendWrite(cmd)
+}
+
+// send a write cmd to the remote feature and remember the operation for its result
+func (f *Feature) sendWrite(cmd model.CmdType) (*model.MsgCounterType, error) {
+	f.results.beginSend()
+	defer f.results.endSend()
+
+	msgCounter, err := f.remoteDevice.Sender().Write(f.featureLocal.Address(), f.featureRemote.Address(), cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	f.results.addOperation(f.remoteDevice.Ski(), msgCounter, api.SpineOperationWrite)
+
 	return msgCounter, nil
 }
 
+// return an error if the remote feature does not support the function
+// or the read or write operation on it
+func (f *Feature) checkOperation(function model.FunctionType, write bool) error {
+	if f.featureRemote == nil {
+		return api.ErrDataNotAvailable
+	}
+
+	operations, exists := f.featureRemote.Operations()[function]
+	if !exists {
+		return api.ErrFunctionNotSupported
+	}
+
+	if (write && !operations.Write()) || (!write && !operations.Read()) {
+		return api.ErrOperationOnFunctionNotSupported
+	}
+
+	return nil
+}
+
 // internal helper method for getting local and remote feature for a given featureType and a given remoteDevice
 func (f *Feature) getLocalAndRemoteFeatures() (
 	spineapi.FeatureLocalInterface,
@@ -162,3 +386,57 @@ func (f *Feature) getLocalAndRemoteFeatures() (
 
 	return featureLocal, featureRemote, nil
 }
+
+// send a read request with a method of a features helper and wait for the reply
+//
+// T has to be the list data type of the requested function, e.g.
+//
+//	data, err := features.RequestAndWait[model.MeasurementListDataType](ctx, measurement, measurement.RequestValues)
+//
+// possible errors:
+//   - the errors of the request method and of WaitForReply
+//   - ErrDataNotAvailable if the reply contains no data of type T
+func RequestAndWait[T any](
+	ctx context.Context,
+	feature FeatureInterface,
+	request func() (*model.MsgCounterType, error)) (*T, error) {
+	msgCounter, err := request()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := feature.WaitForReply(ctx, msgCounter)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := data.(*T)
+	if !ok || result == nil {
+		return nil, api.ErrDataNotAvailable
+	}
+
+	return result, nil
+}
+
+// send a write with a method of a features helper and wait for the result
+//
+// the result is dispatched by the feature helper, which registers before
+// the message is sent, so a result arriving before the wait started is reported
+//
+//	err := features.WriteAndWait(ctx, loadControl, func() (*model.MsgCounterType, error) {
+//		return loadControl.WriteLimitValues(limits)
+//	})
+//
+// possible errors:
+//   - the errors of the write method and of WaitForResult
+func WriteAndWait(
+	ctx context.Context,
+	feature FeatureInterface,
+	write func() (*model.MsgCounterType, error)) error {
+	msgCounter, err := write()
+	if err != nil {
+		return err
+	}
+
+	return feature.WaitForResult(ctx, msgCounter)
+}
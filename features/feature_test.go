@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/util"
 	shipapi "github.com/enbility/ship-go/api"
 	spineapi "github.com/enbility/spine-go/api"
 	"github.com/enbility/spine-go/model"
@@ -12,6 +13,68 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+// the selector requests and partial writes use partial filters for remote
+// features created by SPINE from a real discovery
+func TestPartialFiltersOfDiscoveredFeatures(t *testing.T) {
+	writeHandler := &WriteMessageHandler{}
+	localEntity, remoteEntity := setupDiscoveredFeatures(t, writeHandler, []featureFunctions{
+		{
+			featureType: model.FeatureTypeTypeLoadControl,
+			functions: []model.FunctionType{
+				model.FunctionTypeLoadControlLimitListData,
+			},
+		},
+		{
+			featureType: model.FeatureTypeTypeDeviceConfiguration,
+			functions: []model.FunctionType{
+				model.FunctionTypeDeviceConfigurationKeyValueListData,
+			},
+		},
+	})
+	if !assert.NotNil(t, remoteEntity) {
+		return
+	}
+
+	loadControl, err := features.NewLoadControl(localEntity, remoteEntity)
+	assert.Nil(t, err)
+	assert.True(t, loadControl.SupportsPartialRead(model.FunctionTypeLoadControlLimitListData))
+	assert.True(t, loadControl.SupportsPartialWrite(model.FunctionTypeLoadControlLimitListData))
+
+	counter, err := loadControl.RequestLimitValuesForLimitId(1)
+	assert.Nil(t, err)
+	cmd := sentCmd(t, writeHandler, counter)
+	assert.Equal(t, 1, len(cmd.Filter))
+	assert.NotNil(t, cmd.Filter[0].CmdControl.Partial)
+	assert.Equal(t, model.LoadControlLimitIdType(1), *cmd.Filter[0].LoadControlLimitListDataSelectors.LimitId)
+
+	counter, err = loadControl.WriteLimitValuesPartial([]model.LoadControlLimitDataType{
+		{
+			LimitId: util.Ptr(model.LoadControlLimitIdType(1)),
+			Value:   model.NewScaledNumberType(10),
+		},
+	})
+	assert.Nil(t, err)
+	cmd = sentCmd(t, writeHandler, counter)
+	assert.Equal(t, 1, len(cmd.Filter))
+	assert.NotNil(t, cmd.Filter[0].CmdControl.Partial)
+
+	deviceConfiguration, err := features.NewDeviceConfiguration(localEntity, remoteEntity)
+	assert.Nil(t, err)
+
+	counter, err = deviceConfiguration.WriteKeyValuesPartial([]model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				Boolean: util.Ptr(true),
+			},
+		},
+	})
+	assert.Nil(t, err)
+	cmd = sentCmd(t, writeHandler, counter)
+	assert.Equal(t, 1, len(cmd.Filter))
+	assert.NotNil(t, cmd.Filter[0].CmdControl.Partial)
+}
+
 func TestFeatureSuite(t *testing.T) {
 	suite.Run(t, new(FeatureSuite))
 }
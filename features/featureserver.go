@@ -0,0 +1,123 @@
+package features
+
+import (
+	"errors"
+	"sync"
+
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+)
+
+// FeatureServer is the base of the helpers for features where the local
+// feature role is server, e.g. to publish data of a controllable system
+//
+// The server feature is added to the local entity if it does not exist yet.
+// All data is kept on the local feature, so helpers may be created whenever
+// they are needed. Remote writes are validated before they are applied.
+type FeatureServer struct {
+	featureType model.FeatureTypeType
+
+	localEntity  spineapi.EntityLocalInterface
+	featureLocal *serverFeature
+
+	// the data lock of the local feature
+	mux *sync.Mutex
+}
+
+// Get a new server features helper
+//
+// possible errors:
+//   - the local entity is nil
+//   - the local server feature was added without a server helper
+func NewFeatureServer(
+	featureType model.FeatureTypeType,
+	localEntity spineapi.EntityLocalInterface) (*FeatureServer, error) {
+	if localEntity == nil {
+		return nil, errors.New("local entity is nil")
+	}
+
+	featureLocal, err := serverFeatureOfEntity(localEntity, featureType)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &FeatureServer{
+		featureType:  featureType,
+		localEntity:  localEntity,
+		featureLocal: featureLocal,
+		mux:          &featureLocal.dataMux,
+	}
+
+	return f, nil
+}
+
+// return the local server feature
+func (f *FeatureServer) LocalFeature() spineapi.FeatureLocalInterface {
+	return f.featureLocal
+}
+
+// add function types to the local feature, existing function types are kept
+func (f *FeatureServer) addFunctionTypes(write bool, functions ...model.FunctionType) {
+	for _, function := range functions {
+		f.featureLocal.AddFunctionType(function, true, write)
+	}
+}
+
+// return a copy of the data of a function of the local feature,
+// an empty list if no data is set
+//
+// T has to be the list data type, e.g. model.MeasurementListDataType
+func localDataCopy[T any](feature spineapi.FeatureLocalInterface, function model.FunctionType) *T {
+	if data, err := spine.LocalFeatureDataCopyOfType[*T](feature, function); err == nil && data != nil {
+		return data
+	}
+
+	return new(T)
+}
+
+// return the items with the updates applied
+//
+// items matching an update are replaced, updates without a matching item
+// are appended and all other items are kept
+func mergeItems[T any](items, updates []T, matches func(a, b T) bool) []T {
+	result := append([]T{}, items...)
+
+	for _, update := range updates {
+		replaced := false
+		for index := range result {
+			if matches(result[index], update) {
+				result[index] = update
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			result = append(result, update)
+		}
+	}
+
+	return result
+}
+
+// return the next free id, which is larger than all given ids
+func nextId[T ~uint](ids []T) T {
+	var next T
+	for _, id := range ids {
+		if id >= next {
+			next = id + 1
+		}
+	}
+
+	return next
+}
+
+// return if both optional values are unset or equal
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,38 @@
+package features_test
+
+import (
+	"testing"
+
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFeatureServer(t *testing.T) {
+	feature, err := features.NewFeatureServer(model.FeatureTypeTypeMeasurement, nil)
+	assert.NotNil(t, err)
+	assert.Nil(t, feature)
+
+	localEntity := setupLocalEntity()
+	feature, err = features.NewFeatureServer(model.FeatureTypeTypeMeasurement, localEntity)
+	assert.Nil(t, err)
+	assert.NotNil(t, feature)
+
+	local := feature.LocalFeature()
+	assert.Equal(t, local, localEntity.FeatureOfTypeAndRole(model.FeatureTypeTypeMeasurement, model.RoleTypeServer))
+
+	// the existing feature is reused
+	feature, err = features.NewFeatureServer(model.FeatureTypeTypeMeasurement, localEntity)
+	assert.Nil(t, err)
+	assert.Equal(t, local, feature.LocalFeature())
+}
+
+func TestNewFeatureServerOfPlainFeature(t *testing.T) {
+	localEntity := setupLocalEntity()
+	localEntity.GetOrAddFeature(model.FeatureTypeTypeLoadControl, model.RoleTypeServer)
+
+	// remote writes of the feature could not be validated
+	feature, err := features.NewFeatureServer(model.FeatureTypeTypeLoadControl, localEntity)
+	assert.NotNil(t, err)
+	assert.Nil(t, feature)
+}
@@ -175,3 +175,302 @@ func setupFeatures(
 
 	return localEntity, remoteEntities[0]
 }
+
+// return a local entity with client features and a remote entity whose
+// server features are created by SPINE from a NodeManagementDetailedDiscovery
+// reply, in which all functions announce partial reads and writes
+func setupDiscoveredFeatures(
+	t assert.TestingT,
+	dataCon shipapi.ShipConnectionDataWriterInterface,
+	featureFunctions []featureFunctions) (spineapi.EntityLocalInterface, spineapi.EntityRemoteInterface) {
+	localDevice := spine.NewDeviceLocal("TestBrandName", "TestDeviceModel", "TestSerialNumber", "TestDeviceCode",
+		"TestDeviceAddress", model.DeviceTypeTypeEnergyManagementSystem, model.NetworkManagementFeatureSetTypeSmart, time.Second*4)
+	localEntity := spine.NewEntityLocal(localDevice, model.EntityTypeTypeCEM, spine.NewAddressEntityType([]uint{1}))
+
+	for i, item := range featureFunctions {
+		f := spine.NewFeatureLocal(uint(i+1), localEntity, item.featureType, model.RoleTypeClient)
+		localEntity.AddFeature(f)
+	}
+
+	localDevice.AddEntity(localEntity)
+
+	remoteDeviceName := model.AddressDeviceType("remoteDevice")
+	remoteDevice := spine.NewDeviceRemote(localDevice, "test", spine.NewSender(dataCon))
+	localDevice.AddRemoteDeviceForSki("test", remoteDevice)
+
+	msgCounter, err := localDevice.RequestRemoteDetailedDiscoveryData(remoteDevice)
+	assert.Nil(t, err)
+
+	data := &model.NodeManagementDetailedDiscoveryDataType{
+		DeviceInformation: &model.NodeManagementDetailedDiscoveryDeviceInformationType{
+			Description: &model.NetworkManagementDeviceDescriptionDataType{
+				DeviceAddress: &model.DeviceAddressType{
+					Device: util.Ptr(remoteDeviceName),
+				},
+			},
+		},
+		EntityInformation: []model.NodeManagementDetailedDiscoveryEntityInformationType{
+			{
+				Description: &model.NetworkManagementEntityDescriptionDataType{
+					EntityAddress: &model.EntityAddressType{
+						Device: util.Ptr(remoteDeviceName),
+						Entity: []model.AddressEntityType{1},
+					},
+					EntityType: util.Ptr(model.EntityTypeTypeEVSE),
+				},
+			},
+		},
+	}
+
+	for i, item := range featureFunctions {
+		feature := model.NodeManagementDetailedDiscoveryFeatureInformationType{
+			Description: &model.NetworkManagementFeatureDescriptionDataType{
+				FeatureAddress: &model.FeatureAddressType{
+					Device:  util.Ptr(remoteDeviceName),
+					Entity:  []model.AddressEntityType{1},
+					Feature: util.Ptr(model.AddressFeatureType(i + 1)),
+				},
+				FeatureType: util.Ptr(item.featureType),
+				Role:        util.Ptr(model.RoleTypeServer),
+			},
+		}
+		for _, function := range item.functions {
+			feature.Description.SupportedFunction = append(feature.Description.SupportedFunction, model.FunctionPropertyType{
+				Function: util.Ptr(function),
+				PossibleOperations: &model.PossibleOperationsType{
+					Read:  &model.PossibleOperationsReadType{Partial: &model.ElementTagType{}},
+					Write: &model.PossibleOperationsWriteType{Partial: &model.ElementTagType{}},
+				},
+			})
+		}
+		data.FeatureInformation = append(data.FeatureInformation, feature)
+	}
+
+	datagram := model.Datagram{
+		Datagram: model.DatagramType{
+			Header: model.HeaderType{
+				SpecificationVersion: util.Ptr(model.SpecificationVersionType("1.3.0")),
+				AddressSource:        spine.NodeManagementAddress(util.Ptr(remoteDeviceName)),
+				AddressDestination:   localDevice.NodeManagement().Address(),
+				MsgCounter:           util.Ptr(model.MsgCounterType(1)),
+				MsgCounterReference:  msgCounter,
+				CmdClassifier:        util.Ptr(model.CmdClassifierTypeReply),
+			},
+			Payload: model.PayloadType{
+				Cmd: []model.CmdType{
+					{
+						NodeManagementDetailedDiscoveryData: data,
+					},
+				},
+			},
+		},
+	}
+	msg, jsonErr := json.Marshal(datagram)
+	assert.Nil(t, jsonErr)
+
+	_, jsonErr = remoteDevice.HandleSpineMesssage(msg)
+	assert.Nil(t, jsonErr)
+
+	return localEntity, remoteDevice.Entity([]model.AddressEntityType{1})
+}
+
+// return a wrapper of a remote entity, where the remote features announce
+// the read and write operations of all functions without partial reads and writes
+//
+// spine does not keep the partial flags of the remote feature operations
+func nonPartialRemoteEntity(remoteEntity spineapi.EntityRemoteInterface) spineapi.EntityRemoteInterface {
+	return &nonPartialEntityRemote{EntityRemoteInterface: remoteEntity}
+}
+
+type nonPartialEntityRemote struct {
+	spineapi.EntityRemoteInterface
+}
+
+func (p *nonPartialEntityRemote) Device() spineapi.DeviceRemoteInterface {
+	return &nonPartialDeviceRemote{DeviceRemoteInterface: p.EntityRemoteInterface.Device()}
+}
+
+type nonPartialDeviceRemote struct {
+	spineapi.DeviceRemoteInterface
+}
+
+func (p *nonPartialDeviceRemote) FeatureByEntityTypeAndRole(
+	entity spineapi.EntityRemoteInterface,
+	featureType model.FeatureTypeType,
+	role model.RoleType) spineapi.FeatureRemoteInterface {
+	if wrapper, ok := entity.(*nonPartialEntityRemote); ok {
+		entity = wrapper.EntityRemoteInterface
+	}
+
+	feature := p.DeviceRemoteInterface.FeatureByEntityTypeAndRole(entity, featureType, role)
+	if feature == nil {
+		return nil
+	}
+
+	return &nonPartialFeatureRemote{FeatureRemoteInterface: feature}
+}
+
+type nonPartialFeatureRemote struct {
+	spineapi.FeatureRemoteInterface
+}
+
+func (p *nonPartialFeatureRemote) Operations() map[model.FunctionType]spineapi.OperationsInterface {
+	result := make(map[model.FunctionType]spineapi.OperationsInterface)
+	for function, operations := range p.FeatureRemoteInterface.Operations() {
+		result[function] = &nonPartialOperations{OperationsInterface: operations}
+	}
+
+	return result
+}
+
+type nonPartialOperations struct {
+	spineapi.OperationsInterface
+}
+
+func (p *nonPartialOperations) Information() *model.PossibleOperationsType {
+	result := &model.PossibleOperationsType{}
+	if p.Read() {
+		result.Read = &model.PossibleOperationsReadType{}
+	}
+	if p.Write() {
+		result.Write = &model.PossibleOperationsWriteType{}
+	}
+
+	return result
+}
+
+// return a local entity without any features, used for the server feature helpers
+func setupLocalEntity() spineapi.EntityLocalInterface {
+	localDevice := spine.NewDeviceLocal("TestBrandName", "TestDeviceModel", "TestSerialNumber", "TestDeviceCode",
+		"TestDeviceAddress", model.DeviceTypeTypeChargingStation, model.NetworkManagementFeatureSetTypeSmart, time.Second*4)
+	localEntity := spine.NewEntityLocal(localDevice, model.EntityTypeTypeEVSE, spine.NewAddressEntityType([]uint{1}))
+	localDevice.AddEntity(localEntity)
+
+	return localEntity
+}
+
+// return the cmd of the message sent with the msg counter
+func sentCmd(t assert.TestingT, writeHandler *WriteMessageHandler, counter *model.MsgCounterType) *model.CmdType {
+	writeHandler.mux.Lock()
+	defer writeHandler.mux.Unlock()
+
+	for _, msg := range writeHandler.sentMessages {
+		var datagram model.Datagram
+		assert.Nil(t, json.Unmarshal(msg, &datagram))
+		if header := datagram.Datagram.Header; header.MsgCounter != nil && *header.MsgCounter == *counter {
+			return &datagram.Datagram.Payload.Cmd[0]
+		}
+	}
+
+	assert.Fail(t, "no message sent with the msg counter")
+	return nil
+}
+
+// return the cmd of a sent message
+func cmdOfMessage(t assert.TestingT, message []byte) *model.CmdType {
+	var datagram model.Datagram
+	if !assert.Nil(t, json.Unmarshal(message, &datagram)) || !assert.NotEmpty(t, datagram.Datagram.Payload.Cmd) {
+		return nil
+	}
+
+	return &datagram.Datagram.Payload.Cmd[0]
+}
+
+// let the local entity receive a cmd from the remote feature of the feature type,
+// e.g. a reply or a result to a message with the msg counter reference
+func receiveCmd(
+	t assert.TestingT,
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	featureType model.FeatureTypeType,
+	classifier model.CmdClassifierType,
+	msgCounterReference *model.MsgCounterType,
+	cmd model.CmdType) {
+	localFeature := localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient)
+	remoteFeature := remoteEntity.Device().FeatureByEntityTypeAndRole(remoteEntity, featureType, model.RoleTypeServer)
+
+	datagram := model.Datagram{
+		Datagram: model.DatagramType{
+			Header: model.HeaderType{
+				SpecificationVersion: util.Ptr(model.SpecificationVersionType("1.3.0")),
+				AddressSource:        remoteFeature.Address(),
+				AddressDestination:   localFeature.Address(),
+				MsgCounter:           util.Ptr(model.MsgCounterType(1000 + *msgCounterReference)),
+				MsgCounterReference:  msgCounterReference,
+				CmdClassifier:        util.Ptr(classifier),
+			},
+			Payload: model.PayloadType{
+				Cmd: []model.CmdType{cmd},
+			},
+		},
+	}
+
+	msg, err := json.Marshal(datagram)
+	assert.Nil(t, err)
+
+	_, err = remoteEntity.Device().HandleSpineMesssage(msg)
+	assert.Nil(t, err)
+}
+
+// let the local node management receive a result from the remote node management,
+// e.g. to a subscription or binding request with the msg counter reference
+func receiveNodeManagementResult(
+	t assert.TestingT,
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	msgCounterReference *model.MsgCounterType,
+	cmd model.CmdType) {
+	datagram := model.Datagram{
+		Datagram: model.DatagramType{
+			Header: model.HeaderType{
+				SpecificationVersion: util.Ptr(model.SpecificationVersionType("1.3.0")),
+				AddressSource:        spine.NodeManagementAddress(remoteEntity.Device().Address()),
+				AddressDestination:   localEntity.Device().NodeManagement().Address(),
+				MsgCounter:           util.Ptr(model.MsgCounterType(1000 + *msgCounterReference)),
+				MsgCounterReference:  msgCounterReference,
+				CmdClassifier:        util.Ptr(model.CmdClassifierTypeResult),
+			},
+			Payload: model.PayloadType{
+				Cmd: []model.CmdType{cmd},
+			},
+		},
+	}
+
+	msg, err := json.Marshal(datagram)
+	assert.Nil(t, err)
+
+	_, err = remoteEntity.Device().HandleSpineMesssage(msg)
+	assert.Nil(t, err)
+}
+
+// return the msg counters of the sent messages whose cmd matches
+func sentCounters(t assert.TestingT, writeHandler *WriteMessageHandler, matches func(cmd model.CmdType) bool) []model.MsgCounterType {
+	writeHandler.mux.Lock()
+	defer writeHandler.mux.Unlock()
+
+	var result []model.MsgCounterType
+	for _, msg := range writeHandler.sentMessages {
+		var datagram model.Datagram
+		assert.Nil(t, json.Unmarshal(msg, &datagram))
+		header := datagram.Datagram.Header
+		if header.MsgCounter != nil && len(datagram.Datagram.Payload.Cmd) > 0 && matches(datagram.Datagram.Payload.Cmd[0]) {
+			result = append(result, *header.MsgCounter)
+		}
+	}
+
+	return result
+}
+
+// return a result cmd with the error number
+func resultCmd(errorNumber model.ErrorNumberType, description string) model.CmdType {
+	result := &model.ResultDataType{
+		ErrorNumber: util.Ptr(errorNumber),
+	}
+	if description != "" {
+		result.Description = util.Ptr(model.DescriptionType(description))
+	}
+
+	return model.CmdType{
+		ResultData: result,
+	}
+}
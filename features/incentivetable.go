@@ -58,7 +58,7 @@ func (i *IncentiveTable) WriteValues(data []model.IncentiveTableType) (*model.Ms
 		},
 	}
 
-	return i.remoteDevice.Sender().Write(i.featureLocal.Address(), i.featureRemote.Address(), cmd)
+	return i.sendWrite(cmd)
 }
 
 // return current values for Time Series
@@ -84,7 +84,7 @@ func (i *IncentiveTable) WriteDescriptions(data []model.IncentiveTableDescriptio
 		},
 	}
 
-	return i.remoteDevice.Sender().Write(i.featureLocal.Address(), i.featureRemote.Address(), cmd)
+	return i.sendWrite(cmd)
 }
 
 // return list of descriptions
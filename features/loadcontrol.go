@@ -45,6 +45,25 @@ func (l *LoadControl) RequestLimitValues() (*model.MsgCounterType, error) {
 	return l.requestData(model.FunctionTypeLoadControlLimitListData, nil, nil)
 }
 
+// request FunctionTypeLoadControlLimitListData of a limit from a remote entity
+//
+// if the remote entity does not announce partial reads, all limits are requested
+func (l *LoadControl) RequestLimitValuesForLimitId(limitId model.LoadControlLimitIdType) (*model.MsgCounterType, error) {
+	function := model.FunctionTypeLoadControlLimitListData
+	if !l.SupportsPartialRead(function) {
+		return l.requestData(function, nil, nil)
+	}
+
+	filter := model.NewFilterTypePartial()
+	filter.LoadControlLimitListDataSelectors = &model.LoadControlLimitListDataSelectorsType{
+		LimitId: &limitId,
+	}
+	cmd := model.CmdType{
+		LoadControlLimitListData: &model.LoadControlLimitListDataType{},
+	}
+	return l.requestDataWithFilter(function, filter, cmd)
+}
+
 // returns the load control limit descriptions
 // returns an error if no description data is available yet
 func (l *LoadControl) GetLimitDescriptions() ([]model.LoadControlLimitDescriptionDataType, error) {
@@ -151,7 +170,49 @@ func (l *LoadControl) WriteLimitValues(data []model.LoadControlLimitDataType) (*
 		},
 	}
 
-	return l.remoteDevice.Sender().Write(l.featureLocal.Address(), l.featureRemote.Address(), cmd)
+	return l.sendWrite(cmd)
+}
+
+// write load control limits partially, the other limits of the remote entity are kept
+//
+// if the remote entity does not announce partial writes, the limits are
+// merged into the current limits and the full list is written
+//
+// possible errors:
+//   - ErrMissingData if no limits are provided or a limit has no limit id
+//   - ErrFunctionNotSupported if the remote entity does not support the limit list data
+//   - ErrOperationOnFunctionNotSupported if the limit list data is not writeable
+//   - and others
+func (l *LoadControl) WriteLimitValuesPartial(data []model.LoadControlLimitDataType) (*model.MsgCounterType, error) {
+	if len(data) == 0 {
+		return nil, api.ErrMissingData
+	}
+
+	for _, item := range data {
+		if item.LimitId == nil {
+			return nil, api.ErrMissingData
+		}
+	}
+
+	function := model.FunctionTypeLoadControlLimitListData
+
+	var filter *model.FilterType
+	if l.SupportsPartialWrite(function) {
+		filter = model.NewFilterTypePartial()
+	} else {
+		current, _ := l.GetLimitValues()
+		data = mergeItems(current, data, func(a, b model.LoadControlLimitDataType) bool {
+			return equalPtr(a.LimitId, b.LimitId)
+		})
+	}
+
+	cmd := model.CmdType{
+		LoadControlLimitListData: &model.LoadControlLimitListDataType{
+			LoadControlLimitData: data,
+		},
+	}
+
+	return l.writeData(function, filter, cmd)
 }
 
 // return limit data
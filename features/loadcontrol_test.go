@@ -1,8 +1,11 @@
 package features_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/enbility/eebus-go/api"
 	"github.com/enbility/eebus-go/features"
 	"github.com/enbility/eebus-go/util"
 	shipapi "github.com/enbility/ship-go/api"
@@ -72,6 +75,30 @@ func (s *LoadControlSuite) Test_RequestLimits() {
 	assert.NotNil(s.T(), counter)
 }
 
+func (s *LoadControlSuite) Test_RequestLimitValuesForLimitId() {
+	limitId := model.LoadControlLimitIdType(1)
+
+	counter, err := s.loadControl.RequestLimitValuesForLimitId(limitId)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd := cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 1, len(cmd.Filter))
+	assert.NotNil(s.T(), cmd.Filter[0].CmdControl.Partial)
+	assert.Equal(s.T(), limitId, *cmd.Filter[0].LoadControlLimitListDataSelectors.LimitId)
+	assert.NotNil(s.T(), cmd.LoadControlLimitListData)
+
+	// partial reads are not announced, so all limits are requested
+	nonPartial, err := features.NewLoadControl(s.localEntity, nonPartialRemoteEntity(s.remoteEntity))
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), nonPartial.SupportsPartialRead(model.FunctionTypeLoadControlLimitListData))
+
+	counter, err = nonPartial.RequestLimitValuesForLimitId(limitId)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd = cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 0, len(cmd.Filter))
+}
+
 func (s *LoadControlSuite) Test_GetLimitDescriptions() {
 	data, err := s.loadControl.GetLimitDescriptions()
 	assert.NotNil(s.T(), err)
@@ -167,6 +194,213 @@ func (s *LoadControlSuite) Test_WriteLimitValues() {
 	assert.NotNil(s.T(), counter)
 }
 
+func (s *LoadControlSuite) Test_WriteLimitValuesPartial() {
+	counter, err := s.loadControl.WriteLimitValuesPartial(nil)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), counter)
+
+	data := []model.LoadControlLimitDataType{
+		{
+			Value: model.NewScaledNumberType(10),
+		},
+	}
+	counter, err = s.loadControl.WriteLimitValuesPartial(data)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), counter)
+
+	s.addData()
+
+	// only the provided limits are written
+	data = []model.LoadControlLimitDataType{
+		{
+			LimitId: util.Ptr(model.LoadControlLimitIdType(1)),
+			Value:   model.NewScaledNumberType(10),
+		},
+	}
+	counter, err = s.loadControl.WriteLimitValuesPartial(data)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd := cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 1, len(cmd.Filter))
+	assert.NotNil(s.T(), cmd.Filter[0].CmdControl.Partial)
+	assert.Equal(s.T(), 1, len(cmd.LoadControlLimitListData.LoadControlLimitData))
+
+	// partial writes are not announced, so the limits are merged into the full list
+	nonPartial, err := features.NewLoadControl(s.localEntity, nonPartialRemoteEntity(s.remoteEntity))
+	assert.Nil(s.T(), err)
+
+	counter, err = nonPartial.WriteLimitValuesPartial(data)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd = cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 0, len(cmd.Filter))
+	assert.Equal(s.T(), 2, len(cmd.LoadControlLimitListData.LoadControlLimitData))
+}
+
+func (s *LoadControlSuite) Test_WriteAndWait() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	err := s.loadControl.WaitForResult(ctx, nil)
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	data := []model.LoadControlLimitDataType{
+		{
+			LimitId: util.Ptr(model.LoadControlLimitIdType(0)),
+			Value:   model.NewScaledNumberType(10),
+		},
+	}
+	write := func(result model.CmdType) func() (*model.MsgCounterType, error) {
+		return func() (*model.MsgCounterType, error) {
+			counter, err := s.loadControl.WriteLimitValues(data)
+			// the result arrives before the wait started
+			receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+				model.CmdClassifierTypeResult, counter, result)
+			return counter, err
+		}
+	}
+
+	err = features.WriteAndWait(ctx, s.loadControl, write(resultCmd(model.ErrorNumberTypeNoError, "")))
+	assert.Nil(s.T(), err)
+
+	// a result callback added for the msg counter is kept
+	callbackCh := make(chan spineapi.ResultMessage, 1)
+	counter, err := s.loadControl.WriteLimitValues(data)
+	assert.Nil(s.T(), err)
+	s.loadControl.AddResultCallback(*counter, func(msg spineapi.ResultMessage) {
+		callbackCh <- msg
+	})
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+			model.CmdClassifierTypeResult, counter, resultCmd(model.ErrorNumberTypeNoError, ""))
+	}()
+	err = s.loadControl.WaitForResult(ctx, counter)
+	assert.Nil(s.T(), err)
+	select {
+	case msg := <-callbackCh:
+		assert.Equal(s.T(), *counter, msg.MsgCounterReference)
+	case <-ctx.Done():
+		assert.Fail(s.T(), "result callback not invoked")
+	}
+
+	err = features.WriteAndWait(ctx, s.loadControl, write(resultCmd(model.ErrorNumberTypeCommandNotSupported, "")))
+	var spineErr *api.SpineError
+	assert.ErrorAs(s.T(), err, &spineErr)
+	assert.Equal(s.T(), model.ErrorNumberTypeCommandNotSupported, spineErr.ErrorNumber)
+	assert.Equal(s.T(), api.SpineOperationWrite, spineErr.Operation)
+	assert.ErrorIs(s.T(), err, api.ErrNotSupported)
+
+	err = features.WriteAndWait(ctx, s.loadControl, func() (*model.MsgCounterType, error) {
+		return s.loadControl.WriteLimitValues(nil)
+	})
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	// no result arrives before the context is done
+	shortCtx, shortCancel := context.WithTimeout(ctx, time.Millisecond*10)
+	defer shortCancel()
+
+	err = features.WriteAndWait(shortCtx, s.loadControl, func() (*model.MsgCounterType, error) {
+		return s.loadControl.WriteLimitValues(data)
+	})
+	assert.Equal(s.T(), context.DeadlineExceeded, err)
+}
+
+func (s *LoadControlSuite) Test_ResultsOfPendingMessages() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	data := []model.LoadControlLimitDataType{
+		{
+			LimitId: util.Ptr(model.LoadControlLimitIdType(0)),
+			Value:   model.NewScaledNumberType(10),
+		},
+	}
+
+	// a message waiting for its result is kept while many other results arrive
+	pending, err := s.loadControl.WriteLimitValues(data)
+	assert.Nil(s.T(), err)
+	callbackCh := make(chan spineapi.ResultMessage, 1)
+	s.loadControl.AddResultCallback(*pending, func(msg spineapi.ResultMessage) {
+		callbackCh <- msg
+	})
+	unanswered, err := s.loadControl.WriteLimitValues(data)
+	assert.Nil(s.T(), err)
+
+	for i := 0; i < 150; i++ {
+		counter, err := s.loadControl.WriteLimitValues(data)
+		assert.Nil(s.T(), err)
+		receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+			model.CmdClassifierTypeResult, counter, resultCmd(model.ErrorNumberTypeNoError, ""))
+		assert.Nil(s.T(), s.loadControl.WaitForResult(ctx, counter))
+	}
+
+	receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.CmdClassifierTypeResult, pending, resultCmd(model.ErrorNumberTypeNoError, ""))
+	select {
+	case msg := <-callbackCh:
+		assert.Equal(s.T(), *pending, msg.MsgCounterReference)
+	case <-ctx.Done():
+		assert.Fail(s.T(), "result callback not invoked")
+	}
+
+	// the operation of a message without a result yet is reported in its error
+	receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.CmdClassifierTypeResult, unanswered, resultCmd(model.ErrorNumberTypeCommandRejected, ""))
+	err = s.loadControl.WaitForResult(ctx, unanswered)
+	var spineErr *api.SpineError
+	assert.ErrorAs(s.T(), err, &spineErr)
+	assert.Equal(s.T(), api.SpineOperationWrite, spineErr.Operation)
+
+	// a result of a message not sent by a helper is not kept
+	unknown := model.MsgCounterType(10000)
+	receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.CmdClassifierTypeResult, &unknown, resultCmd(model.ErrorNumberTypeNoError, ""))
+	// the results are handled asynchronously by SPINE
+	time.Sleep(time.Millisecond * 50)
+	shortCtx, shortCancel := context.WithTimeout(ctx, time.Millisecond*10)
+	defer shortCancel()
+	err = s.loadControl.WaitForResult(shortCtx, &unknown)
+	assert.Equal(s.T(), context.DeadlineExceeded, err)
+}
+
+func (s *LoadControlSuite) Test_RemoveResults() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	data := []model.LoadControlLimitDataType{
+		{
+			LimitId: util.Ptr(model.LoadControlLimitIdType(0)),
+			Value:   model.NewScaledNumberType(10),
+		},
+	}
+
+	counter, err := s.loadControl.WriteLimitValues(data)
+	assert.Nil(s.T(), err)
+	receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.CmdClassifierTypeResult, counter, resultCmd(model.ErrorNumberTypeNoError, ""))
+	assert.Nil(s.T(), s.loadControl.WaitForResult(ctx, counter))
+
+	// the results of a removed remote device are no longer kept
+	features.RemoveRemoteDeviceResults(s.localEntity.Device(), s.remoteEntity.Device().Ski())
+	shortCtx, shortCancel := context.WithTimeout(ctx, time.Millisecond*10)
+	defer shortCancel()
+	err = s.loadControl.WaitForResult(shortCtx, counter)
+	assert.Equal(s.T(), context.DeadlineExceeded, err)
+
+	// a new helper of a removed local device gets new results
+	features.RemoveLocalDeviceResults(s.localEntity.Device())
+	loadControl, err := features.NewLoadControl(s.localEntity, s.remoteEntity)
+	assert.Nil(s.T(), err)
+	err = features.WriteAndWait(ctx, loadControl, func() (*model.MsgCounterType, error) {
+		counter, err := loadControl.WriteLimitValues(data)
+		receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+			model.CmdClassifierTypeResult, counter, resultCmd(model.ErrorNumberTypeNoError, ""))
+		return counter, err
+	})
+	assert.Nil(s.T(), err)
+}
+
 func (s *LoadControlSuite) Test_GetLimitData() {
 	data, err := s.loadControl.GetLimitValues()
 	assert.NotNil(s.T(), err)
@@ -0,0 +1,180 @@
+package features
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+type LoadControlServer struct {
+	*FeatureServer
+}
+
+// Get a new LoadControl server features helper
+//
+// - The feature on the local entity has the role server and is added if needed
+// - The limit list data may be written by remote clients. A write is rejected
+// if it adds or removes limits, changes limits which are not changeable or is
+// not approved by an approval added with AddWriteApproval.
+func NewLoadControlServer(localEntity spineapi.EntityLocalInterface) (*LoadControlServer, error) {
+	feature, err := NewFeatureServer(model.FeatureTypeTypeLoadControl, localEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &LoadControlServer{
+		FeatureServer: feature,
+	}
+	l.addFunctionTypes(false, model.FunctionTypeLoadControlLimitDescriptionListData)
+	l.addFunctionTypes(true, model.FunctionTypeLoadControlLimitListData)
+	l.featureLocal.setWriteCheck(model.FunctionTypeLoadControlLimitListData, l.checkWrite)
+
+	return l, nil
+}
+
+// add a limit description and return the assigned limit id
+//
+// a limit id set in the description is ignored
+func (l *LoadControlServer) AddLimitDescription(description model.LoadControlLimitDescriptionDataType) model.LoadControlLimitIdType {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	data := localDataCopy[model.LoadControlLimitDescriptionListDataType](
+		l.featureLocal, model.FunctionTypeLoadControlLimitDescriptionListData)
+
+	limitId := nextId(l.limitIds(data))
+	description.LimitId = util.Ptr(limitId)
+	data.LoadControlLimitDescriptionData = append(data.LoadControlLimitDescriptionData, description)
+	l.featureLocal.SetData(model.FunctionTypeLoadControlLimitDescriptionListData, data)
+
+	return limitId
+}
+
+// update the data of described limits and notify subscribers
+//
+// only the data of the given limits is replaced,
+// the data of all other limits is kept
+//
+// possible errors:
+//   - ErrMissingData if a limit has no limit id
+//   - ErrMetadataNotAvailable if a limit is not described
+func (l *LoadControlServer) UpdateLimits(limits ...model.LoadControlLimitDataType) error {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+
+	limitIds := l.limitIds(localDataCopy[model.LoadControlLimitDescriptionListDataType](
+		l.featureLocal, model.FunctionTypeLoadControlLimitDescriptionListData))
+	for _, item := range limits {
+		if item.LimitId == nil {
+			return api.ErrMissingData
+		}
+		if !slices.Contains(limitIds, *item.LimitId) {
+			return api.ErrMetadataNotAvailable
+		}
+	}
+
+	data := localDataCopy[model.LoadControlLimitListDataType](l.featureLocal, model.FunctionTypeLoadControlLimitListData)
+	data.LoadControlLimitData = mergeItems(data.LoadControlLimitData, limits, l.matches)
+	l.featureLocal.SetData(model.FunctionTypeLoadControlLimitListData, data)
+
+	return nil
+}
+
+// return the data of a limit
+//
+// possible errors:
+//   - ErrDataNotAvailable if no data for the limit is set
+func (l *LoadControlServer) GetLimitForLimitId(limitId model.LoadControlLimitIdType) (*model.LoadControlLimitDataType, error) {
+	data := localDataCopy[model.LoadControlLimitListDataType](l.featureLocal, model.FunctionTypeLoadControlLimitListData)
+	for _, item := range data.LoadControlLimitData {
+		if item.LimitId != nil && *item.LimitId == limitId {
+			return &item, nil
+		}
+	}
+
+	return nil, api.ErrDataNotAvailable
+}
+
+// add a function approving remote writes of a limit
+//
+// approve is invoked with the write message and the data of the limit
+// resulting from a write, which is rejected if any approval returns an
+// error. The approvals are kept on the local feature and are invoked
+// without the data being locked.
+func (l *LoadControlServer) AddWriteApproval(
+	limitId model.LoadControlLimitIdType,
+	approve func(message *spineapi.Message, limit model.LoadControlLimitDataType) error) {
+	l.featureLocal.addWriteApproval(model.FunctionTypeLoadControlLimitListData, uint(limitId),
+		func(message *spineapi.Message, item any) error {
+			return approve(message, item.(model.LoadControlLimitDataType))
+		})
+}
+
+// check a remote write of the limit list data
+//
+// only the data of existing limits which are changeable may be written,
+// the changeability is defined by the server only and is kept
+//
+// possible errors:
+//   - ErrDataNotAvailable if a written limit has no data
+//   - ErrNotSupported if a limit would be removed or is not changeable
+func (l *LoadControlServer) checkWrite(message *spineapi.Message, written any) (any, map[uint]any, error) {
+	current, result, err := writeResult[model.LoadControlLimitListDataType](
+		l.featureLocal, model.FunctionTypeLoadControlLimitListData, message, written)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, item := range current.LoadControlLimitData {
+		if !slices.ContainsFunc(result.LoadControlLimitData, func(a model.LoadControlLimitDataType) bool {
+			return a.LimitId != nil && l.matches(a, item)
+		}) {
+			return nil, nil, api.ErrNotSupported
+		}
+	}
+
+	items := make(map[uint]any)
+	for index, item := range result.LoadControlLimitData {
+		i := slices.IndexFunc(current.LoadControlLimitData, func(a model.LoadControlLimitDataType) bool {
+			return item.LimitId != nil && l.matches(a, item)
+		})
+		if i < 0 {
+			return nil, nil, api.ErrDataNotAvailable
+		}
+
+		existing := current.LoadControlLimitData[i]
+		item.IsLimitChangeable = existing.IsLimitChangeable
+		result.LoadControlLimitData[index] = item
+		if reflect.DeepEqual(item, existing) {
+			continue
+		}
+
+		if existing.IsLimitChangeable != nil && !*existing.IsLimitChangeable {
+			return nil, nil, api.ErrNotSupported
+		}
+
+		items[uint(*item.LimitId)] = item
+	}
+
+	return result, items, nil
+}
+
+func (l *LoadControlServer) matches(a, b model.LoadControlLimitDataType) bool {
+	return *a.LimitId == *b.LimitId
+}
+
+// return the ids of the described limits
+func (l *LoadControlServer) limitIds(data *model.LoadControlLimitDescriptionListDataType) []model.LoadControlLimitIdType {
+	var result []model.LoadControlLimitIdType
+	for _, item := range data.LoadControlLimitDescriptionData {
+		if item.LimitId != nil {
+			result = append(result, *item.LimitId)
+		}
+	}
+
+	return result
+}
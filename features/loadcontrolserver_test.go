@@ -0,0 +1,219 @@
+package features_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLoadControlServerSuite(t *testing.T) {
+	suite.Run(t, new(LoadControlServerSuite))
+}
+
+type LoadControlServerSuite struct {
+	suite.Suite
+
+	loadControl *features.LoadControlServer
+	limitId     model.LoadControlLimitIdType
+}
+
+func (s *LoadControlServerSuite) BeforeTest(suiteName, testName string) {
+	var err error
+	s.loadControl, err = features.NewLoadControlServer(setupLocalEntity())
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), s.loadControl)
+
+	s.limitId = s.loadControl.AddLimitDescription(model.LoadControlLimitDescriptionDataType{
+		LimitType:     util.Ptr(model.LoadControlLimitTypeTypeSignDependentAbsValueLimit),
+		LimitCategory: util.Ptr(model.LoadControlCategoryTypeObligation),
+	})
+}
+
+// let the local feature receive a partial remote write of a limit
+func (s *LoadControlServerSuite) write(limit model.LoadControlLimitDataType) *model.ErrorType {
+	return s.writeWithFilter(limit, model.NewFilterTypePartial())
+}
+
+func (s *LoadControlServerSuite) writeWithFilter(limit model.LoadControlLimitDataType, filter *model.FilterType) *model.ErrorType {
+	return s.loadControl.LocalFeature().HandleMessage(&spineapi.Message{
+		RequestHeader: &model.HeaderType{},
+		CmdClassifier: model.CmdClassifierTypeWrite,
+		Cmd: model.CmdType{
+			LoadControlLimitListData: &model.LoadControlLimitListDataType{
+				LoadControlLimitData: []model.LoadControlLimitDataType{limit},
+			},
+		},
+		FilterPartial: filter,
+	})
+}
+
+// let the local feature receive a remote write of the whole limit list
+func (s *LoadControlServerSuite) writeAll(limits []model.LoadControlLimitDataType) *model.ErrorType {
+	return s.loadControl.LocalFeature().HandleMessage(&spineapi.Message{
+		RequestHeader: &model.HeaderType{},
+		CmdClassifier: model.CmdClassifierTypeWrite,
+		Cmd: model.CmdType{
+			LoadControlLimitListData: &model.LoadControlLimitListDataType{
+				LoadControlLimitData: limits,
+			},
+		},
+	})
+}
+
+func (s *LoadControlServerSuite) value() float64 {
+	limit, err := s.loadControl.GetLimitForLimitId(s.limitId)
+	assert.Nil(s.T(), err)
+
+	return limit.Value.GetValue()
+}
+
+func (s *LoadControlServerSuite) Test_NewLoadControlServer() {
+	loadControl, err := features.NewLoadControlServer(nil)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), loadControl)
+}
+
+func (s *LoadControlServerSuite) Test_UpdateLimits() {
+	_, err := s.loadControl.GetLimitForLimitId(s.limitId)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	err = s.loadControl.UpdateLimits(model.LoadControlLimitDataType{})
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	err = s.loadControl.UpdateLimits(model.LoadControlLimitDataType{
+		LimitId: util.Ptr(s.limitId + 1),
+	})
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	err = s.loadControl.UpdateLimits(model.LoadControlLimitDataType{
+		LimitId:           util.Ptr(s.limitId),
+		IsLimitChangeable: util.Ptr(true),
+		IsLimitActive:     util.Ptr(false),
+		Value:             model.NewScaledNumberType(4200),
+	})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, s.value())
+}
+
+func (s *LoadControlServerSuite) Test_Write() {
+	// no data for the limit set yet
+	err := s.write(model.LoadControlLimitDataType{
+		LimitId: util.Ptr(s.limitId),
+		Value:   model.NewScaledNumberType(1000),
+	})
+	assert.NotNil(s.T(), err)
+	_, limitErr := s.loadControl.GetLimitForLimitId(s.limitId)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, limitErr)
+
+	assert.Nil(s.T(), s.loadControl.UpdateLimits(model.LoadControlLimitDataType{
+		LimitId:           util.Ptr(s.limitId),
+		IsLimitChangeable: util.Ptr(true),
+		IsLimitActive:     util.Ptr(false),
+		Value:             model.NewScaledNumberType(4200),
+	}))
+
+	var approved []model.LoadControlLimitDataType
+	s.loadControl.AddWriteApproval(s.limitId, func(message *spineapi.Message, limit model.LoadControlLimitDataType) error {
+		approved = append(approved, limit)
+		if limit.Value.GetValue() < 0 {
+			return errors.New("negative limit")
+		}
+		return nil
+	})
+
+	// a valid write, the changeability is kept
+	err = s.write(model.LoadControlLimitDataType{
+		LimitId:           util.Ptr(s.limitId),
+		IsLimitChangeable: util.Ptr(false),
+		IsLimitActive:     util.Ptr(true),
+		Value:             model.NewScaledNumberType(1000),
+	})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1000.0, s.value())
+	assert.Equal(s.T(), 1, len(approved))
+
+	limit, limitErr := s.loadControl.GetLimitForLimitId(s.limitId)
+	assert.Nil(s.T(), limitErr)
+	assert.True(s.T(), *limit.IsLimitChangeable)
+	assert.True(s.T(), *limit.IsLimitActive)
+
+	// a write without filters replaces the whole list, so a subset is rejected
+	otherId := s.loadControl.AddLimitDescription(model.LoadControlLimitDescriptionDataType{})
+	assert.Nil(s.T(), s.loadControl.UpdateLimits(model.LoadControlLimitDataType{
+		LimitId: util.Ptr(otherId),
+		Value:   model.NewScaledNumberType(100),
+	}))
+	err = s.writeWithFilter(model.LoadControlLimitDataType{
+		LimitId:       util.Ptr(s.limitId),
+		IsLimitActive: util.Ptr(true),
+		Value:         model.NewScaledNumberType(2000),
+	}, nil)
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), model.ErrorNumberTypeCommandRejected, err.ErrorNumber)
+	assert.Equal(s.T(), 1000.0, s.value())
+	other, limitErr := s.loadControl.GetLimitForLimitId(otherId)
+	assert.Nil(s.T(), limitErr)
+	assert.Equal(s.T(), 100.0, other.Value.GetValue())
+
+	// a write without filters containing all limits is applied
+	err = s.writeAll([]model.LoadControlLimitDataType{
+		{
+			LimitId:       util.Ptr(s.limitId),
+			IsLimitActive: util.Ptr(true),
+			Value:         model.NewScaledNumberType(1000),
+		},
+		{
+			LimitId: util.Ptr(otherId),
+			Value:   model.NewScaledNumberType(200),
+		},
+	})
+	assert.Nil(s.T(), err)
+	other, limitErr = s.loadControl.GetLimitForLimitId(otherId)
+	assert.Nil(s.T(), limitErr)
+	assert.Equal(s.T(), 200.0, other.Value.GetValue())
+
+	// a write rejected by the approval is not applied
+	err = s.write(model.LoadControlLimitDataType{
+		LimitId: util.Ptr(s.limitId),
+		Value:   model.NewScaledNumberType(-1),
+	})
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), model.ErrorNumberTypeCommandRejected, err.ErrorNumber)
+	assert.Equal(s.T(), 1000.0, s.value())
+
+	// a write of an unknown limit is not applied
+	err = s.write(model.LoadControlLimitDataType{
+		LimitId: util.Ptr(otherId + 1),
+		Value:   model.NewScaledNumberType(2000),
+	})
+	assert.NotNil(s.T(), err)
+
+	// a write of a non changeable limit is not applied
+	assert.Nil(s.T(), s.loadControl.UpdateLimits(model.LoadControlLimitDataType{
+		LimitId:           util.Ptr(s.limitId),
+		IsLimitChangeable: util.Ptr(false),
+		Value:             model.NewScaledNumberType(1000),
+	}))
+
+	err = s.write(model.LoadControlLimitDataType{
+		LimitId: util.Ptr(s.limitId),
+		Value:   model.NewScaledNumberType(2000),
+	})
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), 1000.0, s.value())
+	assert.Equal(s.T(), 2, len(approved))
+
+	// the data is kept on the local feature, so a new helper returns it
+	loadControl, newErr := features.NewLoadControlServer(s.loadControl.LocalFeature().Entity())
+	assert.Nil(s.T(), newErr)
+	limit, limitErr = loadControl.GetLimitForLimitId(s.limitId)
+	assert.Nil(s.T(), limitErr)
+	assert.Equal(s.T(), 1000.0, limit.Value.GetValue())
+}
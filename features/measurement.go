@@ -45,6 +45,25 @@ func (m *Measurement) RequestValues() (*model.MsgCounterType, error) {
 	return m.requestData(model.FunctionTypeMeasurementListData, nil, nil)
 }
 
+// request FunctionTypeMeasurementListData of a measurement from a remote entity
+//
+// if the remote entity does not announce partial reads, all values are requested
+func (m *Measurement) RequestValuesForMeasurementId(measurementId model.MeasurementIdType) (*model.MsgCounterType, error) {
+	function := model.FunctionTypeMeasurementListData
+	if !m.SupportsPartialRead(function) {
+		return m.requestData(function, nil, nil)
+	}
+
+	filter := model.NewFilterTypePartial()
+	filter.MeasurementListDataSelectors = &model.MeasurementListDataSelectorsType{
+		MeasurementId: &measurementId,
+	}
+	cmd := model.CmdType{
+		MeasurementListData: &model.MeasurementListDataType{},
+	}
+	return m.requestDataWithFilter(function, filter, cmd)
+}
+
 // return list of descriptions
 func (m *Measurement) GetDescriptions() ([]model.MeasurementDescriptionDataType, error) {
 	data, err := spine.RemoteFeatureDataCopyOfType[*model.MeasurementDescriptionListDataType](m.featureRemote, model.FunctionTypeMeasurementDescriptionListData)
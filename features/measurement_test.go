@@ -1,9 +1,11 @@
 package features_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/enbility/eebus-go/api"
 	"github.com/enbility/eebus-go/features"
 	"github.com/enbility/eebus-go/util"
 	shipapi "github.com/enbility/ship-go/api"
@@ -81,6 +83,82 @@ func (s *MeasurementSuite) Test_RequestValues() {
 	assert.NotNil(s.T(), counter)
 }
 
+func (s *MeasurementSuite) Test_RequestValuesForMeasurementId() {
+	measurementId := model.MeasurementIdType(1)
+
+	counter, err := s.measurement.RequestValuesForMeasurementId(measurementId)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd := cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 1, len(cmd.Filter))
+	assert.NotNil(s.T(), cmd.Filter[0].CmdControl.Partial)
+	assert.Equal(s.T(), measurementId, *cmd.Filter[0].MeasurementListDataSelectors.MeasurementId)
+
+	// partial reads are not announced, so all values are requested
+	nonPartial, err := features.NewMeasurement(s.localEntity, nonPartialRemoteEntity(s.remoteEntity))
+	assert.Nil(s.T(), err)
+
+	counter, err = nonPartial.RequestValuesForMeasurementId(measurementId)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd = cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 0, len(cmd.Filter))
+}
+
+func (s *MeasurementSuite) Test_RequestAndWait() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+
+	data, err := s.measurement.WaitForReply(ctx, nil)
+	assert.Equal(s.T(), api.ErrMissingData, err)
+	assert.Nil(s.T(), data)
+
+	reply := model.CmdType{
+		MeasurementListData: &model.MeasurementListDataType{
+			MeasurementData: []model.MeasurementDataType{
+				{
+					MeasurementId: util.Ptr(model.MeasurementIdType(0)),
+					Value:         model.NewScaledNumberType(9),
+				},
+			},
+		},
+	}
+	values, err := features.RequestAndWait[model.MeasurementListDataType](ctx, s.measurement,
+		func() (*model.MsgCounterType, error) {
+			counter, err := s.measurement.RequestValues()
+			go receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeMeasurement,
+				model.CmdClassifierTypeReply, counter, reply)
+			return counter, err
+		})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(values.MeasurementData))
+	assert.Equal(s.T(), 9.0, values.MeasurementData[0].Value.GetValue())
+
+	values, err = features.RequestAndWait[model.MeasurementListDataType](ctx, s.measurement,
+		func() (*model.MsgCounterType, error) {
+			counter, err := s.measurement.RequestValues()
+			go receiveCmd(s.T(), s.localEntity, s.remoteEntity, model.FeatureTypeTypeMeasurement,
+				model.CmdClassifierTypeResult, counter, resultCmd(model.ErrorNumberTypeCommandRejected, "rejected"))
+			return counter, err
+		})
+	assert.Nil(s.T(), values)
+	var spineErr *api.SpineError
+	assert.ErrorAs(s.T(), err, &spineErr)
+	assert.Equal(s.T(), model.ErrorNumberTypeCommandRejected, spineErr.ErrorNumber)
+	assert.Equal(s.T(), "rejected", spineErr.Description)
+	assert.Equal(s.T(), api.SpineOperationRead, spineErr.Operation)
+	assert.NotNil(s.T(), spineErr.Address)
+	assert.ErrorIs(s.T(), err, api.ErrCommandRejected)
+
+	// no reply arrives before the context is done
+	shortCtx, shortCancel := context.WithTimeout(ctx, time.Millisecond*10)
+	defer shortCancel()
+
+	values, err = features.RequestAndWait[model.MeasurementListDataType](shortCtx, s.measurement, s.measurement.RequestValues)
+	assert.Equal(s.T(), context.DeadlineExceeded, err)
+	assert.Nil(s.T(), values)
+}
+
 func (s *MeasurementSuite) Test_GetValueForMeasurementId() {
 	measurement := model.MeasurementIdType(0)
 
@@ -0,0 +1,164 @@
+package features
+
+import (
+	"slices"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+type MeasurementServer struct {
+	*FeatureServer
+}
+
+// Get a new Measurement server features helper
+//
+// - The feature on the local entity has the role server and is added if needed
+// - All functions are read only for remote clients
+func NewMeasurementServer(localEntity spineapi.EntityLocalInterface) (*MeasurementServer, error) {
+	feature, err := NewFeatureServer(model.FeatureTypeTypeMeasurement, localEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MeasurementServer{
+		FeatureServer: feature,
+	}
+	m.addFunctionTypes(false,
+		model.FunctionTypeMeasurementDescriptionListData,
+		model.FunctionTypeMeasurementConstraintsListData,
+		model.FunctionTypeMeasurementListData,
+	)
+
+	return m, nil
+}
+
+// add a measurement description and return the assigned measurement id
+//
+// a measurement id set in the description is ignored
+func (m *MeasurementServer) AddDescription(description model.MeasurementDescriptionDataType) model.MeasurementIdType {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	data := localDataCopy[model.MeasurementDescriptionListDataType](
+		m.featureLocal, model.FunctionTypeMeasurementDescriptionListData)
+
+	measurementId := nextId(m.measurementIds(data))
+	description.MeasurementId = util.Ptr(measurementId)
+	data.MeasurementDescriptionData = append(data.MeasurementDescriptionData, description)
+	m.featureLocal.SetData(model.FunctionTypeMeasurementDescriptionListData, data)
+
+	return measurementId
+}
+
+// return the measurement descriptions
+func (m *MeasurementServer) GetDescriptions() []model.MeasurementDescriptionDataType {
+	data := localDataCopy[model.MeasurementDescriptionListDataType](
+		m.featureLocal, model.FunctionTypeMeasurementDescriptionListData)
+
+	return data.MeasurementDescriptionData
+}
+
+// update the constraints of described measurements and notify subscribers
+//
+// only the constraints of the given measurements are replaced,
+// the constraints of all other measurements are kept
+//
+// possible errors:
+//   - ErrMissingData if a constraint has no measurement id
+//   - ErrMetadataNotAvailable if a measurement id has no description
+func (m *MeasurementServer) UpdateConstraints(constraints ...model.MeasurementConstraintsDataType) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for _, item := range constraints {
+		if err := m.checkMeasurementId(item.MeasurementId); err != nil {
+			return err
+		}
+	}
+
+	data := localDataCopy[model.MeasurementConstraintsListDataType](
+		m.featureLocal, model.FunctionTypeMeasurementConstraintsListData)
+	data.MeasurementConstraintsData = mergeItems(data.MeasurementConstraintsData, constraints,
+		func(a, b model.MeasurementConstraintsDataType) bool {
+			return *a.MeasurementId == *b.MeasurementId
+		})
+	m.featureLocal.SetData(model.FunctionTypeMeasurementConstraintsListData, data)
+
+	return nil
+}
+
+// update the values of described measurements and notify subscribers
+//
+// only the values of the given measurements are replaced,
+// the values of all other measurements are kept
+//
+// possible errors:
+//   - ErrMissingData if a value has no measurement id
+//   - ErrMetadataNotAvailable if a measurement id has no description
+func (m *MeasurementServer) UpdateValues(values ...model.MeasurementDataType) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for _, item := range values {
+		if err := m.checkMeasurementId(item.MeasurementId); err != nil {
+			return err
+		}
+	}
+
+	data := localDataCopy[model.MeasurementListDataType](
+		m.featureLocal, model.FunctionTypeMeasurementListData)
+	data.MeasurementData = mergeItems(data.MeasurementData, values,
+		func(a, b model.MeasurementDataType) bool {
+			return *a.MeasurementId == *b.MeasurementId && equalPtr(a.ValueType, b.ValueType)
+		})
+	m.featureLocal.SetData(model.FunctionTypeMeasurementListData, data)
+
+	return nil
+}
+
+// return the value of a measurement
+//
+// possible errors:
+//   - ErrDataNotAvailable if no value for the measurement is set
+func (m *MeasurementServer) GetValueForMeasurementId(measurementId model.MeasurementIdType) (*model.MeasurementDataType, error) {
+	data := localDataCopy[model.MeasurementListDataType](
+		m.featureLocal, model.FunctionTypeMeasurementListData)
+
+	for _, item := range data.MeasurementData {
+		if item.MeasurementId != nil && *item.MeasurementId == measurementId {
+			return &item, nil
+		}
+	}
+
+	return nil, api.ErrDataNotAvailable
+}
+
+// return an error if the measurement id is missing or not described
+func (m *MeasurementServer) checkMeasurementId(measurementId *model.MeasurementIdType) error {
+	if measurementId == nil {
+		return api.ErrMissingData
+	}
+
+	data := localDataCopy[model.MeasurementDescriptionListDataType](
+		m.featureLocal, model.FunctionTypeMeasurementDescriptionListData)
+	if !slices.Contains(m.measurementIds(data), *measurementId) {
+		return api.ErrMetadataNotAvailable
+	}
+
+	return nil
+}
+
+// return the ids of the described measurements
+func (m *MeasurementServer) measurementIds(data *model.MeasurementDescriptionListDataType) []model.MeasurementIdType {
+	var result []model.MeasurementIdType
+	for _, item := range data.MeasurementDescriptionData {
+		if item.MeasurementId != nil {
+			result = append(result, *item.MeasurementId)
+		}
+	}
+
+	return result
+}
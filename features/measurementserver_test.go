@@ -0,0 +1,123 @@
+package features_test
+
+import (
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/util"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMeasurementServerSuite(t *testing.T) {
+	suite.Run(t, new(MeasurementServerSuite))
+}
+
+type MeasurementServerSuite struct {
+	suite.Suite
+
+	measurement *features.MeasurementServer
+}
+
+func (s *MeasurementServerSuite) BeforeTest(suiteName, testName string) {
+	var err error
+	s.measurement, err = features.NewMeasurementServer(setupLocalEntity())
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), s.measurement)
+}
+
+func (s *MeasurementServerSuite) Test_NewMeasurementServer() {
+	measurement, err := features.NewMeasurementServer(nil)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), measurement)
+
+	functions := s.measurement.LocalFeature().Functions()
+	assert.Contains(s.T(), functions, model.FunctionTypeMeasurementDescriptionListData)
+	assert.Contains(s.T(), functions, model.FunctionTypeMeasurementConstraintsListData)
+	assert.Contains(s.T(), functions, model.FunctionTypeMeasurementListData)
+}
+
+func (s *MeasurementServerSuite) Test_AddDescription() {
+	id := s.measurement.AddDescription(model.MeasurementDescriptionDataType{
+		MeasurementId:   util.Ptr(model.MeasurementIdType(10)),
+		MeasurementType: util.Ptr(model.MeasurementTypeTypePower),
+	})
+	assert.Equal(s.T(), model.MeasurementIdType(0), id)
+
+	id = s.measurement.AddDescription(model.MeasurementDescriptionDataType{
+		MeasurementType: util.Ptr(model.MeasurementTypeTypeCurrent),
+	})
+	assert.Equal(s.T(), model.MeasurementIdType(1), id)
+
+	descriptions := s.measurement.GetDescriptions()
+	assert.Equal(s.T(), 2, len(descriptions))
+	assert.Equal(s.T(), model.MeasurementIdType(1), *descriptions[1].MeasurementId)
+}
+
+func (s *MeasurementServerSuite) Test_UpdateValues() {
+	err := s.measurement.UpdateValues(model.MeasurementDataType{
+		Value: model.NewScaledNumberType(1),
+	})
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	err = s.measurement.UpdateValues(model.MeasurementDataType{
+		MeasurementId: util.Ptr(model.MeasurementIdType(0)),
+		Value:         model.NewScaledNumberType(1),
+	})
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	power := s.measurement.AddDescription(model.MeasurementDescriptionDataType{})
+	current := s.measurement.AddDescription(model.MeasurementDescriptionDataType{})
+
+	err = s.measurement.UpdateValues(
+		model.MeasurementDataType{MeasurementId: util.Ptr(power), Value: model.NewScaledNumberType(1000)},
+		model.MeasurementDataType{MeasurementId: util.Ptr(current), Value: model.NewScaledNumberType(4)},
+	)
+	assert.Nil(s.T(), err)
+
+	// partial update, the current is kept
+	err = s.measurement.UpdateValues(
+		model.MeasurementDataType{MeasurementId: util.Ptr(power), Value: model.NewScaledNumberType(2000)},
+	)
+	assert.Nil(s.T(), err)
+
+	data, err := spine.LocalFeatureDataCopyOfType[*model.MeasurementListDataType](
+		s.measurement.LocalFeature(), model.FunctionTypeMeasurementListData)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, len(data.MeasurementData))
+
+	value, err := s.measurement.GetValueForMeasurementId(power)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2000.0, value.Value.GetValue())
+
+	value, err = s.measurement.GetValueForMeasurementId(current)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4.0, value.Value.GetValue())
+
+	_, err = s.measurement.GetValueForMeasurementId(model.MeasurementIdType(100))
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+}
+
+func (s *MeasurementServerSuite) Test_UpdateConstraints() {
+	err := s.measurement.UpdateConstraints(model.MeasurementConstraintsDataType{
+		MeasurementId: util.Ptr(model.MeasurementIdType(0)),
+	})
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	id := s.measurement.AddDescription(model.MeasurementDescriptionDataType{})
+	err = s.measurement.UpdateConstraints(model.MeasurementConstraintsDataType{
+		MeasurementId: util.Ptr(id),
+		ValueRangeMin: model.NewScaledNumberType(0),
+		ValueRangeMax: model.NewScaledNumberType(11000),
+	})
+	assert.Nil(s.T(), err)
+
+	data, err := spine.LocalFeatureDataCopyOfType[*model.MeasurementConstraintsListDataType](
+		s.measurement.LocalFeature(), model.FunctionTypeMeasurementConstraintsListData)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(data.MeasurementConstraintsData))
+	assert.Equal(s.T(), 11000.0, data.MeasurementConstraintsData[0].ValueRangeMax.GetValue())
+}
@@ -0,0 +1,254 @@
+package features
+
+import (
+	"sync"
+
+	"github.com/enbility/eebus-go/api"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the number of messages per local device for which the results are kept,
+// messages still waiting for their result are always kept
+const maxResultEntries = 100
+
+type resultKey struct {
+	ski        string
+	msgCounter model.MsgCounterType
+}
+
+type resultEntry struct {
+	operation api.SpineOperation
+	result    *spineapi.ResultMessage
+	callbacks []func(msg spineapi.ResultMessage)
+}
+
+// dispatches the results of the messages sent to remote devices to any
+// number of callbacks per msg counter
+//
+// SPINE only keeps one callback per msg counter and drops results arriving
+// before a callback was added. The dispatcher is added as a result handler to
+// the local features before any message is sent and keeps the last results of
+// the messages sent by the feature helpers, so callbacks added after the
+// result arrived are invoked as well. Results of other messages are ignored.
+//
+// one dispatcher is used per local device, as the msg counters are unique per remote device.
+// The dispatcher of a local device is kept until RemoveLocalDeviceResults is invoked
+type resultDispatcher struct {
+	features map[spineapi.FeatureLocalInterface]struct{}
+
+	entries map[resultKey]*resultEntry
+	order   []resultKey
+
+	// the number of messages being sent, whose operations are not added yet,
+	// results are handled once all of them are added
+	sending int
+	sent    *sync.Cond
+
+	mux sync.Mutex
+}
+
+var (
+	resultDispatchers    = make(map[spineapi.DeviceLocalInterface]*resultDispatcher)
+	muxResultDispatchers sync.Mutex
+)
+
+var _ spineapi.FeatureResultInterface = (*resultDispatcher)(nil)
+
+// return the result dispatcher of a local device
+func resultDispatcherForDevice(device spineapi.DeviceLocalInterface) *resultDispatcher {
+	muxResultDispatchers.Lock()
+	defer muxResultDispatchers.Unlock()
+
+	dispatcher, ok := resultDispatchers[device]
+	if !ok {
+		dispatcher = &resultDispatcher{
+			features: make(map[spineapi.FeatureLocalInterface]struct{}),
+			entries:  make(map[resultKey]*resultEntry),
+		}
+		dispatcher.sent = sync.NewCond(&dispatcher.mux)
+		resultDispatchers[device] = dispatcher
+	}
+
+	return dispatcher
+}
+
+// RemoveLocalDeviceResults removes the kept results and result callbacks
+// of the messages sent by the feature helpers of a local device
+//
+// has to be invoked once the local device is no longer used, e.g. on shutdown
+func RemoveLocalDeviceResults(device spineapi.DeviceLocalInterface) {
+	muxResultDispatchers.Lock()
+	defer muxResultDispatchers.Unlock()
+
+	delete(resultDispatchers, device)
+}
+
+// RemoveRemoteDeviceResults removes the kept results and result callbacks
+// of the messages sent by the feature helpers of a local device to a remote device
+//
+// has to be invoked once the remote device is removed
+func RemoveRemoteDeviceResults(device spineapi.DeviceLocalInterface, ski string) {
+	muxResultDispatchers.Lock()
+	dispatcher, ok := resultDispatchers[device]
+	muxResultDispatchers.Unlock()
+
+	if ok {
+		dispatcher.removeSki(ski)
+	}
+}
+
+// add the dispatcher as result handler to a local feature, if it is not added yet
+func (r *resultDispatcher) addFeature(feature spineapi.FeatureLocalInterface) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if _, ok := r.features[feature]; ok {
+		return
+	}
+
+	r.features[feature] = struct{}{}
+	feature.AddResultHandler(r)
+}
+
+// has to be invoked before a message is sent and endSend once its operation
+// is added, as the result may arrive before the msg counter is known
+func (r *resultDispatcher) beginSend() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.sending++
+}
+
+// has to be invoked once the operation of a sent message is added
+// or sending the message failed
+func (r *resultDispatcher) endSend() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.sending--
+	if r.sending == 0 {
+		r.sent.Broadcast()
+	}
+}
+
+// remember the operation of a sent message, which is reported in the errors of its result
+func (r *resultDispatcher) addOperation(ski string, msgCounter *model.MsgCounterType, operation api.SpineOperation) {
+	if msgCounter == nil {
+		return
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.entry(resultKey{ski: ski, msgCounter: *msgCounter}).operation = operation
+}
+
+// add a callback function to be invoked once the result of a message came in
+//
+// the callback is invoked right away if the result already arrived,
+// other callbacks of the same message are kept
+func (r *resultDispatcher) addCallback(
+	ski string,
+	msgCounter model.MsgCounterType,
+	function func(msg spineapi.ResultMessage)) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	entry := r.entry(resultKey{ski: ski, msgCounter: msgCounter})
+	if entry.result != nil {
+		go function(*entry.result)
+		return
+	}
+
+	entry.callbacks = append(entry.callbacks, function)
+}
+
+// return the operation of a sent message, empty if it is unknown
+func (r *resultDispatcher) operation(ski string, msgCounter model.MsgCounterType) api.SpineOperation {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if entry, ok := r.entries[resultKey{ski: ski, msgCounter: msgCounter}]; ok {
+		return entry.operation
+	}
+
+	return ""
+}
+
+// HandleResult implements spineapi.FeatureResultInterface
+func (r *resultDispatcher) HandleResult(msg spineapi.ResultMessage) {
+	if msg.DeviceRemote == nil {
+		return
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	// the result may belong to a message whose operation is not added yet
+	for r.sending > 0 {
+		r.sent.Wait()
+	}
+
+	// only messages sent by the helpers or with added callbacks have an entry
+	entry, ok := r.entries[resultKey{ski: msg.DeviceRemote.Ski(), msgCounter: msg.MsgCounterReference}]
+	if !ok || entry.result != nil {
+		return
+	}
+
+	entry.result = &msg
+	for _, function := range entry.callbacks {
+		go function(msg)
+	}
+	entry.callbacks = nil
+}
+
+// return the entry of a message and create it if needed,
+// the oldest entry with a result is removed if there are too many
+//
+// has to be invoked with the mutex being locked
+func (r *resultDispatcher) entry(key resultKey) *resultEntry {
+	if entry, ok := r.entries[key]; ok {
+		return entry
+	}
+
+	if len(r.order) >= maxResultEntries {
+		r.removeOldestResult()
+	}
+
+	entry := &resultEntry{}
+	r.entries[key] = entry
+	r.order = append(r.order, key)
+
+	return entry
+}
+
+// remove the oldest entry which already has a result,
+// entries still waiting for their result are kept
+//
+// has to be invoked with the mutex being locked
+func (r *resultDispatcher) removeOldestResult() {
+	for i, key := range r.order {
+		if entry := r.entries[key]; entry.result != nil && len(entry.callbacks) == 0 {
+			delete(r.entries, key)
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// remove the entries of the messages sent to a remote device
+func (r *resultDispatcher) removeSki(ski string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	order := r.order[:0]
+	for _, key := range r.order {
+		if key.ski == ski {
+			delete(r.entries, key)
+			continue
+		}
+		order = append(order, key)
+	}
+	r.order = order
+}
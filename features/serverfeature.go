@@ -0,0 +1,220 @@
+package features
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+	spineutil "github.com/enbility/spine-go/util"
+)
+
+// checks a remote write of a function of a local server feature
+//
+// returns the data to be set on the local feature and the changed items
+// by their id, which are passed to the write approvals of the items
+type writeCheck func(message *spineapi.Message, written any) (data any, items map[uint]any, err error)
+
+// approves the data of a written item, the item has the type of the list items
+type writeApproval func(message *spineapi.Message, item any) error
+
+// a local server feature, which validates remote writes before they are applied
+//
+// SPINE applies remote writes to the local data and answers them successfully
+// without any validation. For functions with a write check, a write is applied
+// to a copy of the local data first. The local data is only updated and
+// subscribers are notified if the check and all approvals of the changed items
+// accept the write, otherwise the write is answered with an error result.
+//
+// The checks and approvals are kept with the feature, so any number of helpers
+// may be created for it
+type serverFeature struct {
+	*spine.FeatureLocal
+
+	checks    map[model.FunctionType]writeCheck
+	approvals map[model.FunctionType]map[uint][]writeApproval
+
+	// locks the data of the feature while it is updated by helpers or remote writes
+	dataMux sync.Mutex
+
+	mux sync.Mutex
+}
+
+var _ spineapi.FeatureLocalInterface = (*serverFeature)(nil)
+
+// locks adding server features, so each one is only added once
+var muxServerFeatures sync.Mutex
+
+// return the server feature of a type of the local entity and add it if needed
+//
+// an error is returned if the feature was added without a server helper,
+// as remote writes could not be validated
+func serverFeatureOfEntity(localEntity spineapi.EntityLocalInterface, featureType model.FeatureTypeType) (*serverFeature, error) {
+	muxServerFeatures.Lock()
+	defer muxServerFeatures.Unlock()
+
+	switch feature := localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeServer).(type) {
+	case nil:
+	case *serverFeature:
+		return feature, nil
+	default:
+		return nil, errors.New("the local server feature was not added by a server helper")
+	}
+
+	feature := &serverFeature{
+		FeatureLocal: spine.NewFeatureLocal(localEntity.NextFeatureId(), localEntity, featureType, model.RoleTypeServer),
+		checks:       make(map[model.FunctionType]writeCheck),
+		approvals:    make(map[model.FunctionType]map[uint][]writeApproval),
+	}
+	feature.SetDescriptionString(string(featureType) + " Server")
+	localEntity.AddFeature(feature)
+
+	return feature, nil
+}
+
+// set the check of remote writes of a function, an existing check is replaced
+func (f *serverFeature) setWriteCheck(function model.FunctionType, check writeCheck) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	f.checks[function] = check
+}
+
+// add an approval of remote writes of an item of a function
+func (f *serverFeature) addWriteApproval(function model.FunctionType, id uint, approval writeApproval) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	if f.approvals[function] == nil {
+		f.approvals[function] = make(map[uint][]writeApproval)
+	}
+	f.approvals[function][id] = append(f.approvals[function][id], approval)
+}
+
+func (f *serverFeature) writeCheck(function model.FunctionType) writeCheck {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	return f.checks[function]
+}
+
+func (f *serverFeature) writeApprovals(function model.FunctionType, id uint) []writeApproval {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	return append([]writeApproval{}, f.approvals[function][id]...)
+}
+
+// HandleMessage implements spineapi.FeatureLocalInterface
+//
+// remote writes of functions with a write check are validated and applied,
+// all other messages are handled by SPINE
+func (f *serverFeature) HandleMessage(message *spineapi.Message) *model.ErrorType {
+	if message.CmdClassifier != model.CmdClassifierTypeWrite || message.Cmd.ResultData != nil {
+		return f.FeatureLocal.HandleMessage(message)
+	}
+
+	cmdData, err := message.Cmd.Data()
+	if err != nil || cmdData.Function == nil {
+		return f.FeatureLocal.HandleMessage(message)
+	}
+
+	check := f.writeCheck(*cmdData.Function)
+	if check == nil {
+		return f.FeatureLocal.HandleMessage(message)
+	}
+
+	if err := f.handleWrite(*cmdData.Function, check, message, cmdData.Value); err != nil {
+		return model.NewErrorType(model.ErrorNumberTypeCommandRejected, err.Error())
+	}
+
+	return nil
+}
+
+// validate and apply a remote write
+//
+// the approvals are invoked without the data being locked, so they may use
+// the helpers. The write is checked again when it is applied, as the data
+// may have been updated in the meantime.
+func (f *serverFeature) handleWrite(function model.FunctionType, check writeCheck, message *spineapi.Message, written any) error {
+	_, items, err := check(message, written)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	for id, item := range items {
+		for _, approve := range f.writeApprovals(function, id) {
+			if err := approve(message, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	f.dataMux.Lock()
+	data, _, err := check(message, written)
+	if err == nil {
+		f.SetData(function, data)
+	}
+	f.dataMux.Unlock()
+	if err != nil {
+		return err
+	}
+
+	payload := spineapi.EventPayload{
+		EventType:     spineapi.EventTypeDataChange,
+		ChangeType:    spineapi.ElementChangeUpdate,
+		Feature:       message.FeatureRemote,
+		Device:        message.DeviceRemote,
+		Entity:        message.EntityRemote,
+		LocalFeature:  f,
+		Function:      function,
+		CmdClassifier: util.Ptr(model.CmdClassifierTypeWrite),
+		Data:          written,
+	}
+	if message.DeviceRemote != nil {
+		payload.Ski = message.DeviceRemote.Ski()
+	}
+	spine.Events.Publish(payload)
+
+	return nil
+}
+
+// return the current data of a function and the data resulting from applying
+// a remote write to a copy of it, the same way SPINE applies writes
+//
+// writes without filters replace the whole list, so they have to contain all items
+//
+// T has to be the list data type, e.g. model.LoadControlLimitListDataType
+func writeResult[T any](
+	feature spineapi.FeatureLocalInterface,
+	function model.FunctionType,
+	message *spineapi.Message,
+	written any) (current, result *T, err error) {
+	data, ok := written.(*T)
+	if !ok {
+		return nil, nil, errors.New("invalid data")
+	}
+
+	current = localDataCopy[T](feature, function)
+
+	// the list items are shared with the local data, so a deep copy is updated
+	fctData := spine.NewFunctionData[T](function)
+	copied := new(T)
+	spineutil.DeepCopy(current, copied)
+	_ = fctData.UpdateData(copied, nil, nil)
+	if err := fctData.UpdateData(data, message.FilterPartial, message.FilterDelete); err != nil {
+		return nil, nil, errors.New(err.String())
+	}
+
+	result = fctData.DataCopy()
+	if result == nil {
+		result = new(T)
+	}
+
+	return current, result, nil
+}
@@ -0,0 +1,378 @@
+package features
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the default interval for retrying failed subscriptions and bindings
+const defaultSubscriptionRetryInterval = 10 * time.Second
+
+// SubscriptionManager keeps the subscriptions and bindings of a local entity
+// to the server features of remote entities
+//
+// The desired feature types are defined per remote entity type. Once a remote
+// entity of such a type appears, the client features are subscribed and bound.
+// Requests failing to be sent or rejected by the remote entity are retried
+// until they succeed or the remote entity is removed. As a reconnected remote
+// device announces its entities again, the subscriptions and bindings are
+// reestablished after a reconnect.
+//
+// SPINE events have to be passed to HandleEvent, either by subscribing the
+// manager with spine.Events.Subscribe or by forwarding them from an existing
+// event handler
+type SubscriptionManager struct {
+	localEntity   spineapi.EntityLocalInterface
+	retryInterval time.Duration
+
+	subscriptions map[model.EntityTypeType][]model.FeatureTypeType
+	bindings      map[model.EntityTypeType][]model.FeatureTypeType
+
+	// the managed remote entities with an optional pending retry
+	remoteEntities map[spineapi.EntityRemoteInterface]*time.Timer
+	// the requests of the managed remote entities rejected by the remote entity
+	rejected map[spineapi.EntityRemoteInterface][]rejectedRequest
+
+	shutdown bool
+
+	mux sync.Mutex
+}
+
+var _ spineapi.EventHandlerInterface = (*SubscriptionManager)(nil)
+
+// a subscription or binding request rejected by a remote entity
+//
+// the local feature keeps the subscription or binding after sending the
+// request, so it has to be requested again without checking it
+type rejectedRequest struct {
+	featureType model.FeatureTypeType
+	binding     bool
+}
+
+// Get a new subscription manager for a local entity
+//
+// retryInterval defines the delay for retrying failed subscriptions and bindings,
+// a default of 10 seconds is used if it is not positive
+func NewSubscriptionManager(
+	localEntity spineapi.EntityLocalInterface,
+	retryInterval time.Duration) (*SubscriptionManager, error) {
+	if localEntity == nil {
+		return nil, errors.New("local entity is nil")
+	}
+
+	if retryInterval <= 0 {
+		retryInterval = defaultSubscriptionRetryInterval
+	}
+
+	m := &SubscriptionManager{
+		localEntity:    localEntity,
+		retryInterval:  retryInterval,
+		subscriptions:  make(map[model.EntityTypeType][]model.FeatureTypeType),
+		bindings:       make(map[model.EntityTypeType][]model.FeatureTypeType),
+		remoteEntities: make(map[spineapi.EntityRemoteInterface]*time.Timer),
+		rejected:       make(map[spineapi.EntityRemoteInterface][]rejectedRequest),
+	}
+
+	return m, nil
+}
+
+// subscribe to the server features of the given types of all remote entities of an entity type
+//
+// the client features are added to the local entity if needed, already
+// known remote entities of the entity type are handled immediately
+func (m *SubscriptionManager) AddSubscriptions(entityType model.EntityTypeType, featureTypes ...model.FeatureTypeType) {
+	m.addFeatureTypes(m.subscriptions, entityType, featureTypes)
+}
+
+// bind to the server features of the given types of all remote entities of an entity type
+//
+// the client features are added to the local entity if needed, already
+// known remote entities of the entity type are handled immediately
+func (m *SubscriptionManager) AddBindings(entityType model.EntityTypeType, featureTypes ...model.FeatureTypeType) {
+	m.addFeatureTypes(m.bindings, entityType, featureTypes)
+}
+
+// return the managed remote entities
+func (m *SubscriptionManager) RemoteEntities() []spineapi.EntityRemoteInterface {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	result := make([]spineapi.EntityRemoteInterface, 0, len(m.remoteEntities))
+	for entity := range m.remoteEntities {
+		result = append(result, entity)
+	}
+
+	return result
+}
+
+// handle SPINE events to manage the remote entities
+func (m *SubscriptionManager) HandleEvent(payload spineapi.EventPayload) {
+	switch payload.EventType {
+	case spineapi.EventTypeEntityChange:
+		if payload.Entity == nil {
+			return
+		}
+
+		switch payload.ChangeType {
+		case spineapi.ElementChangeAdd:
+			m.addEntity(payload.Entity)
+		case spineapi.ElementChangeRemove:
+			m.removeEntity(payload.Entity)
+		}
+
+	case spineapi.EventTypeDeviceChange:
+		if payload.ChangeType == spineapi.ElementChangeRemove {
+			m.removeEntitiesOfSki(payload.Ski)
+		}
+	}
+}
+
+// remove all subscriptions and bindings of the managed remote entities
+//
+// pending retries are stopped and later events are ignored
+func (m *SubscriptionManager) Shutdown() {
+	m.mux.Lock()
+	if m.shutdown {
+		m.mux.Unlock()
+		return
+	}
+	m.shutdown = true
+
+	var entities []spineapi.EntityRemoteInterface
+	for entity, timer := range m.remoteEntities {
+		if timer != nil {
+			timer.Stop()
+		}
+		entities = append(entities, entity)
+	}
+	m.remoteEntities = make(map[spineapi.EntityRemoteInterface]*time.Timer)
+	m.rejected = make(map[spineapi.EntityRemoteInterface][]rejectedRequest)
+	m.mux.Unlock()
+
+	for _, entity := range entities {
+		subscriptions, bindings := m.featureTypes(entity)
+
+		for _, featureType := range subscriptions {
+			feature, err := NewFeature(featureType, m.localEntity, entity)
+			if err != nil || !feature.HasSubscription() {
+				continue
+			}
+
+			if _, err := feature.Unsubscribe(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+		for _, featureType := range bindings {
+			feature, err := NewFeature(featureType, m.localEntity, entity)
+			if err != nil || !feature.HasBinding() {
+				continue
+			}
+
+			if _, err := feature.Unbind(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+	}
+}
+
+// add the feature types of an entity type and handle the known remote entities
+func (m *SubscriptionManager) addFeatureTypes(
+	rules map[model.EntityTypeType][]model.FeatureTypeType,
+	entityType model.EntityTypeType,
+	featureTypes []model.FeatureTypeType) {
+	m.mux.Lock()
+	for _, featureType := range featureTypes {
+		m.localEntity.GetOrAddFeature(featureType, model.RoleTypeClient)
+
+		if !slices.Contains(rules[entityType], featureType) {
+			rules[entityType] = append(rules[entityType], featureType)
+		}
+	}
+	m.mux.Unlock()
+
+	if m.localEntity.Device() == nil {
+		return
+	}
+
+	for _, remoteDevice := range m.localEntity.Device().RemoteDevices() {
+		for _, entity := range remoteDevice.Entities() {
+			if entity.EntityType() == entityType {
+				m.addEntity(entity)
+			}
+		}
+	}
+}
+
+// return the feature types to subscribe and bind for a remote entity
+func (m *SubscriptionManager) featureTypes(
+	remoteEntity spineapi.EntityRemoteInterface) (subscriptions, bindings []model.FeatureTypeType) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	entityType := remoteEntity.EntityType()
+
+	return slices.Clone(m.subscriptions[entityType]), slices.Clone(m.bindings[entityType])
+}
+
+// add a remote entity and establish its subscriptions and bindings
+func (m *SubscriptionManager) addEntity(remoteEntity spineapi.EntityRemoteInterface) {
+	subscriptions, bindings := m.featureTypes(remoteEntity)
+	if len(subscriptions) == 0 && len(bindings) == 0 {
+		return
+	}
+
+	m.mux.Lock()
+	if m.shutdown {
+		m.mux.Unlock()
+		return
+	}
+	if timer := m.remoteEntities[remoteEntity]; timer != nil {
+		timer.Stop()
+	}
+	m.remoteEntities[remoteEntity] = nil
+	delete(m.rejected, remoteEntity)
+	m.mux.Unlock()
+
+	m.establish(remoteEntity, nil)
+}
+
+// remove a remote entity and stop a pending retry
+func (m *SubscriptionManager) removeEntity(remoteEntity spineapi.EntityRemoteInterface) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if timer := m.remoteEntities[remoteEntity]; timer != nil {
+		timer.Stop()
+	}
+	delete(m.remoteEntities, remoteEntity)
+	delete(m.rejected, remoteEntity)
+}
+
+// remove all remote entities of a disconnected remote device
+func (m *SubscriptionManager) removeEntitiesOfSki(ski string) {
+	for _, entity := range m.RemoteEntities() {
+		if entity.Device() != nil && entity.Device().Ski() == ski {
+			m.removeEntity(entity)
+		}
+	}
+}
+
+// subscribe and bind the missing features of a remote entity and the
+// rejected ones, and schedule a retry if a request failed
+func (m *SubscriptionManager) establish(remoteEntity spineapi.EntityRemoteInterface, rejected []rejectedRequest) {
+	subscriptions, bindings := m.featureTypes(remoteEntity)
+
+	failed := false
+
+	for _, featureType := range subscriptions {
+		// the remote entity does not provide the feature, retrying does not help
+		feature, err := NewFeature(featureType, m.localEntity, remoteEntity)
+		if err != nil {
+			logging.Log().Debug(err)
+			continue
+		}
+
+		request := rejectedRequest{featureType: featureType}
+		if feature.HasSubscription() && !slices.Contains(rejected, request) {
+			continue
+		}
+
+		msgCounter, err := feature.Subscribe()
+		if err != nil {
+			logging.Log().Debug(err)
+			failed = true
+			continue
+		}
+
+		m.retryOnRejection(feature, remoteEntity, msgCounter, request)
+	}
+
+	for _, featureType := range bindings {
+		feature, err := NewFeature(featureType, m.localEntity, remoteEntity)
+		if err != nil {
+			logging.Log().Debug(err)
+			continue
+		}
+
+		request := rejectedRequest{featureType: featureType, binding: true}
+		if feature.HasBinding() && !slices.Contains(rejected, request) {
+			continue
+		}
+
+		msgCounter, err := feature.Bind()
+		if err != nil {
+			logging.Log().Debug(err)
+			failed = true
+			continue
+		}
+
+		m.retryOnRejection(feature, remoteEntity, msgCounter, request)
+	}
+
+	if failed {
+		m.scheduleRetry(remoteEntity)
+	}
+}
+
+// schedule a retry of a request if the remote entity responds with an error result
+func (m *SubscriptionManager) retryOnRejection(
+	feature *Feature,
+	remoteEntity spineapi.EntityRemoteInterface,
+	msgCounter *model.MsgCounterType,
+	request rejectedRequest) {
+	if msgCounter == nil {
+		return
+	}
+
+	feature.AddResultCallback(*msgCounter, func(msg spineapi.ResultMessage) {
+		if msg.Result == nil || msg.Result.ErrorNumber == nil ||
+			*msg.Result.ErrorNumber == model.ErrorNumberTypeNoError {
+			return
+		}
+
+		logging.Log().Debug("request rejected by remote entity:", request.featureType, *msg.Result.ErrorNumber)
+
+		m.mux.Lock()
+		if !slices.Contains(m.rejected[remoteEntity], request) {
+			m.rejected[remoteEntity] = append(m.rejected[remoteEntity], request)
+		}
+		m.mux.Unlock()
+
+		m.scheduleRetry(remoteEntity)
+	})
+}
+
+// schedule establishing the subscriptions and bindings of a remote entity again,
+// if no retry is pending yet
+func (m *SubscriptionManager) scheduleRetry(remoteEntity spineapi.EntityRemoteInterface) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	// the remote entity was removed in the meantime
+	timer, ok := m.remoteEntities[remoteEntity]
+	if !ok || m.shutdown || timer != nil {
+		return
+	}
+
+	m.remoteEntities[remoteEntity] = time.AfterFunc(m.retryInterval, func() {
+		m.mux.Lock()
+		_, ok := m.remoteEntities[remoteEntity]
+		rejected := m.rejected[remoteEntity]
+		if ok {
+			m.remoteEntities[remoteEntity] = nil
+			delete(m.rejected, remoteEntity)
+		}
+		m.mux.Unlock()
+
+		if ok {
+			m.establish(remoteEntity, rejected)
+		}
+	})
+}
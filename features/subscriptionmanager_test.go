@@ -0,0 +1,191 @@
+package features_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/features"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSubscriptionManagerSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionManagerSuite))
+}
+
+type SubscriptionManagerSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *WriteMessageHandler
+
+	sut *features.SubscriptionManager
+}
+
+func (s *SubscriptionManagerSuite) BeforeTest(suiteName, testName string) {
+	s.writeHandler = &WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = setupFeatures(
+		s.T(),
+		s.writeHandler,
+		[]featureFunctions{
+			{
+				featureType: model.FeatureTypeTypeMeasurement,
+				functions: []model.FunctionType{
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				featureType: model.FeatureTypeTypeLoadControl,
+				functions: []model.FunctionType{
+					model.FunctionTypeLoadControlLimitListData,
+				},
+			},
+		},
+	)
+
+	var err error
+	s.sut, err = features.NewSubscriptionManager(s.localEntity, 10*time.Millisecond)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), s.sut)
+}
+
+func (s *SubscriptionManagerSuite) feature(featureType model.FeatureTypeType) *features.Feature {
+	feature, err := features.NewFeature(featureType, s.localEntity, s.remoteEntity)
+	assert.Nil(s.T(), err)
+
+	return feature
+}
+
+func (s *SubscriptionManagerSuite) entityEvent(changeType spineapi.ElementChangeType) spineapi.EventPayload {
+	return spineapi.EventPayload{
+		Ski:        "test",
+		EventType:  spineapi.EventTypeEntityChange,
+		ChangeType: changeType,
+		Entity:     s.remoteEntity,
+	}
+}
+
+func (s *SubscriptionManagerSuite) Test_NewSubscriptionManager() {
+	sut, err := features.NewSubscriptionManager(nil, time.Second)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), sut)
+}
+
+func (s *SubscriptionManagerSuite) Test_KnownEntities() {
+	s.sut.AddSubscriptions(model.EntityTypeTypeEVSE, model.FeatureTypeTypeMeasurement)
+	s.sut.AddBindings(model.EntityTypeTypeEVSE, model.FeatureTypeTypeLoadControl)
+
+	assert.Equal(s.T(), []spineapi.EntityRemoteInterface{s.remoteEntity}, s.sut.RemoteEntities())
+	assert.True(s.T(), s.feature(model.FeatureTypeTypeMeasurement).HasSubscription())
+	assert.False(s.T(), s.feature(model.FeatureTypeTypeMeasurement).HasBinding())
+	assert.True(s.T(), s.feature(model.FeatureTypeTypeLoadControl).HasBinding())
+	assert.False(s.T(), s.feature(model.FeatureTypeTypeLoadControl).HasSubscription())
+
+	s.sut.Shutdown()
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+	assert.False(s.T(), s.feature(model.FeatureTypeTypeMeasurement).HasSubscription())
+	assert.False(s.T(), s.feature(model.FeatureTypeTypeLoadControl).HasBinding())
+
+	// events after the shutdown are ignored
+	s.sut.HandleEvent(s.entityEvent(spineapi.ElementChangeAdd))
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+	assert.False(s.T(), s.feature(model.FeatureTypeTypeMeasurement).HasSubscription())
+}
+
+func (s *SubscriptionManagerSuite) Test_HandleEvent() {
+	s.sut.AddSubscriptions(model.EntityTypeTypeCompressor, model.FeatureTypeTypeMeasurement)
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+
+	// the local client feature is added if needed
+	s.sut.AddSubscriptions(model.EntityTypeTypeCompressor, model.FeatureTypeTypeBill)
+	assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(model.FeatureTypeTypeBill, model.RoleTypeClient))
+
+	// the entity type does not match
+	s.sut.HandleEvent(s.entityEvent(spineapi.ElementChangeAdd))
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+
+	s.sut.AddSubscriptions(model.EntityTypeTypeEVSE, model.FeatureTypeTypeMeasurement, model.FeatureTypeTypeBill)
+	assert.True(s.T(), s.feature(model.FeatureTypeTypeMeasurement).HasSubscription())
+
+	s.sut.HandleEvent(s.entityEvent(spineapi.ElementChangeRemove))
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+
+	s.sut.HandleEvent(s.entityEvent(spineapi.ElementChangeAdd))
+	assert.Equal(s.T(), []spineapi.EntityRemoteInterface{s.remoteEntity}, s.sut.RemoteEntities())
+
+	s.sut.HandleEvent(spineapi.EventPayload{
+		Ski:        "other",
+		EventType:  spineapi.EventTypeDeviceChange,
+		ChangeType: spineapi.ElementChangeRemove,
+	})
+	assert.Equal(s.T(), 1, len(s.sut.RemoteEntities()))
+
+	s.sut.HandleEvent(spineapi.EventPayload{
+		Ski:        "test",
+		EventType:  spineapi.EventTypeDeviceChange,
+		ChangeType: spineapi.ElementChangeRemove,
+	})
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+}
+
+func (s *SubscriptionManagerSuite) Test_Retry() {
+	remoteDevice := s.remoteEntity.Device()
+	localDevice := s.localEntity.Device()
+
+	// the subscription fails, as the local device does not know the remote device
+	localDevice.RemoveRemoteDevice("test")
+
+	s.sut.AddSubscriptions(model.EntityTypeTypeEVSE, model.FeatureTypeTypeMeasurement)
+	s.sut.HandleEvent(s.entityEvent(spineapi.ElementChangeAdd))
+	assert.Equal(s.T(), 1, len(s.sut.RemoteEntities()))
+	assert.False(s.T(), s.feature(model.FeatureTypeTypeMeasurement).HasSubscription())
+
+	localDevice.AddRemoteDeviceForSki("test", remoteDevice)
+
+	assert.Eventually(s.T(), func() bool {
+		return s.feature(model.FeatureTypeTypeMeasurement).HasSubscription()
+	}, time.Second, 5*time.Millisecond)
+
+	s.sut.Shutdown()
+}
+
+func (s *SubscriptionManagerSuite) Test_RetryRejected() {
+	subscriptionRequests := func() []model.MsgCounterType {
+		return sentCounters(s.T(), s.writeHandler, func(cmd model.CmdType) bool {
+			return cmd.NodeManagementSubscriptionRequestCall != nil
+		})
+	}
+	bindingRequests := func() []model.MsgCounterType {
+		return sentCounters(s.T(), s.writeHandler, func(cmd model.CmdType) bool {
+			return cmd.NodeManagementBindingRequestCall != nil
+		})
+	}
+
+	s.sut.AddSubscriptions(model.EntityTypeTypeEVSE, model.FeatureTypeTypeMeasurement)
+	s.sut.AddBindings(model.EntityTypeTypeEVSE, model.FeatureTypeTypeLoadControl)
+	assert.Equal(s.T(), 1, len(subscriptionRequests()))
+	assert.Equal(s.T(), 1, len(bindingRequests()))
+
+	// the remote entity rejects the subscription and accepts the binding
+	receiveNodeManagementResult(s.T(), s.localEntity, s.remoteEntity, &subscriptionRequests()[0],
+		resultCmd(model.ErrorNumberTypeCommandRejected, ""))
+	receiveNodeManagementResult(s.T(), s.localEntity, s.remoteEntity, &bindingRequests()[0],
+		resultCmd(model.ErrorNumberTypeNoError, ""))
+
+	assert.Eventually(s.T(), func() bool {
+		return len(subscriptionRequests()) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	// the accepted subscription is not requested again
+	receiveNodeManagementResult(s.T(), s.localEntity, s.remoteEntity, &subscriptionRequests()[1],
+		resultCmd(model.ErrorNumberTypeNoError, ""))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(s.T(), 2, len(subscriptionRequests()))
+	assert.Equal(s.T(), 1, len(bindingRequests()))
+
+	s.sut.Shutdown()
+}
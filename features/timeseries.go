@@ -45,6 +45,25 @@ func (t *TimeSeries) RequestValues() (*model.MsgCounterType, error) {
 	return t.requestData(model.FunctionTypeTimeSeriesListData, nil, nil)
 }
 
+// request FunctionTypeTimeSeriesListData of a time series from a remote device
+//
+// if the remote entity does not announce partial reads, all time series are requested
+func (t *TimeSeries) RequestValuesForTimeSeriesId(timeSeriesId model.TimeSeriesIdType) (*model.MsgCounterType, error) {
+	function := model.FunctionTypeTimeSeriesListData
+	if !t.SupportsPartialRead(function) {
+		return t.requestData(function, nil, nil)
+	}
+
+	filter := model.NewFilterTypePartial()
+	filter.TimeSeriesListDataSelectors = &model.TimeSeriesListDataSelectorsType{
+		TimeSeriesId: &timeSeriesId,
+	}
+	cmd := model.CmdType{
+		TimeSeriesListData: &model.TimeSeriesListDataType{},
+	}
+	return t.requestDataWithFilter(function, filter, cmd)
+}
+
 // write Time Series values
 // returns an error if this failed
 func (t *TimeSeries) WriteValues(data []model.TimeSeriesDataType) (*model.MsgCounterType, error) {
@@ -58,7 +77,49 @@ func (t *TimeSeries) WriteValues(data []model.TimeSeriesDataType) (*model.MsgCou
 		},
 	}
 
-	return t.remoteDevice.Sender().Write(t.featureLocal.Address(), t.featureRemote.Address(), cmd)
+	return t.sendWrite(cmd)
+}
+
+// write Time Series values partially, the other time series of the remote entity are kept
+//
+// if the remote entity does not announce partial writes, the time series are
+// merged into the current time series and the full list is written
+//
+// possible errors:
+//   - ErrMissingData if no time series are provided or a time series has no time series id
+//   - ErrFunctionNotSupported if the remote entity does not support the time series list data
+//   - ErrOperationOnFunctionNotSupported if the time series list data is not writeable
+//   - and others
+func (t *TimeSeries) WriteValuesPartial(data []model.TimeSeriesDataType) (*model.MsgCounterType, error) {
+	if len(data) == 0 {
+		return nil, api.ErrMissingData
+	}
+
+	for _, item := range data {
+		if item.TimeSeriesId == nil {
+			return nil, api.ErrMissingData
+		}
+	}
+
+	function := model.FunctionTypeTimeSeriesListData
+
+	var filter *model.FilterType
+	if t.SupportsPartialWrite(function) {
+		filter = model.NewFilterTypePartial()
+	} else {
+		current, _ := t.GetValues()
+		data = mergeItems(current, data, func(a, b model.TimeSeriesDataType) bool {
+			return equalPtr(a.TimeSeriesId, b.TimeSeriesId)
+		})
+	}
+
+	cmd := model.CmdType{
+		TimeSeriesListData: &model.TimeSeriesListDataType{
+			TimeSeriesData: data,
+		},
+	}
+
+	return t.writeData(function, filter, cmd)
 }
 
 // return current values for Time Series
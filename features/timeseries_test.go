@@ -73,6 +73,28 @@ func (s *TimeSeriesSuite) Test_RequestValues() {
 	assert.NotNil(s.T(), counter)
 }
 
+func (s *TimeSeriesSuite) Test_RequestValuesForTimeSeriesId() {
+	timeSeriesId := model.TimeSeriesIdType(1)
+
+	counter, err := s.timeSeries.RequestValuesForTimeSeriesId(timeSeriesId)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd := cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 1, len(cmd.Filter))
+	assert.NotNil(s.T(), cmd.Filter[0].CmdControl.Partial)
+	assert.Equal(s.T(), timeSeriesId, *cmd.Filter[0].TimeSeriesListDataSelectors.TimeSeriesId)
+
+	// partial reads are not announced, so all time series are requested
+	nonPartial, err := features.NewTimeSeries(s.localEntity, nonPartialRemoteEntity(s.remoteEntity))
+	assert.Nil(s.T(), err)
+
+	counter, err = nonPartial.RequestValuesForTimeSeriesId(timeSeriesId)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd = cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 0, len(cmd.Filter))
+}
+
 func (s *TimeSeriesSuite) Test_WriteValues() {
 	counter, err := s.timeSeries.WriteValues(nil)
 	assert.NotNil(s.T(), err)
@@ -93,6 +115,43 @@ func (s *TimeSeriesSuite) Test_WriteValues() {
 	assert.NotNil(s.T(), counter)
 }
 
+func (s *TimeSeriesSuite) Test_WriteValuesPartial() {
+	counter, err := s.timeSeries.WriteValuesPartial(nil)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), counter)
+
+	counter, err = s.timeSeries.WriteValuesPartial([]model.TimeSeriesDataType{{}})
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), counter)
+
+	s.addData()
+
+	// only the provided time series are written
+	data := []model.TimeSeriesDataType{
+		{
+			TimeSeriesId: util.Ptr(model.TimeSeriesIdType(1)),
+		},
+	}
+	counter, err = s.timeSeries.WriteValuesPartial(data)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd := cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 1, len(cmd.Filter))
+	assert.NotNil(s.T(), cmd.Filter[0].CmdControl.Partial)
+	assert.Equal(s.T(), 1, len(cmd.TimeSeriesListData.TimeSeriesData))
+
+	// partial writes are not announced, so the time series are merged into the full list
+	nonPartial, err := features.NewTimeSeries(s.localEntity, nonPartialRemoteEntity(s.remoteEntity))
+	assert.Nil(s.T(), err)
+
+	counter, err = nonPartial.WriteValuesPartial(data)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), counter)
+	cmd = cmdOfMessage(s.T(), s.sentMessage)
+	assert.Equal(s.T(), 0, len(cmd.Filter))
+	assert.Equal(s.T(), 2, len(cmd.TimeSeriesListData.TimeSeriesData))
+}
+
 func (s *TimeSeriesSuite) Test_GetValues() {
 	data, err := s.timeSeries.GetValues()
 	assert.NotNil(s.T(), err)
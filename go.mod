@@ -6,6 +6,7 @@ require (
 	github.com/enbility/ship-go v0.0.0-20240227162634-e4ac25eae5ae
 	github.com/enbility/spine-go v0.0.0-20240226123143-abcf863b0736
 	github.com/stretchr/testify v1.8.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -27,7 +28,6 @@ require (
 	golang.org/x/net v0.21.0 // indirect
 	golang.org/x/sys v0.17.0 // indirect
 	golang.org/x/tools v0.17.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 retract (
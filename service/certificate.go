@@ -0,0 +1,329 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/ship-go/cert"
+	"github.com/enbility/ship-go/logging"
+)
+
+// the default duration before the expiry of a certificate, from which on warnings are logged
+const defaultCertificateExpiryWarning = time.Hour * 24 * 30
+
+// the subject details used when creating a new certificate
+type CertificateSubject struct {
+	// The OU of the certificate
+	OrganizationalUnit string
+
+	// The O of the certificate
+	Organization string
+
+	// The C of the certificate
+	Country string
+
+	// The CN of the certificate
+	// Example: "deviceModel-deviceSerialNumber"
+	CommonName string
+}
+
+// A certificate manager handles the lifecycle of the certificate of a service
+//
+// It loads the certificate and private key from PEM files or creates and
+// persists a new SHIP compatible certificate if none exists yet.
+// The private key is stored PKCS#8 encoded and only readable by the owner.
+type CertificateManager struct {
+	certFile string
+	keyFile  string
+
+	subject CertificateSubject
+
+	// duration before the expiry from which on warnings are logged
+	expiryWarning time.Duration
+
+	certificate tls.Certificate
+	leaf        *x509.Certificate
+
+	rotationHandlers []func(oldCertificate, newCertificate tls.Certificate)
+
+	mux sync.Mutex
+}
+
+// creates a new certificate manager
+//
+// certFile and keyFile are the paths of the PEM files of the certificate and private key
+// subject is used when a new certificate has to be created
+func NewCertificateManager(certFile, keyFile string, subject CertificateSubject) *CertificateManager {
+	return &CertificateManager{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		subject:       subject,
+		expiryWarning: defaultCertificateExpiryWarning,
+	}
+}
+
+var _ api.CertificateProviderInterface = (*CertificateManager)(nil)
+
+// define the duration before the expiry of the certificate from which on
+// warnings are logged, default is 30 days
+func (c *CertificateManager) SetExpiryWarning(duration time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.expiryWarning = duration
+}
+
+// add a callback function to be invoked after the certificate got rotated
+//
+// Note: the certificate is only used for new connections once the
+// service is set up and started again, as the SKI of the service changes
+// and remote services need to trust the new SKI
+func (c *CertificateManager) AddRotationHandler(handler func(oldCertificate, newCertificate tls.Certificate)) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.rotationHandlers = append(c.rotationHandlers, handler)
+}
+
+// load the certificate and private key from the files
+// or create and persist new ones if both files do not exist
+//
+// returns an error if only one of the files exists, the files are invalid
+// or the private key file is accessible by other users
+func (c *CertificateManager) LoadOrCreate() error {
+	certExists, err := fileExists(c.certFile)
+	if err != nil {
+		return err
+	}
+	keyExists, err := fileExists(c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case certExists && keyExists:
+		return c.load()
+	case !certExists && !keyExists:
+		certificate, err := c.create()
+		if err != nil {
+			return err
+		}
+		return c.setCertificate(certificate)
+	case certExists:
+		return fmt.Errorf("key file %s is missing", c.keyFile)
+	default:
+		return fmt.Errorf("certificate file %s is missing", c.certFile)
+	}
+}
+
+// return the currently used certificate
+func (c *CertificateManager) Certificate() tls.Certificate {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	return c.certificate
+}
+
+// return the SKI of the currently used certificate
+func (c *CertificateManager) SKI() (string, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.leaf == nil {
+		return "", errors.New("no certificate loaded")
+	}
+
+	return cert.SkiFromCertificate(c.leaf)
+}
+
+// return the time the currently used certificate expires
+func (c *CertificateManager) NotAfter() (time.Time, error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.leaf == nil {
+		return time.Time{}, errors.New("no certificate loaded")
+	}
+
+	return c.leaf.NotAfter, nil
+}
+
+// check if the currently used certificate is expired or expires within the
+// expiry warning duration and log a warning if it does
+//
+// returns true if a warning was logged
+func (c *CertificateManager) CheckExpiry() bool {
+	notAfter, err := c.NotAfter()
+	if err != nil {
+		return false
+	}
+
+	c.mux.Lock()
+	expiryWarning := c.expiryWarning
+	c.mux.Unlock()
+
+	remaining := time.Until(notAfter)
+	if remaining <= 0 {
+		logging.Log().Error("certificate expired at", notAfter)
+		return true
+	}
+
+	if remaining <= expiryWarning {
+		logging.Log().Info("certificate expires at", notAfter)
+		return true
+	}
+
+	return false
+}
+
+// create a new certificate, persist it replacing the existing files
+// and invoke all rotation handlers
+func (c *CertificateManager) Rotate() error {
+	oldCertificate := c.Certificate()
+
+	certificate, err := c.create()
+	if err != nil {
+		return err
+	}
+
+	if err := c.setCertificate(certificate); err != nil {
+		return err
+	}
+
+	c.mux.Lock()
+	handlers := c.rotationHandlers
+	c.mux.Unlock()
+
+	for _, handler := range handlers {
+		handler(oldCertificate, certificate)
+	}
+
+	return nil
+}
+
+// load the certificate and private key from the files
+func (c *CertificateManager) load() error {
+	if err := checkKeyFilePermissions(c.keyFile); err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+
+	if err := c.setCertificate(certificate); err != nil {
+		return err
+	}
+
+	c.CheckExpiry()
+
+	return nil
+}
+
+// create a new certificate and persist it
+func (c *CertificateManager) create() (tls.Certificate, error) {
+	certificate, err := cert.CreateCertificate(
+		c.subject.OrganizationalUnit,
+		c.subject.Organization,
+		c.subject.Country,
+		c.subject.CommonName)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(certificate.PrivateKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	certData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]})
+	keyData := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+
+	// the previous key is restored if the certificate can not be written,
+	// so the files never contain a key without its certificate
+	previousKeyData, err := os.ReadFile(c.keyFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return tls.Certificate{}, err
+	}
+
+	if err := writeFileAtomically(c.keyFile, keyData, 0600); err != nil {
+		return tls.Certificate{}, err
+	}
+
+	if err := writeFileAtomically(c.certFile, certData, 0644); err != nil {
+		if previousKeyData == nil {
+			_ = os.Remove(c.keyFile)
+		} else {
+			_ = writeFileAtomically(c.keyFile, previousKeyData, 0600)
+		}
+		return tls.Certificate{}, err
+	}
+
+	return certificate, nil
+}
+
+// set the certificate and its parsed leaf certificate
+func (c *CertificateManager) setCertificate(certificate tls.Certificate) error {
+	if len(certificate.Certificate) == 0 {
+		return errors.New("missing certificate")
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return err
+	}
+
+	if _, err := cert.SkiFromCertificate(leaf); err != nil {
+		return err
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.certificate = certificate
+	c.leaf = leaf
+
+	return nil
+}
+
+// return if a file exists
+func fileExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// return an error if the private key file is accessible by the group or others
+//
+// file permissions are not checked on Windows
+func checkKeyFilePermissions(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("key file %s has permissions %s, it may only be accessible by its owner", path, info.Mode().Perm())
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/enbility/ship-go/cert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCertificateManagerSuite(t *testing.T) {
+	suite.Run(t, new(CertificateManagerSuite))
+}
+
+type CertificateManagerSuite struct {
+	suite.Suite
+
+	certFile string
+	keyFile  string
+
+	sut *CertificateManager
+}
+
+func (s *CertificateManagerSuite) BeforeTest(suiteName, testName string) {
+	dir := s.T().TempDir()
+	s.certFile = filepath.Join(dir, "test.crt")
+	s.keyFile = filepath.Join(dir, "test.key")
+
+	subject := CertificateSubject{
+		OrganizationalUnit: "unit",
+		Organization:       "org",
+		Country:            "DE",
+		CommonName:         "cn",
+	}
+	s.sut = NewCertificateManager(s.certFile, s.keyFile, subject)
+}
+
+func (s *CertificateManagerSuite) Test_LoadOrCreate() {
+	ski, err := s.sut.SKI()
+	assert.NotNil(s.T(), err)
+	assert.Equal(s.T(), "", ski)
+
+	_, err = s.sut.NotAfter()
+	assert.NotNil(s.T(), err)
+
+	assert.Equal(s.T(), false, s.sut.CheckExpiry())
+
+	err = s.sut.LoadOrCreate()
+	assert.Nil(s.T(), err)
+
+	ski, err = s.sut.SKI()
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), "", ski)
+
+	certificate := s.sut.Certificate()
+	assert.Equal(s.T(), 1, len(certificate.Certificate))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(s.keyFile)
+		assert.Nil(s.T(), err)
+		assert.Equal(s.T(), os.FileMode(0600), info.Mode().Perm())
+	}
+
+	keyData, err := os.ReadFile(s.keyFile)
+	assert.Nil(s.T(), err)
+	block, _ := pem.Decode(keyData)
+	assert.NotNil(s.T(), block)
+	assert.Equal(s.T(), "PRIVATE KEY", block.Type)
+	_, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.Nil(s.T(), err)
+
+	// loading the same files again returns the same certificate
+	subject := CertificateSubject{}
+	loaded := NewCertificateManager(s.certFile, s.keyFile, subject)
+	err = loaded.LoadOrCreate()
+	assert.Nil(s.T(), err)
+
+	loadedSki, err := loaded.SKI()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), ski, loadedSki)
+}
+
+func (s *CertificateManagerSuite) Test_LoadOrCreate_Errors() {
+	err := os.WriteFile(s.certFile, []byte("invalid"), 0644)
+	assert.Nil(s.T(), err)
+
+	err = s.sut.LoadOrCreate()
+	assert.NotNil(s.T(), err)
+
+	err = os.WriteFile(s.keyFile, []byte("invalid"), 0600)
+	assert.Nil(s.T(), err)
+
+	err = s.sut.LoadOrCreate()
+	assert.NotNil(s.T(), err)
+
+	err = os.Remove(s.certFile)
+	assert.Nil(s.T(), err)
+
+	err = s.sut.LoadOrCreate()
+	assert.NotNil(s.T(), err)
+
+	invalid := NewCertificateManager(filepath.Join(s.certFile, "missing", "test.crt"), s.keyFile, CertificateSubject{})
+	err = invalid.LoadOrCreate()
+	assert.NotNil(s.T(), err)
+}
+
+func (s *CertificateManagerSuite) Test_LoadOrCreate_CertificateWriteError() {
+	// the certificate directory is blocked by a file
+	certDir := filepath.Join(filepath.Dir(s.certFile), "certs")
+	err := os.WriteFile(certDir, []byte("blocked"), 0644)
+	assert.Nil(s.T(), err)
+
+	certFile := filepath.Join(certDir, "test.crt")
+	sut := NewCertificateManager(certFile, s.keyFile, CertificateSubject{})
+	err = sut.LoadOrCreate()
+	assert.NotNil(s.T(), err)
+
+	// no key is left without its certificate
+	_, err = os.Stat(s.keyFile)
+	assert.True(s.T(), os.IsNotExist(err))
+
+	err = os.Remove(certDir)
+	assert.Nil(s.T(), err)
+	err = os.Mkdir(certDir, 0755)
+	assert.Nil(s.T(), err)
+
+	err = sut.LoadOrCreate()
+	assert.Nil(s.T(), err)
+
+	// a failed rotation keeps the previous key
+	keyData, err := os.ReadFile(s.keyFile)
+	assert.Nil(s.T(), err)
+
+	err = os.Remove(certFile)
+	assert.Nil(s.T(), err)
+	err = os.Remove(certDir)
+	assert.Nil(s.T(), err)
+	err = os.WriteFile(certDir, []byte("blocked"), 0644)
+	assert.Nil(s.T(), err)
+
+	err = sut.Rotate()
+	assert.NotNil(s.T(), err)
+
+	rotatedKeyData, err := os.ReadFile(s.keyFile)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), keyData, rotatedKeyData)
+}
+
+func (s *CertificateManagerSuite) Test_LoadLegacyKey() {
+	if runtime.GOOS == "windows" {
+		s.T().Skip("file permissions are not checked on windows")
+	}
+
+	certificate, err := cert.CreateCertificate("unit", "org", "DE", "cn")
+	assert.Nil(s.T(), err)
+
+	certData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Certificate[0]})
+	keyBytes, err := x509.MarshalECPrivateKey(certificate.PrivateKey.(*ecdsa.PrivateKey))
+	assert.Nil(s.T(), err)
+	keyData := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes})
+
+	err = os.WriteFile(s.certFile, certData, 0644)
+	assert.Nil(s.T(), err)
+	err = os.WriteFile(s.keyFile, keyData, 0644)
+	assert.Nil(s.T(), err)
+
+	// the key file is readable by others
+	err = s.sut.LoadOrCreate()
+	assert.NotNil(s.T(), err)
+
+	err = os.Chmod(s.keyFile, 0600)
+	assert.Nil(s.T(), err)
+
+	err = s.sut.LoadOrCreate()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), certificate.Certificate, s.sut.Certificate().Certificate)
+}
+
+func (s *CertificateManagerSuite) Test_CheckExpiry() {
+	err := s.sut.LoadOrCreate()
+	assert.Nil(s.T(), err)
+
+	notAfter, err := s.sut.NotAfter()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), true, notAfter.After(time.Now()))
+
+	assert.Equal(s.T(), false, s.sut.CheckExpiry())
+
+	s.sut.SetExpiryWarning(time.Hour * 24 * 365 * 11)
+	assert.Equal(s.T(), true, s.sut.CheckExpiry())
+
+	s.sut.leaf.NotAfter = time.Now().Add(-time.Hour)
+	assert.Equal(s.T(), true, s.sut.CheckExpiry())
+}
+
+func (s *CertificateManagerSuite) Test_Rotate() {
+	err := s.sut.LoadOrCreate()
+	assert.Nil(s.T(), err)
+
+	oldSki, err := s.sut.SKI()
+	assert.Nil(s.T(), err)
+
+	var rotatedOld, rotatedNew tls.Certificate
+	s.sut.AddRotationHandler(func(oldCertificate, newCertificate tls.Certificate) {
+		rotatedOld = oldCertificate
+		rotatedNew = newCertificate
+	})
+
+	oldCertificate := s.sut.Certificate()
+
+	err = s.sut.Rotate()
+	assert.Nil(s.T(), err)
+
+	newSki, err := s.sut.SKI()
+	assert.Nil(s.T(), err)
+	assert.NotEqual(s.T(), oldSki, newSki)
+
+	assert.Equal(s.T(), oldCertificate.Certificate, rotatedOld.Certificate)
+	assert.Equal(s.T(), s.sut.Certificate().Certificate, rotatedNew.Certificate)
+
+	// the rotated certificate got persisted
+	loaded := NewCertificateManager(s.certFile, s.keyFile, CertificateSubject{})
+	err = loaded.LoadOrCreate()
+	assert.Nil(s.T(), err)
+
+	loadedSki, err := loaded.SKI()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), newSki, loadedSki)
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/enbility/eebus-go/api"
+)
+
+// load a Configuration from a YAML or JSON file
+//
+// the values of the file are overridden by environment variables
+// using the api.DefaultEnvironmentPrefix, e.g. EEBUS_PORT. Invalid
+// environment variables and invalid values are reported together
+func LoadConfiguration(path string) (*api.Configuration, error) {
+	data, err := api.LoadConfigurationData(path)
+	if err != nil {
+		return nil, err
+	}
+
+	envErr := data.ApplyEnvironment(api.DefaultEnvironmentPrefix)
+	if err := errors.Join(envErr, data.Validate()); err != nil {
+		return nil, err
+	}
+
+	return NewConfigurationFromData(data)
+}
+
+// create a Configuration from declarative configuration data
+//
+// the certificate and key file are required, they are loaded or created
+// using a CertificateManager, which is set as the certificate provider
+func NewConfigurationFromData(data *api.ConfigurationData) (*api.Configuration, error) {
+	// without a provided certificate the validation requires the certificate and key file
+	configuration, err := data.Configuration(tls.Certificate{})
+	if err != nil {
+		return nil, err
+	}
+
+	certManager := NewCertificateManager(data.CertificateFile, data.KeyFile, CertificateSubject{
+		OrganizationalUnit: data.DeviceBrand,
+		Organization:       data.DeviceBrand,
+		CommonName:         fmt.Sprintf("%s-%s", data.DeviceModel, data.SerialNumber),
+	})
+	if err := certManager.LoadOrCreate(); err != nil {
+		return nil, err
+	}
+
+	configuration.SetCertificateProvider(certManager)
+
+	return configuration, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestConfigurationSuite(t *testing.T) {
+	suite.Run(t, new(ConfigurationSuite))
+}
+
+type ConfigurationSuite struct {
+	suite.Suite
+
+	dir string
+}
+
+func (s *ConfigurationSuite) BeforeTest(suiteName, testName string) {
+	s.dir = s.T().TempDir()
+}
+
+func (s *ConfigurationSuite) Test_LoadConfiguration() {
+	path := filepath.Join(s.dir, "config.yaml")
+	content := `
+deviceBrand: brand
+deviceModel: model
+serialNumber: serial
+deviceType: EnergyManagementSystem
+entityTypes: [CEM]
+certificateFile: ` + filepath.Join(s.dir, "service.crt") + `
+keyFile: ` + filepath.Join(s.dir, "service.key") + `
+`
+	err := os.WriteFile(path, []byte(content), 0600)
+	assert.Nil(s.T(), err)
+
+	s.T().Setenv("EEBUS_PORT", "4800")
+
+	config, err := LoadConfiguration(path)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), config)
+	assert.Equal(s.T(), 4800, config.Port())
+	assert.NotNil(s.T(), config.CertificateProvider())
+	assert.Equal(s.T(), 1, len(config.Certificate().Certificate))
+
+	// the created certificate is loaded again
+	config2, err := LoadConfiguration(path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), config.Certificate().Certificate, config2.Certificate().Certificate)
+
+	s.T().Setenv("EEBUS_PORT", "invalid")
+	config, err = LoadConfiguration(path)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), config)
+
+	// invalid environment variables and invalid values are reported together
+	s.T().Setenv("EEBUS_DEVICE_TYPE", "invalid")
+	config, err = LoadConfiguration(path)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), config)
+	assert.Contains(s.T(), err.Error(), "EEBUS_PORT")
+	assert.Contains(s.T(), err.Error(), "deviceType")
+
+	config, err = LoadConfiguration(filepath.Join(s.dir, "missing.yaml"))
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), config)
+}
+
+func (s *ConfigurationSuite) Test_NewConfigurationFromData() {
+	data := &api.ConfigurationData{}
+	config, err := NewConfigurationFromData(data)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), config)
+
+	data = &api.ConfigurationData{
+		DeviceBrand:  "brand",
+		DeviceModel:  "model",
+		SerialNumber: "serial",
+		DeviceType:   "EnergyManagementSystem",
+		EntityTypes:  []string{"CEM"},
+	}
+	// neither certificate and key files nor a certificate are configured
+	config, err = NewConfigurationFromData(data)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), config)
+	assert.Contains(s.T(), err.Error(), "certificateFile and keyFile are required")
+
+	data.CertificateFile = filepath.Join(s.dir, "service.crt")
+	data.KeyFile = filepath.Join(s.dir, "missing", "service.key")
+	config, err = NewConfigurationFromData(data)
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), config)
+}
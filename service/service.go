@@ -4,14 +4,17 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
 	shipapi "github.com/enbility/ship-go/api"
 	"github.com/enbility/ship-go/cert"
 	"github.com/enbility/ship-go/hub"
 	"github.com/enbility/ship-go/logging"
 	"github.com/enbility/ship-go/mdns"
+	shiputil "github.com/enbility/ship-go/util"
 	spineapi "github.com/enbility/spine-go/api"
 	"github.com/enbility/spine-go/model"
 	"github.com/enbility/spine-go/spine"
@@ -33,7 +36,15 @@ type Service struct {
 
 	serviceHandler api.ServiceReaderInterface
 
+	// The store persisting the trusted remote services
+	trustStore api.TrustStoreInterface
+
+	// The use cases added to the local device
+	usecases []api.UseCaseInterface
+
 	startOnce sync.Once
+
+	mux sync.Mutex
 }
 
 // creates a new EEBUS service
@@ -41,10 +52,12 @@ func NewService(configuration *api.Configuration, serviceHandler api.ServiceRead
 	return &Service{
 		configuration:  configuration,
 		serviceHandler: serviceHandler,
+		trustStore:     NewMemoryTrustStore(),
 	}
 }
 
 var _ api.ServiceInterface = (*Service)(nil)
+var _ spineapi.EventHandlerInterface = (*Service)(nil)
 
 // Starts the service by initializeing mDNS and the server.
 func (s *Service) Setup() error {
@@ -112,6 +125,9 @@ func (s *Service) Setup() error {
 		s.spineLocalDevice.AddEntity(entity)
 	}
 
+	// Forward SPINE events to the use cases
+	_ = spine.Events.Subscribe(s)
+
 	// setup mDNS
 	mdns := mdns.NewMDNS(
 		s.localService.SKI(),
@@ -128,6 +144,20 @@ func (s *Service) Setup() error {
 	// Setup connections hub with mDNS and websocket connection handling
 	s.connectionsHub = hub.NewHub(s, mdns, s.configuration.Port(), s.configuration.Certificate(), s.localService)
 
+	// Restore the trusted remote services, so they are reconnected once the hub is started
+	trustedServices, err := s.trustStore.TrustedServices()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range trustedServices {
+		service := s.connectionsHub.ServiceForSKI(item.SKI)
+		service.SetTrusted(true)
+		if len(item.ShipID) > 0 {
+			service.SetShipID(item.ShipID)
+		}
+	}
+
 	return nil
 }
 
@@ -142,6 +172,13 @@ func (s *Service) Start() {
 func (s *Service) Shutdown() {
 	// Shut down all running connections
 	s.connectionsHub.Shutdown()
+
+	// Stop forwarding SPINE events to the use cases,
+	// this SPINE version removes all application level event handlers
+	_ = spine.Events.Unsubscribe(s)
+
+	// The results of the feature helpers are no longer needed
+	features.RemoveLocalDeviceResults(s.spineLocalDevice)
 }
 
 func (s *Service) Configuration() *api.Configuration {
@@ -165,6 +202,54 @@ func (s *Service) SetLogging(logger logging.LoggingInterface) {
 	logging.SetLogging(logger)
 }
 
+// Sets a custom trust store implementation
+// By default a MemoryTrustStore is used, so trusted remote services are not persisted
+//
+// This has to be invoked before Setup
+func (s *Service) SetTrustStore(store api.TrustStoreInterface) {
+	if store == nil {
+		return
+	}
+	s.trustStore = store
+}
+
+// Adds a use case to the local device
+//
+// The features and the use case support are added to the local entity of the use case
+// and all SPINE events are forwarded to it
+//
+// This has to be invoked after Setup and before Start
+func (s *Service) AddUseCase(usecase api.UseCaseInterface) {
+	if usecase == nil {
+		return
+	}
+
+	usecase.AddFeatures()
+	usecase.AddUseCase()
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.usecases = append(s.usecases, usecase)
+}
+
+// Forwards SPINE events to all added use cases
+//
+// The results of the feature helpers kept for a removed remote device are removed
+func (s *Service) HandleEvent(payload spineapi.EventPayload) {
+	if payload.EventType == spineapi.EventTypeDeviceChange && payload.ChangeType == spineapi.ElementChangeRemove {
+		features.RemoveRemoteDeviceResults(s.spineLocalDevice, payload.Ski)
+	}
+
+	s.mux.Lock()
+	usecases := slices.Clone(s.usecases)
+	s.mux.Unlock()
+
+	for _, usecase := range usecases {
+		usecase.HandleEvent(payload)
+	}
+}
+
 // Get the current pairing details for a given SKI
 func (s *Service) PairingDetailForSki(ski string) *shipapi.ConnectionStateDetail {
 	return s.connectionsHub.PairingDetailForSki(ski)
@@ -177,8 +262,17 @@ func (s *Service) RemoteServiceForSKI(ski string) *shipapi.ServiceDetails {
 
 // Sets the SKI as being paired or not
 // and connect it if paired and not currently being connected
+//
+// The change is persisted in the trust store
 func (s *Service) RegisterRemoteSKI(ski string, enable bool) {
 	s.connectionsHub.RegisterRemoteSKI(ski, enable)
+
+	if !enable {
+		s.removeTrustedService(ski)
+		return
+	}
+
+	s.storeTrustedService(ski, s.connectionsHub.ServiceForSKI(ski).ShipID())
 }
 
 // Close a connection to a remote SKI
@@ -195,3 +289,40 @@ func (s *Service) InitiateOrApprovePairingWithSKI(ski string) {
 func (s *Service) CancelPairingWithSKI(ski string) {
 	s.connectionsHub.CancelPairingWithSKI(ski)
 }
+
+// persist a trusted remote service in the trust store
+func (s *Service) storeTrustedService(ski, shipID string) {
+	entry := api.TrustedService{
+		SKI:    ski,
+		ShipID: shipID,
+	}
+
+	if err := s.trustStore.SetTrustedService(entry); err != nil {
+		logging.Log().Error("error storing trusted service", ski, err)
+	}
+}
+
+// remove a remote service from the trust store
+func (s *Service) removeTrustedService(ski string) {
+	if err := s.trustStore.RemoveTrustedService(ski); err != nil {
+		logging.Log().Error("error removing trusted service", ski, err)
+	}
+}
+
+// return the trust store entry for a SKI, nil if the SKI is not trusted
+func (s *Service) trustedService(ski string) *api.TrustedService {
+	services, err := s.trustStore.TrustedServices()
+	if err != nil {
+		logging.Log().Error("error reading trusted services", err)
+		return nil
+	}
+
+	ski = shiputil.NormalizeSKI(ski)
+	for _, item := range services {
+		if item.SKI == ski {
+			return &item
+		}
+	}
+
+	return nil
+}
@@ -31,16 +31,28 @@ func (s *Service) VisibleRemoteServicesUpdated(entries []shipapi.RemoteService)
 }
 
 // Provides the SHIP ID the remote service reported during the handshake process
-// This needs to be persisted and passed on for future remote service connections
-// when using `PairRemoteService`
+// This is persisted in the trust store if the remote service is trusted
 func (s *Service) ServiceShipIDUpdate(ski string, shipdID string) {
+	if entry := s.trustedService(ski); entry != nil && entry.ShipID != shipdID {
+		s.storeTrustedService(ski, shipdID)
+	}
+
 	s.serviceHandler.ServiceShipIDUpdate(ski, shipdID)
 }
 
 // Provides the current pairing state for the remote service
 // This is called whenever the state changes and can be used to
 // provide user information for the pairing/connection process
+//
+// Remote services reaching the trusted state are persisted in the trust store
 func (s *Service) ServicePairingDetailUpdate(ski string, detail *shipapi.ConnectionStateDetail) {
+	if detail != nil &&
+		detail.State() == shipapi.ConnectionStateTrusted &&
+		s.connectionsHub != nil &&
+		s.trustedService(ski) == nil {
+		s.storeTrustedService(ski, s.connectionsHub.ServiceForSKI(ski).ShipID())
+	}
+
 	s.serviceHandler.ServicePairingDetailUpdate(ski, detail)
 }
 
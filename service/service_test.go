@@ -11,8 +11,10 @@ import (
 	"github.com/enbility/ship-go/cert"
 	"github.com/enbility/ship-go/logging"
 	shipmocks "github.com/enbility/ship-go/mocks"
+	spineapi "github.com/enbility/spine-go/api"
 	spinemocks "github.com/enbility/spine-go/mocks"
 	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -96,7 +98,7 @@ func (s *ServiceSuite) Test_ConnectionsHub() {
 	s.conHub.EXPECT().PairingDetailForSki(mock.Anything).Return(nil)
 	s.sut.PairingDetailForSki(testSki)
 
-	s.conHub.EXPECT().ServiceForSKI(mock.Anything).Return(nil)
+	s.conHub.EXPECT().ServiceForSKI(mock.Anything).Return(nil).Once()
 	details := s.sut.RemoteServiceForSKI(testSki)
 	assert.Nil(s.T(), details)
 
@@ -104,6 +106,7 @@ func (s *ServiceSuite) Test_ConnectionsHub() {
 	s.sut.SetupRemoteDevice(testSki, s)
 
 	s.conHub.EXPECT().RegisterRemoteSKI(mock.Anything, mock.Anything).Return()
+	s.conHub.EXPECT().ServiceForSKI(testSki).Return(shipapi.NewServiceDetails(testSki))
 	s.sut.RegisterRemoteSKI(testSki, true)
 
 	s.conHub.EXPECT().InitiateOrApprovePairingWithSKI(mock.Anything).Return()
@@ -116,6 +119,80 @@ func (s *ServiceSuite) Test_ConnectionsHub() {
 	s.sut.DisconnectSKI(testSki, "reason")
 }
 
+func (s *ServiceSuite) Test_TrustStore() {
+	testSki := "test"
+	store := NewMemoryTrustStore()
+
+	s.sut.SetTrustStore(nil)
+	s.sut.SetTrustStore(store)
+	assert.Equal(s.T(), store, s.sut.trustStore)
+
+	s.sut.connectionsHub = s.conHub
+
+	details := shipapi.NewServiceDetails(testSki)
+	details.SetShipID("shipid")
+	s.conHub.EXPECT().ServiceForSKI(testSki).Return(details)
+	s.conHub.EXPECT().RegisterRemoteSKI(testSki, mock.Anything).Return()
+	s.sut.RegisterRemoteSKI(testSki, true)
+
+	services, _ := store.TrustedServices()
+	assert.Equal(s.T(), []api.TrustedService{{SKI: testSki, ShipID: "shipid"}}, services)
+
+	s.serviceReader.EXPECT().ServiceShipIDUpdate(testSki, mock.Anything).Return()
+	s.sut.ServiceShipIDUpdate(testSki, "newshipid")
+
+	services, _ = store.TrustedServices()
+	assert.Equal(s.T(), []api.TrustedService{{SKI: testSki, ShipID: "newshipid"}}, services)
+
+	s.serviceReader.EXPECT().ServiceShipIDUpdate("unknown", mock.Anything).Return()
+	s.sut.ServiceShipIDUpdate("unknown", "shipid")
+
+	services, _ = store.TrustedServices()
+	assert.Equal(s.T(), 1, len(services))
+
+	s.sut.RegisterRemoteSKI(testSki, false)
+
+	services, _ = store.TrustedServices()
+	assert.Equal(s.T(), 0, len(services))
+
+	s.serviceReader.EXPECT().ServicePairingDetailUpdate(testSki, mock.Anything).Return()
+	detail := shipapi.NewConnectionStateDetail(shipapi.ConnectionStateInProgress, nil)
+	s.sut.ServicePairingDetailUpdate(testSki, detail)
+
+	services, _ = store.TrustedServices()
+	assert.Equal(s.T(), 0, len(services))
+
+	detail = shipapi.NewConnectionStateDetail(shipapi.ConnectionStateTrusted, nil)
+	s.sut.ServicePairingDetailUpdate(testSki, detail)
+
+	services, _ = store.TrustedServices()
+	assert.Equal(s.T(), []api.TrustedService{{SKI: testSki, ShipID: "shipid"}}, services)
+}
+
+func (s *ServiceSuite) Test_Setup_TrustStore() {
+	certificate, err := cert.CreateCertificate("unit", "org", "de", "cn")
+	assert.Nil(s.T(), err)
+	s.config.SetCertificate(certificate)
+
+	store := NewMemoryTrustStore()
+	_ = store.SetTrustedService(api.TrustedService{SKI: "1234", ShipID: "shipid"})
+	s.sut.SetTrustStore(store)
+
+	err = s.sut.Setup()
+	assert.Nil(s.T(), err)
+
+	details := s.sut.RemoteServiceForSKI("1234")
+	assert.Equal(s.T(), true, details.Trusted())
+	assert.Equal(s.T(), "shipid", details.ShipID())
+
+	details = s.sut.RemoteServiceForSKI("5678")
+	assert.Equal(s.T(), false, details.Trusted())
+
+	s.sut.SetTrustStore(NewFileTrustStore(s.T().TempDir()))
+	err = s.sut.Setup()
+	assert.NotNil(s.T(), err)
+}
+
 func (s *ServiceSuite) Test_SetLogging() {
 	s.sut.SetLogging(nil)
 	assert.Equal(s.T(), &logging.NoLogging{}, logging.Log())
@@ -150,4 +227,36 @@ func (s *ServiceSuite) Test_Setup() {
 
 	device := s.sut.LocalDevice()
 	assert.NotNil(s.T(), device)
+
+	// SPINE events are no longer forwarded to the use cases
+	usecase := mocks.NewUseCaseInterface(s.T())
+	s.sut.usecases = append(s.sut.usecases, usecase)
+	spine.Events.Publish(spineapi.EventPayload{
+		Ski:       "test",
+		EventType: spineapi.EventTypeDeviceChange,
+	})
+	usecase.AssertNotCalled(s.T(), "HandleEvent", mock.Anything)
+}
+
+func (s *ServiceSuite) Test_AddUseCase() {
+	s.sut.AddUseCase(nil)
+	assert.Equal(s.T(), 0, len(s.sut.usecases))
+
+	usecase := mocks.NewUseCaseInterface(s.T())
+	usecase.EXPECT().AddFeatures().Return().Once()
+	usecase.EXPECT().AddUseCase().Return().Once()
+	s.sut.AddUseCase(usecase)
+	assert.Equal(s.T(), 1, len(s.sut.usecases))
+
+	payload := spineapi.EventPayload{
+		Ski:       "test",
+		EventType: spineapi.EventTypeDeviceChange,
+	}
+	usecase.EXPECT().HandleEvent(payload).Return().Once()
+	s.sut.HandleEvent(payload)
+
+	// the removal of a remote device is forwarded as well
+	payload.ChangeType = spineapi.ElementChangeRemove
+	usecase.EXPECT().HandleEvent(payload).Return().Once()
+	s.sut.HandleEvent(payload)
 }
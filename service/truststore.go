@@ -0,0 +1,181 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/enbility/eebus-go/api"
+	shiputil "github.com/enbility/ship-go/util"
+)
+
+// A trust store keeping the trusted remote services in memory only
+//
+// This is the default trust store of a Service, all trusted
+// remote services are lost once the process is stopped
+type MemoryTrustStore struct {
+	services map[string]api.TrustedService
+
+	mux sync.Mutex
+}
+
+// creates a new empty in-memory trust store
+func NewMemoryTrustStore() *MemoryTrustStore {
+	return &MemoryTrustStore{
+		services: make(map[string]api.TrustedService),
+	}
+}
+
+var _ api.TrustStoreInterface = (*MemoryTrustStore)(nil)
+
+// return all trusted remote services
+func (m *MemoryTrustStore) TrustedServices() ([]api.TrustedService, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	result := make([]api.TrustedService, 0, len(m.services))
+	for _, item := range m.services {
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// add or update a trusted remote service
+func (m *MemoryTrustStore) SetTrustedService(service api.TrustedService) error {
+	service.SKI = shiputil.NormalizeSKI(service.SKI)
+	if len(service.SKI) == 0 {
+		return errors.New("ski is required")
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.services[service.SKI] = service
+
+	return nil
+}
+
+// remove a trusted remote service for a given SKI
+func (m *MemoryTrustStore) RemoveTrustedService(ski string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	delete(m.services, shiputil.NormalizeSKI(ski))
+
+	return nil
+}
+
+// A trust store persisting the trusted remote services in a JSON file
+//
+// The file is rewritten on every change and only readable by the owner
+type FileTrustStore struct {
+	path string
+
+	mux sync.Mutex
+}
+
+// creates a new trust store using the JSON file at the given path
+//
+// the file does not need to exist, it will be created on the first change
+func NewFileTrustStore(path string) *FileTrustStore {
+	return &FileTrustStore{
+		path: path,
+	}
+}
+
+var _ api.TrustStoreInterface = (*FileTrustStore)(nil)
+
+// return all trusted remote services
+func (f *FileTrustStore) TrustedServices() ([]api.TrustedService, error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	return f.load()
+}
+
+// add or update a trusted remote service
+func (f *FileTrustStore) SetTrustedService(service api.TrustedService) error {
+	service.SKI = shiputil.NormalizeSKI(service.SKI)
+	if len(service.SKI) == 0 {
+		return errors.New("ski is required")
+	}
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	services, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i, item := range services {
+		if item.SKI == service.SKI {
+			services[i] = service
+			found = true
+			break
+		}
+	}
+	if !found {
+		services = append(services, service)
+	}
+
+	return f.save(services)
+}
+
+// remove a trusted remote service for a given SKI
+func (f *FileTrustStore) RemoveTrustedService(ski string) error {
+	ski = shiputil.NormalizeSKI(ski)
+
+	f.mux.Lock()
+	defer f.mux.Unlock()
+
+	services, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	result := make([]api.TrustedService, 0, len(services))
+	for _, item := range services {
+		if item.SKI == ski {
+			continue
+		}
+		result = append(result, item)
+	}
+
+	if len(result) == len(services) {
+		return nil
+	}
+
+	return f.save(result)
+}
+
+// read the services from the file, a missing file is an empty store
+func (f *FileTrustStore) load() ([]api.TrustedService, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []api.TrustedService{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var services []api.TrustedService
+	if err := json.Unmarshal(data, &services); err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+// write the services into the file
+func (f *FileTrustStore) save(services []api.TrustedService) error {
+	data, err := json.MarshalIndent(services, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomically(f.path, data, 0600)
+}
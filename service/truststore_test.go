@@ -0,0 +1,102 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTrustStoreSuite(t *testing.T) {
+	suite.Run(t, new(TrustStoreSuite))
+}
+
+type TrustStoreSuite struct {
+	suite.Suite
+
+	path string
+}
+
+func (s *TrustStoreSuite) BeforeTest(suiteName, testName string) {
+	s.path = filepath.Join(s.T().TempDir(), "trust.json")
+}
+
+func (s *TrustStoreSuite) Test_MemoryTrustStore() {
+	s.runStoreTests(NewMemoryTrustStore())
+}
+
+func (s *TrustStoreSuite) Test_FileTrustStore() {
+	s.runStoreTests(NewFileTrustStore(s.path))
+
+	info, err := os.Stat(s.path)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), os.FileMode(0600), info.Mode().Perm())
+}
+
+func (s *TrustStoreSuite) Test_FileTrustStore_Persistence() {
+	sut := NewFileTrustStore(s.path)
+
+	err := sut.SetTrustedService(api.TrustedService{SKI: "1234", ShipID: "shipid"})
+	assert.Nil(s.T(), err)
+
+	sut = NewFileTrustStore(s.path)
+	services, err := sut.TrustedServices()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []api.TrustedService{{SKI: "1234", ShipID: "shipid"}}, services)
+}
+
+func (s *TrustStoreSuite) Test_FileTrustStore_Invalid() {
+	err := os.WriteFile(s.path, []byte("invalid"), 0600)
+	assert.Nil(s.T(), err)
+
+	sut := NewFileTrustStore(s.path)
+	services, err := sut.TrustedServices()
+	assert.NotNil(s.T(), err)
+	assert.Nil(s.T(), services)
+
+	err = sut.SetTrustedService(api.TrustedService{SKI: "1234"})
+	assert.NotNil(s.T(), err)
+
+	err = sut.RemoveTrustedService("1234")
+	assert.NotNil(s.T(), err)
+
+	sut = NewFileTrustStore(filepath.Join(s.path, "missing", "trust.json"))
+	err = sut.SetTrustedService(api.TrustedService{SKI: "1234"})
+	assert.NotNil(s.T(), err)
+}
+
+func (s *TrustStoreSuite) runStoreTests(sut api.TrustStoreInterface) {
+	services, err := sut.TrustedServices()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, len(services))
+
+	err = sut.SetTrustedService(api.TrustedService{})
+	assert.NotNil(s.T(), err)
+
+	err = sut.SetTrustedService(api.TrustedService{SKI: "12 34 AB"})
+	assert.Nil(s.T(), err)
+
+	services, err = sut.TrustedServices()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []api.TrustedService{{SKI: "1234ab"}}, services)
+
+	err = sut.SetTrustedService(api.TrustedService{SKI: "1234ab", ShipID: "shipid"})
+	assert.Nil(s.T(), err)
+
+	services, err = sut.TrustedServices()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []api.TrustedService{{SKI: "1234ab", ShipID: "shipid"}}, services)
+
+	err = sut.RemoveTrustedService("unknown")
+	assert.Nil(s.T(), err)
+
+	err = sut.RemoveTrustedService("1234AB")
+	assert.Nil(s.T(), err)
+
+	services, err = sut.TrustedServices()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 0, len(services))
+}
@@ -0,0 +1,132 @@
+package cevc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Coordinated EV Charging, actor CEM
+//
+// Used e.g. by a HEMS to negotiate a charge plan with a connected EV by
+// providing power limits and incentives for its energy demand
+type CEVC struct {
+	*usecases.UseCase
+}
+
+// creates a new CEVC CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote EVs
+func NewCEVC(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *CEVC {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeEV,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeTimeSeries,
+		model.FeatureTypeTypeIncentiveTable,
+	}
+
+	e := &CEVC{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeCoordinatedEVCharging,
+		"1.0.1",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3, 4, 5, 6, 7, 8},
+		[]model.UseCaseActorType{model.UseCaseActorTypeEV},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*CEVC)(nil)
+var _ usecases.EntityHandlerInterface = (*CEVC)(nil)
+
+// bind to the features required for writing and request the initial data
+func (e *CEVC) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if timeSeries, err := features.NewTimeSeries(e.LocalEntity, entity); err == nil {
+		if !timeSeries.HasBinding() {
+			_, _ = timeSeries.Bind()
+		}
+
+		if _, err := timeSeries.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := timeSeries.RequestConstraints(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if incentiveTable, err := features.NewIncentiveTable(e.LocalEntity, entity); err == nil {
+		if !incentiveTable.HasBinding() {
+			_, _ = incentiveTable.Bind()
+		}
+
+		if _, err := incentiveTable.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := incentiveTable.RequestConstraints(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *CEVC) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *CEVC) HandleDataChange(payload spineapi.EventPayload) {
+	switch payload.Data.(type) {
+	case *model.TimeSeriesDescriptionListDataType:
+		if timeSeries, err := features.NewTimeSeries(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := timeSeries.RequestValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+
+			if description, err := timeSeries.GetDescriptionForType(model.TimeSeriesTypeTypeConstraints); err == nil &&
+				description.UpdateRequired != nil && *description.UpdateRequired {
+				e.ReportEvent(payload.Entity, DataRequestedPowerLimits)
+			}
+		}
+
+	case *model.TimeSeriesConstraintsListDataType:
+		if _, err := e.TimeSlotConstraints(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateTimeSlotConstraints)
+		}
+
+	case *model.TimeSeriesListDataType:
+		if _, err := e.EnergyDemand(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateEnergyDemand)
+		}
+
+		if _, err := e.ChargePlan(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateChargePlan)
+		}
+
+	case *model.IncentiveTableDescriptionDataType:
+		if incentiveTable, err := features.NewIncentiveTable(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := incentiveTable.RequestValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+
+			if descriptions, err := incentiveTable.GetDescriptionsForScope(model.ScopeTypeTypeSimpleIncentiveTable); err == nil &&
+				descriptions[0].TariffDescription.UpdateRequired != nil && *descriptions[0].TariffDescription.UpdateRequired {
+				e.ReportEvent(payload.Entity, DataRequestedIncentiveTable)
+			}
+		}
+
+	case *model.IncentiveTableConstraintsDataType:
+		if _, err := e.IncentiveConstraints(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateIncentiveConstraints)
+		}
+	}
+}
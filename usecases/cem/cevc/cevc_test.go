@@ -0,0 +1,289 @@
+package cevc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCemCEVCSuite(t *testing.T) {
+	suite.Run(t, new(CemCEVCSuite))
+}
+
+type CemCEVCSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *CEVC
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *CemCEVCSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CemCEVCSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *CemCEVCSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEV,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeTimeSeries,
+				Functions: []model.FunctionType{
+					model.FunctionTypeTimeSeriesDescriptionListData,
+					model.FunctionTypeTimeSeriesConstraintsListData,
+					model.FunctionTypeTimeSeriesListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeIncentiveTable,
+				Functions: []model.FunctionType{
+					model.FunctionTypeIncentiveTableDescriptionData,
+					model.FunctionTypeIncentiveTableConstraintsData,
+					model.FunctionTypeIncentiveTableData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewCEVC(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *CemCEVCSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeEV,
+		model.UseCaseNameTypeCoordinatedEVCharging, true)
+	s.sut.HandleEvent(payload)
+}
+
+// the time series of the EV, the ids are the index
+var timeSeriesTypes = []model.TimeSeriesTypeType{
+	model.TimeSeriesTypeTypeConstraints,
+	model.TimeSeriesTypeTypePlan,
+	model.TimeSeriesTypeTypeSingleDemand,
+}
+
+func (s *CemCEVCSuite) timeSeriesDescriptions(updateRequired bool) spineapi.EventPayload {
+	data := &model.TimeSeriesDescriptionListDataType{}
+	for id, timeSeriesType := range timeSeriesTypes {
+		data.TimeSeriesDescriptionData = append(data.TimeSeriesDescriptionData, model.TimeSeriesDescriptionDataType{
+			TimeSeriesId:        util.Ptr(model.TimeSeriesIdType(id)),
+			TimeSeriesType:      util.Ptr(timeSeriesType),
+			TimeSeriesWriteable: util.Ptr(timeSeriesType == model.TimeSeriesTypeTypeConstraints),
+			UpdateRequired:      util.Ptr(updateRequired && timeSeriesType == model.TimeSeriesTypeTypeConstraints),
+		})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeTimeSeries,
+		model.FunctionTypeTimeSeriesDescriptionListData, data)
+}
+
+// 1 to 10 slots of 15 minutes up to 1 hour with a maximum of 11 kW
+func (s *CemCEVCSuite) timeSeriesConstraints() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeTimeSeries,
+		model.FunctionTypeTimeSeriesConstraintsListData,
+		&model.TimeSeriesConstraintsListDataType{
+			TimeSeriesConstraintsData: []model.TimeSeriesConstraintsDataType{
+				{
+					TimeSeriesId:         util.Ptr(model.TimeSeriesIdType(0)),
+					SlotCountMin:         util.Ptr(model.TimeSeriesSlotCountType(1)),
+					SlotCountMax:         util.Ptr(model.TimeSeriesSlotCountType(10)),
+					SlotDurationMin:      model.NewDurationType(time.Minute * 15),
+					SlotDurationMax:      model.NewDurationType(time.Hour),
+					SlotDurationStepSize: model.NewDurationType(time.Minute * 15),
+					SlotValueMax:         model.NewScaledNumberType(11000),
+				},
+			},
+		})
+}
+
+// a demand of 10 kWh starting now with a departure in 8 hours and a
+// charge plan of two slots of 1 hour
+func (s *CemCEVCSuite) timeSeriesValues() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeTimeSeries,
+		model.FunctionTypeTimeSeriesListData,
+		&model.TimeSeriesListDataType{
+			TimeSeriesData: []model.TimeSeriesDataType{
+				{
+					TimeSeriesId: util.Ptr(model.TimeSeriesIdType(1)),
+					TimePeriod: &model.TimePeriodType{
+						StartTime: model.NewAbsoluteOrRelativeTimeTypeFromDuration(0),
+					},
+					TimeSeriesSlot: []model.TimeSeriesSlotType{
+						{
+							TimeSeriesSlotId: util.Ptr(model.TimeSeriesSlotIdType(0)),
+							Duration:         model.NewDurationType(time.Hour),
+							Value:            model.NewScaledNumberType(11000),
+						},
+						{
+							TimeSeriesSlotId: util.Ptr(model.TimeSeriesSlotIdType(1)),
+							Duration:         model.NewDurationType(time.Hour),
+							Value:            model.NewScaledNumberType(4200),
+						},
+					},
+				},
+				{
+					TimeSeriesId: util.Ptr(model.TimeSeriesIdType(2)),
+					TimePeriod: &model.TimePeriodType{
+						StartTime: model.NewAbsoluteOrRelativeTimeTypeFromDuration(0),
+						EndTime:   model.NewAbsoluteOrRelativeTimeTypeFromDuration(time.Hour * 8),
+					},
+					TimeSeriesSlot: []model.TimeSeriesSlotType{
+						{
+							TimeSeriesSlotId: util.Ptr(model.TimeSeriesSlotIdType(0)),
+							Value:            model.NewScaledNumberType(10000),
+							MinValue:         model.NewScaledNumberType(2000),
+							MaxValue:         model.NewScaledNumberType(50000),
+						},
+					},
+				},
+			},
+		})
+}
+
+func (s *CemCEVCSuite) incentiveTableDescriptions(updateRequired bool) spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeIncentiveTable,
+		model.FunctionTypeIncentiveTableDescriptionData,
+		&model.IncentiveTableDescriptionDataType{
+			IncentiveTableDescription: []model.IncentiveTableDescriptionType{
+				{
+					TariffDescription: &model.TariffDescriptionDataType{
+						TariffId:        util.Ptr(model.TariffIdType(0)),
+						TariffWriteable: util.Ptr(true),
+						UpdateRequired:  util.Ptr(updateRequired),
+						ScopeType:       util.Ptr(model.ScopeTypeTypeSimpleIncentiveTable),
+					},
+					Tier: []model.IncentiveTableDescriptionTierType{
+						{
+							TierDescription: &model.TierDescriptionDataType{
+								TierId:   util.Ptr(model.TierIdType(1)),
+								TierType: util.Ptr(model.TierTypeTypeDynamicCost),
+							},
+							BoundaryDescription: []model.TierBoundaryDescriptionDataType{
+								{
+									BoundaryId:   util.Ptr(model.TierBoundaryIdType(1)),
+									BoundaryType: util.Ptr(model.TierBoundaryTypeTypePowerBoundary),
+								},
+							},
+							IncentiveDescription: []model.IncentiveDescriptionDataType{
+								{
+									IncentiveId:   util.Ptr(model.IncentiveIdType(1)),
+									IncentiveType: util.Ptr(model.IncentiveTypeTypeAbsoluteCost),
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+}
+
+// 1 to 24 incentive slots, a single tier with one boundary and one incentive
+func (s *CemCEVCSuite) incentiveTableConstraints() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeIncentiveTable,
+		model.FunctionTypeIncentiveTableConstraintsData,
+		&model.IncentiveTableConstraintsDataType{
+			IncentiveTableConstraints: []model.IncentiveTableConstraintsType{
+				{
+					Tariff: &model.TariffDataType{
+						TariffId: util.Ptr(model.TariffIdType(0)),
+					},
+					TariffConstraints: &model.TariffOverallConstraintsDataType{
+						MaxTiersPerTariff:    util.Ptr(model.TierCountType(1)),
+						MaxBoundariesPerTier: util.Ptr(model.TierBoundaryCountType(1)),
+						MaxIncentivesPerTier: util.Ptr(model.IncentiveCountType(1)),
+					},
+					IncentiveSlotConstraints: &model.TimeTableConstraintsDataType{
+						SlotCountMin: util.Ptr(model.TimeSlotCountType(1)),
+						SlotCountMax: util.Ptr(model.TimeSlotCountType(24)),
+					},
+				},
+			},
+		})
+}
+
+func (s *CemCEVCSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeTimeSeries,
+		model.FeatureTypeTypeIncentiveTable,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeCEM, model.UseCaseNameTypeCoordinatedEVCharging))
+}
+
+func (s *CemCEVCSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := s.timeSeriesDescriptions(true)
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the time series values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+	assert.True(s.T(), s.hasEvent(DataRequestedPowerLimits))
+
+	s.sut.HandleEvent(s.timeSeriesConstraints())
+	assert.True(s.T(), s.hasEvent(DataUpdateTimeSlotConstraints))
+
+	s.sut.HandleEvent(s.timeSeriesValues())
+	assert.True(s.T(), s.hasEvent(DataUpdateEnergyDemand))
+	assert.True(s.T(), s.hasEvent(DataUpdateChargePlan))
+
+	payload = s.incentiveTableDescriptions(true)
+	count = s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the incentive table values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+	assert.True(s.T(), s.hasEvent(DataRequestedIncentiveTable))
+
+	s.sut.HandleEvent(s.incentiveTableConstraints())
+	assert.True(s.T(), s.hasEvent(DataUpdateIncentiveConstraints))
+}
+
+func (s *CemCEVCSuite) Test_HandleDataChange_NoUpdateRequired() {
+	s.connect()
+
+	s.sut.HandleEvent(s.timeSeriesDescriptions(false))
+	assert.False(s.T(), s.hasEvent(DataRequestedPowerLimits))
+
+	s.sut.HandleEvent(s.incentiveTableDescriptions(false))
+	assert.False(s.T(), s.hasEvent(DataRequestedIncentiveTable))
+}
@@ -0,0 +1,35 @@
+package cevc
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// EV energy demand data was updated
+	//
+	// Use `EnergyDemand` to get the current data
+	DataUpdateEnergyDemand api.EventType = "cem-cevc-DataUpdateEnergyDemand"
+
+	// EV time slot constraints of the power limits were updated
+	//
+	// Use `TimeSlotConstraints` to get the current data
+	DataUpdateTimeSlotConstraints api.EventType = "cem-cevc-DataUpdateTimeSlotConstraints"
+
+	// EV incentive table constraints were updated
+	//
+	// Use `IncentiveConstraints` to get the current data
+	DataUpdateIncentiveConstraints api.EventType = "cem-cevc-DataUpdateIncentiveConstraints"
+
+	// EV charge plan was updated
+	//
+	// Use `ChargePlan` to get the current data
+	DataUpdateChargePlan api.EventType = "cem-cevc-DataUpdateChargePlan"
+
+	// EV requested new power limits
+	//
+	// Use `WritePowerLimits` to provide the data
+	DataRequestedPowerLimits api.EventType = "cem-cevc-DataRequestedPowerLimits"
+
+	// EV requested a new incentive table
+	//
+	// Use `WriteIncentiveTableDescriptions` and `WriteIncentives` to provide the data
+	DataRequestedIncentiveTable api.EventType = "cem-cevc-DataRequestedIncentiveTable"
+)
@@ -0,0 +1,604 @@
+package cevc
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// return the duration from now until an absolute or relative time
+func durationUntil(value *model.AbsoluteOrRelativeTimeType) (time.Duration, error) {
+	if duration, err := value.GetTimeDuration(); err == nil {
+		return duration, nil
+	}
+
+	t, err := value.GetTime()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Until(t), nil
+}
+
+// return the value of an optional duration, 0 if not provided or invalid
+func optionalDuration(value *model.DurationType) time.Duration {
+	if value == nil {
+		return 0
+	}
+
+	duration, err := value.GetTimeDuration()
+	if err != nil {
+		return 0
+	}
+
+	return duration
+}
+
+// return the value of an optional scaled number, 0 if not provided
+func optionalValue(value *model.ScaledNumberType) float64 {
+	if value == nil {
+		return 0
+	}
+
+	return value.GetValue()
+}
+
+// Scenario 1
+
+// return the current energy demand of the EV
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - the demand, including the duration until the departure time
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no demand is available
+//   - and others
+func (e *CEVC) EnergyDemand(entity spineapi.EntityRemoteInterface) (usecases.Demand, error) {
+	if !e.HasRemoteEntity(entity) {
+		return usecases.Demand{}, api.ErrUsecCaseNotSupported
+	}
+
+	timeSeries, err := features.NewTimeSeries(e.LocalEntity, entity)
+	if err != nil {
+		return usecases.Demand{}, err
+	}
+
+	data, err := timeSeries.GetValueForType(model.TimeSeriesTypeTypeSingleDemand)
+	if err != nil || len(data.TimeSeriesSlot) == 0 {
+		return usecases.Demand{}, api.ErrDataNotAvailable
+	}
+
+	slot := data.TimeSeriesSlot[0]
+	demand := usecases.Demand{
+		MinDemand: optionalValue(slot.MinValue),
+		OptDemand: optionalValue(slot.Value),
+		MaxDemand: optionalValue(slot.MaxValue),
+	}
+
+	if data.TimePeriod != nil && data.TimePeriod.StartTime != nil {
+		if duration, err := durationUntil(data.TimePeriod.StartTime); err == nil && duration > 0 {
+			demand.DurationUntilStart = duration
+		}
+	}
+
+	switch {
+	case data.TimePeriod != nil && data.TimePeriod.EndTime != nil:
+		if duration, err := durationUntil(data.TimePeriod.EndTime); err == nil && duration > 0 {
+			demand.DurationUntilEnd = duration
+		}
+
+	case slot.Duration != nil:
+		if duration := optionalDuration(slot.Duration); duration > 0 {
+			demand.DurationUntilEnd = demand.DurationUntilStart + duration
+		}
+	}
+
+	return demand, nil
+}
+
+// Scenario 2
+
+// return the description and constraints of the power limits time series
+func (e *CEVC) powerLimitConstraints(entity spineapi.EntityRemoteInterface) (
+	*model.TimeSeriesDescriptionDataType, *model.TimeSeriesConstraintsDataType, error) {
+	timeSeries, err := features.NewTimeSeries(e.LocalEntity, entity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	description, err := timeSeries.GetDescriptionForType(model.TimeSeriesTypeTypeConstraints)
+	if err != nil || description.TimeSeriesId == nil {
+		return nil, nil, api.ErrMetadataNotAvailable
+	}
+
+	constraints, err := timeSeries.GetConstraints()
+	if err != nil {
+		return description, nil, api.ErrDataNotAvailable
+	}
+
+	for _, item := range constraints {
+		if item.TimeSeriesId != nil && *item.TimeSeriesId == *description.TimeSeriesId {
+			return description, &item, nil
+		}
+	}
+
+	return description, nil, api.ErrDataNotAvailable
+}
+
+// return the constraints of the power limit slots
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no power limit description is available
+//   - ErrDataNotAvailable if no constraints are available
+//   - and others
+func (e *CEVC) TimeSlotConstraints(entity spineapi.EntityRemoteInterface) (usecases.TimeSlotConstraints, error) {
+	if !e.HasRemoteEntity(entity) {
+		return usecases.TimeSlotConstraints{}, api.ErrUsecCaseNotSupported
+	}
+
+	_, constraints, err := e.powerLimitConstraints(entity)
+	if err != nil {
+		return usecases.TimeSlotConstraints{}, err
+	}
+
+	result := usecases.TimeSlotConstraints{
+		MinSlotDuration:      optionalDuration(constraints.SlotDurationMin),
+		MaxSlotDuration:      optionalDuration(constraints.SlotDurationMax),
+		SlotDurationStepSize: optionalDuration(constraints.SlotDurationStepSize),
+	}
+	if constraints.SlotCountMin != nil {
+		result.MinSlots = uint(*constraints.SlotCountMin)
+	}
+	if constraints.SlotCountMax != nil {
+		result.MaxSlots = uint(*constraints.SlotCountMax)
+	}
+
+	return result, nil
+}
+
+// check if the power limit slots meet the constraints of the EV
+func validatePowerLimits(constraints *model.TimeSeriesConstraintsDataType, data []usecases.DurationSlotValue) error {
+	count := uint(len(data))
+	if constraints.SlotCountMin != nil && count < uint(*constraints.SlotCountMin) {
+		return api.ErrConstraintsViolated
+	}
+	if constraints.SlotCountMax != nil && count > uint(*constraints.SlotCountMax) {
+		return api.ErrConstraintsViolated
+	}
+
+	minDuration := optionalDuration(constraints.SlotDurationMin)
+	maxDuration := optionalDuration(constraints.SlotDurationMax)
+	stepSize := optionalDuration(constraints.SlotDurationStepSize)
+
+	for _, slot := range data {
+		if slot.Duration < minDuration || (maxDuration > 0 && slot.Duration > maxDuration) {
+			return api.ErrConstraintsViolated
+		}
+		if stepSize > 0 && slot.Duration%stepSize != 0 {
+			return api.ErrConstraintsViolated
+		}
+		if constraints.SlotValueMin != nil && slot.Value < constraints.SlotValueMin.GetValue() {
+			return api.ErrConstraintsViolated
+		}
+		if constraints.SlotValueMax != nil && slot.Value > constraints.SlotValueMax.GetValue() {
+			return api.ErrConstraintsViolated
+		}
+	}
+
+	return nil
+}
+
+// send the maximum power limits the EV may charge with
+//
+// the slots start now, each slot starts at the end of the previous one
+//
+// parameters:
+//   - entity: the entity of the EV
+//   - data: the maximum power in W for each slot
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMissingData if no slot is provided
+//   - ErrMetadataNotAvailable if no power limit description is available
+//   - ErrNotSupported if the power limits are not writeable
+//   - ErrConstraintsViolated if the slots do not meet the constraints of the EV
+//   - and others
+func (e *CEVC) WritePowerLimits(entity spineapi.EntityRemoteInterface, data []usecases.DurationSlotValue) (*model.MsgCounterType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	if len(data) == 0 {
+		return nil, api.ErrMissingData
+	}
+
+	timeSeries, err := features.NewTimeSeries(e.LocalEntity, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	description, constraints, err := e.powerLimitConstraints(entity)
+	if description == nil {
+		return nil, err
+	}
+	if description.TimeSeriesWriteable != nil && !*description.TimeSeriesWriteable {
+		return nil, api.ErrNotSupported
+	}
+	if constraints != nil {
+		if err := validatePowerLimits(constraints, data); err != nil {
+			return nil, err
+		}
+	}
+
+	var slots []model.TimeSeriesSlotType
+	var duration time.Duration
+	for index, slot := range data {
+		item := model.TimeSeriesSlotType{
+			TimeSeriesSlotId: util.Ptr(model.TimeSeriesSlotIdType(index)),
+			TimePeriod: &model.TimePeriodType{
+				StartTime: model.NewAbsoluteOrRelativeTimeTypeFromDuration(duration),
+			},
+			MaxValue: model.NewScaledNumberType(slot.Value),
+		}
+
+		duration += slot.Duration
+
+		// the last slot also defines the end of the time series
+		if index == len(data)-1 {
+			item.TimePeriod.EndTime = model.NewAbsoluteOrRelativeTimeTypeFromDuration(duration)
+		}
+
+		slots = append(slots, item)
+	}
+
+	return timeSeries.WriteValues([]model.TimeSeriesDataType{
+		{
+			TimeSeriesId: description.TimeSeriesId,
+			TimePeriod: &model.TimePeriodType{
+				StartTime: model.NewAbsoluteOrRelativeTimeTypeFromDuration(0),
+				EndTime:   model.NewAbsoluteOrRelativeTimeTypeFromDuration(duration),
+			},
+			TimeSeriesSlot: slots,
+		},
+	})
+}
+
+// Scenario 3
+
+// return the description of the incentive table of the EV
+func (e *CEVC) incentiveTableDescription(incentiveTable *features.IncentiveTable) (*model.IncentiveTableDescriptionType, error) {
+	descriptions, err := incentiveTable.GetDescriptionsForScope(model.ScopeTypeTypeSimpleIncentiveTable)
+	if err != nil || descriptions[0].TariffDescription.TariffId == nil {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	return &descriptions[0], nil
+}
+
+// return the constraints of the incentive table of the EV
+func (e *CEVC) incentiveConstraints(incentiveTable *features.IncentiveTable) (*model.IncentiveTableConstraintsType, error) {
+	constraints, err := incentiveTable.GetConstraints()
+	if err != nil || len(constraints) == 0 {
+		return nil, api.ErrDataNotAvailable
+	}
+
+	return &constraints[0], nil
+}
+
+// return the constraints of the incentive slots
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no constraints are available
+//   - and others
+func (e *CEVC) IncentiveConstraints(entity spineapi.EntityRemoteInterface) (usecases.IncentiveSlotConstraints, error) {
+	if !e.HasRemoteEntity(entity) {
+		return usecases.IncentiveSlotConstraints{}, api.ErrUsecCaseNotSupported
+	}
+
+	incentiveTable, err := features.NewIncentiveTable(e.LocalEntity, entity)
+	if err != nil {
+		return usecases.IncentiveSlotConstraints{}, err
+	}
+
+	constraints, err := e.incentiveConstraints(incentiveTable)
+	if err != nil || constraints.IncentiveSlotConstraints == nil {
+		return usecases.IncentiveSlotConstraints{}, api.ErrDataNotAvailable
+	}
+
+	var result usecases.IncentiveSlotConstraints
+	if value := constraints.IncentiveSlotConstraints.SlotCountMin; value != nil {
+		result.MinSlots = uint(*value)
+	}
+	if value := constraints.IncentiveSlotConstraints.SlotCountMax; value != nil {
+		result.MaxSlots = uint(*value)
+	}
+
+	return result, nil
+}
+
+// check if the tiers meet the constraints of the incentive table of the EV
+func validateIncentiveTableTiers(constraints *model.TariffOverallConstraintsDataType, data []usecases.IncentiveTableDescriptionTier) error {
+	if constraints.MaxTiersPerTariff != nil && uint(len(data)) > uint(*constraints.MaxTiersPerTariff) {
+		return api.ErrConstraintsViolated
+	}
+
+	for _, tier := range data {
+		if constraints.MaxBoundariesPerTier != nil && uint(len(tier.Boundaries)) > uint(*constraints.MaxBoundariesPerTier) {
+			return api.ErrConstraintsViolated
+		}
+		if constraints.MaxIncentivesPerTier != nil && uint(len(tier.Incentives)) > uint(*constraints.MaxIncentivesPerTier) {
+			return api.ErrConstraintsViolated
+		}
+	}
+
+	return nil
+}
+
+// send the description of the incentive table
+//
+// parameters:
+//   - entity: the entity of the EV
+//   - data: the tiers of the incentive table with their boundaries and incentives
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMissingData if no tier is provided
+//   - ErrMetadataNotAvailable if no incentive table description is available
+//   - ErrNotSupported if the incentive table is not writeable
+//   - ErrConstraintsViolated if the tiers do not meet the constraints of the EV
+//   - and others
+func (e *CEVC) WriteIncentiveTableDescriptions(
+	entity spineapi.EntityRemoteInterface,
+	data []usecases.IncentiveTableDescriptionTier,
+) (*model.MsgCounterType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	if len(data) == 0 {
+		return nil, api.ErrMissingData
+	}
+
+	incentiveTable, err := features.NewIncentiveTable(e.LocalEntity, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := e.incentiveTableDescription(incentiveTable)
+	if err != nil {
+		return nil, err
+	}
+
+	tariff := *description.TariffDescription
+	if tariff.TariffWriteable != nil && !*tariff.TariffWriteable {
+		return nil, api.ErrNotSupported
+	}
+	tariff.UpdateRequired = nil
+
+	if constraints, err := e.incentiveConstraints(incentiveTable); err == nil && constraints.TariffConstraints != nil {
+		if err := validateIncentiveTableTiers(constraints.TariffConstraints, data); err != nil {
+			return nil, err
+		}
+	}
+
+	var tiers []model.IncentiveTableDescriptionTierType
+	for _, tier := range data {
+		item := model.IncentiveTableDescriptionTierType{
+			TierDescription: &model.TierDescriptionDataType{
+				TierId:   util.Ptr(tier.Id),
+				TierType: util.Ptr(tier.Type),
+			},
+		}
+
+		for _, boundary := range tier.Boundaries {
+			item.BoundaryDescription = append(item.BoundaryDescription, model.TierBoundaryDescriptionDataType{
+				BoundaryId:     util.Ptr(boundary.Id),
+				BoundaryType:   util.Ptr(boundary.Type),
+				ValidForTierId: util.Ptr(tier.Id),
+				BoundaryUnit:   util.Ptr(boundary.Unit),
+			})
+		}
+
+		for _, incentive := range tier.Incentives {
+			item.IncentiveDescription = append(item.IncentiveDescription, model.IncentiveDescriptionDataType{
+				IncentiveId:   util.Ptr(incentive.Id),
+				IncentiveType: util.Ptr(incentive.Type),
+				Currency:      util.Ptr(incentive.Currency),
+			})
+		}
+
+		tiers = append(tiers, item)
+	}
+
+	return incentiveTable.WriteDescriptions([]model.IncentiveTableDescriptionType{
+		{
+			TariffDescription: &tariff,
+			Tier:              tiers,
+		},
+	})
+}
+
+// send the incentives for the first tier of the incentive table
+//
+// the slots start now, each slot starts at the end of the previous one,
+// the incentive table descriptions have to be written before
+//
+// parameters:
+//   - entity: the entity of the EV
+//   - data: the incentive value for each slot, e.g. the price per kWh
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMissingData if no slot is provided
+//   - ErrMetadataNotAvailable if no incentive table description with a tier, boundary and incentive is available
+//   - ErrConstraintsViolated if the slots do not meet the constraints of the EV
+//   - and others
+func (e *CEVC) WriteIncentives(entity spineapi.EntityRemoteInterface, data []usecases.DurationSlotValue) (*model.MsgCounterType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	if len(data) == 0 {
+		return nil, api.ErrMissingData
+	}
+
+	incentiveTable, err := features.NewIncentiveTable(e.LocalEntity, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := e.incentiveTableDescription(incentiveTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(description.Tier) == 0 ||
+		description.Tier[0].TierDescription == nil || description.Tier[0].TierDescription.TierId == nil ||
+		len(description.Tier[0].BoundaryDescription) == 0 || description.Tier[0].BoundaryDescription[0].BoundaryId == nil ||
+		len(description.Tier[0].IncentiveDescription) == 0 || description.Tier[0].IncentiveDescription[0].IncentiveId == nil {
+		return nil, api.ErrMetadataNotAvailable
+	}
+	tier := description.Tier[0]
+
+	if constraints, err := e.IncentiveConstraints(entity); err == nil {
+		count := uint(len(data))
+		if (constraints.MinSlots > 0 && count < constraints.MinSlots) ||
+			(constraints.MaxSlots > 0 && count > constraints.MaxSlots) {
+			return nil, api.ErrConstraintsViolated
+		}
+	}
+
+	var slots []model.IncentiveTableIncentiveSlotType
+	var duration time.Duration
+	for index, slot := range data {
+		timeInterval := &model.TimeTableDataType{
+			StartTime: &model.AbsoluteOrRecurringTimeType{
+				Relative: model.NewDurationType(duration),
+			},
+		}
+
+		duration += slot.Duration
+
+		// the last slot also defines the end of the incentive table
+		if index == len(data)-1 {
+			timeInterval.EndTime = &model.AbsoluteOrRecurringTimeType{
+				Relative: model.NewDurationType(duration),
+			}
+		}
+
+		slots = append(slots, model.IncentiveTableIncentiveSlotType{
+			TimeInterval: timeInterval,
+			Tier: []model.IncentiveTableTierType{
+				{
+					Tier: &model.TierDataType{
+						TierId: tier.TierDescription.TierId,
+					},
+					Boundary: []model.TierBoundaryDataType{
+						{
+							BoundaryId:         tier.BoundaryDescription[0].BoundaryId,
+							LowerBoundaryValue: model.NewScaledNumberType(0),
+						},
+					},
+					Incentive: []model.IncentiveDataType{
+						{
+							IncentiveId: tier.IncentiveDescription[0].IncentiveId,
+							Value:       model.NewScaledNumberType(slot.Value),
+						},
+					},
+				},
+			},
+		})
+	}
+
+	return incentiveTable.WriteValues([]model.IncentiveTableType{
+		{
+			Tariff: &model.TariffDataType{
+				TariffId: description.TariffDescription.TariffId,
+			},
+			IncentiveSlot: slots,
+		},
+	})
+}
+
+// Scenario 4
+
+// return the charge plan of the EV
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - the plan with the planned power of each slot
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no charge plan is available
+//   - and others
+func (e *CEVC) ChargePlan(entity spineapi.EntityRemoteInterface) (usecases.ChargePlan, error) {
+	if !e.HasRemoteEntity(entity) {
+		return usecases.ChargePlan{}, api.ErrUsecCaseNotSupported
+	}
+
+	timeSeries, err := features.NewTimeSeries(e.LocalEntity, entity)
+	if err != nil {
+		return usecases.ChargePlan{}, err
+	}
+
+	data, err := timeSeries.GetValueForType(model.TimeSeriesTypeTypePlan)
+	if err != nil || len(data.TimeSeriesSlot) == 0 {
+		return usecases.ChargePlan{}, api.ErrDataNotAvailable
+	}
+
+	start := time.Now()
+	if data.TimePeriod != nil && data.TimePeriod.StartTime != nil {
+		if value, err := data.TimePeriod.StartTime.GetTime(); err == nil {
+			start = value
+		}
+	}
+
+	var result usecases.ChargePlan
+	for _, slot := range data.TimeSeriesSlot {
+		item := usecases.ChargePlanSlotValue{
+			Start:    start,
+			Value:    optionalValue(slot.Value),
+			MinValue: optionalValue(slot.MinValue),
+			MaxValue: optionalValue(slot.MaxValue),
+		}
+
+		if slot.TimePeriod != nil && slot.TimePeriod.StartTime != nil {
+			if value, err := slot.TimePeriod.StartTime.GetTime(); err == nil {
+				item.Start = value
+			}
+		}
+		if slot.TimePeriod != nil && slot.TimePeriod.EndTime != nil {
+			if value, err := slot.TimePeriod.EndTime.GetTime(); err == nil {
+				item.End = value
+			}
+		}
+		if item.End.IsZero() {
+			item.End = item.Start.Add(optionalDuration(slot.Duration))
+		}
+
+		result.Slots = append(result.Slots, item)
+		start = item.End
+	}
+
+	return result, nil
+}
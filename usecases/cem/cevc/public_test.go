@@ -0,0 +1,229 @@
+package cevc
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CemCEVCSuite) Test_EnergyDemand() {
+	_, err := s.sut.EnergyDemand(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.EnergyDemand(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.timeSeriesDescriptions(false)
+	s.timeSeriesValues()
+
+	demand, err := s.sut.EnergyDemand(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2000.0, demand.MinDemand)
+	assert.Equal(s.T(), 10000.0, demand.OptDemand)
+	assert.Equal(s.T(), 50000.0, demand.MaxDemand)
+	assert.Equal(s.T(), time.Duration(0), demand.DurationUntilStart)
+	assert.Equal(s.T(), time.Hour*8, demand.DurationUntilEnd)
+}
+
+func (s *CemCEVCSuite) Test_TimeSlotConstraints() {
+	_, err := s.sut.TimeSlotConstraints(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.TimeSlotConstraints(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.timeSeriesDescriptions(false)
+
+	_, err = s.sut.TimeSlotConstraints(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.timeSeriesConstraints()
+
+	constraints, err := s.sut.TimeSlotConstraints(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), usecases.TimeSlotConstraints{
+		MinSlots:             1,
+		MaxSlots:             10,
+		MinSlotDuration:      time.Minute * 15,
+		MaxSlotDuration:      time.Hour,
+		SlotDurationStepSize: time.Minute * 15,
+	}, constraints)
+}
+
+func (s *CemCEVCSuite) Test_WritePowerLimits() {
+	data := []usecases.DurationSlotValue{
+		{Duration: time.Hour, Value: 11000},
+		{Duration: time.Minute * 30, Value: 4200},
+	}
+
+	_, err := s.sut.WritePowerLimits(s.remoteEntity, data)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.WritePowerLimits(s.remoteEntity, nil)
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	_, err = s.sut.WritePowerLimits(s.remoteEntity, data)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.timeSeriesDescriptions(false)
+	s.timeSeriesConstraints()
+
+	for _, invalid := range [][]usecases.DurationSlotValue{
+		{{Duration: time.Hour * 2, Value: 11000}},
+		{{Duration: time.Minute * 20, Value: 11000}},
+		{{Duration: time.Hour, Value: 22000}},
+	} {
+		_, err = s.sut.WritePowerLimits(s.remoteEntity, invalid)
+		assert.Equal(s.T(), api.ErrConstraintsViolated, err)
+	}
+
+	msgCounter, err := s.sut.WritePowerLimits(s.remoteEntity, data)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	datagram := s.writeHandler.LastDatagram()
+	assert.NotNil(s.T(), datagram)
+	written := datagram.Payload.Cmd[0].TimeSeriesListData
+	assert.NotNil(s.T(), written)
+	assert.Equal(s.T(), model.TimeSeriesIdType(0), *written.TimeSeriesData[0].TimeSeriesId)
+
+	slots := written.TimeSeriesData[0].TimeSeriesSlot
+	assert.Equal(s.T(), 2, len(slots))
+	assert.Equal(s.T(), 11000.0, slots[0].MaxValue.GetValue())
+	assert.Nil(s.T(), slots[0].TimePeriod.EndTime)
+	start, err := slots[1].TimePeriod.StartTime.GetTimeDuration()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour, start)
+	end, err := slots[1].TimePeriod.EndTime.GetTimeDuration()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Minute*90, end)
+}
+
+func (s *CemCEVCSuite) Test_IncentiveConstraints() {
+	_, err := s.sut.IncentiveConstraints(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.IncentiveConstraints(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.incentiveTableConstraints()
+
+	constraints, err := s.sut.IncentiveConstraints(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), usecases.IncentiveSlotConstraints{MinSlots: 1, MaxSlots: 24}, constraints)
+}
+
+func (s *CemCEVCSuite) Test_WriteIncentiveTableDescriptions() {
+	tier := usecases.IncentiveTableDescriptionTier{
+		Id:   1,
+		Type: model.TierTypeTypeDynamicCost,
+		Boundaries: []usecases.TierBoundaryDescription{
+			{Id: 1, Type: model.TierBoundaryTypeTypePowerBoundary, Unit: model.UnitOfMeasurementTypeW},
+		},
+		Incentives: []usecases.IncentiveDescription{
+			{Id: 1, Type: model.IncentiveTypeTypeAbsoluteCost, Currency: model.CurrencyTypeEur},
+		},
+	}
+
+	_, err := s.sut.WriteIncentiveTableDescriptions(s.remoteEntity, []usecases.IncentiveTableDescriptionTier{tier})
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.WriteIncentiveTableDescriptions(s.remoteEntity, nil)
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	_, err = s.sut.WriteIncentiveTableDescriptions(s.remoteEntity, []usecases.IncentiveTableDescriptionTier{tier})
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.incentiveTableDescriptions(true)
+	s.incentiveTableConstraints()
+
+	_, err = s.sut.WriteIncentiveTableDescriptions(s.remoteEntity, []usecases.IncentiveTableDescriptionTier{tier, tier})
+	assert.Equal(s.T(), api.ErrConstraintsViolated, err)
+
+	msgCounter, err := s.sut.WriteIncentiveTableDescriptions(s.remoteEntity, []usecases.IncentiveTableDescriptionTier{tier})
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	datagram := s.writeHandler.LastDatagram()
+	assert.NotNil(s.T(), datagram)
+	written := datagram.Payload.Cmd[0].IncentiveTableDescriptionData
+	assert.NotNil(s.T(), written)
+	description := written.IncentiveTableDescription[0]
+	assert.Equal(s.T(), model.TariffIdType(0), *description.TariffDescription.TariffId)
+	assert.Nil(s.T(), description.TariffDescription.UpdateRequired)
+	assert.Equal(s.T(), model.CurrencyTypeEur, *description.Tier[0].IncentiveDescription[0].Currency)
+	assert.Equal(s.T(), model.UnitOfMeasurementTypeW, *description.Tier[0].BoundaryDescription[0].BoundaryUnit)
+}
+
+func (s *CemCEVCSuite) Test_WriteIncentives() {
+	data := []usecases.DurationSlotValue{
+		{Duration: time.Hour, Value: 0.3},
+		{Duration: time.Hour, Value: 0.2},
+	}
+
+	_, err := s.sut.WriteIncentives(s.remoteEntity, data)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.WriteIncentives(s.remoteEntity, nil)
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	_, err = s.sut.WriteIncentives(s.remoteEntity, data)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.incentiveTableDescriptions(false)
+	s.incentiveTableConstraints()
+
+	_, err = s.sut.WriteIncentives(s.remoteEntity, make([]usecases.DurationSlotValue, 25))
+	assert.Equal(s.T(), api.ErrConstraintsViolated, err)
+
+	msgCounter, err := s.sut.WriteIncentives(s.remoteEntity, data)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	datagram := s.writeHandler.LastDatagram()
+	assert.NotNil(s.T(), datagram)
+	written := datagram.Payload.Cmd[0].IncentiveTableData
+	assert.NotNil(s.T(), written)
+	slots := written.IncentiveTable[0].IncentiveSlot
+	assert.Equal(s.T(), 2, len(slots))
+	assert.Nil(s.T(), slots[0].TimeInterval.EndTime)
+	assert.NotNil(s.T(), slots[1].TimeInterval.EndTime)
+	assert.Equal(s.T(), 0.2, slots[1].Tier[0].Incentive[0].Value.GetValue())
+	assert.Equal(s.T(), model.TierIdType(1), *slots[1].Tier[0].Tier.TierId)
+}
+
+func (s *CemCEVCSuite) Test_ChargePlan() {
+	_, err := s.sut.ChargePlan(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.ChargePlan(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.timeSeriesDescriptions(false)
+	s.timeSeriesValues()
+
+	plan, err := s.sut.ChargePlan(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 2, len(plan.Slots))
+	assert.Equal(s.T(), 11000.0, plan.Slots[0].Value)
+	assert.Equal(s.T(), time.Hour, plan.Slots[0].End.Sub(plan.Slots[0].Start))
+	assert.Equal(s.T(), plan.Slots[0].End, plan.Slots[1].Start)
+	assert.Equal(s.T(), 4200.0, plan.Slots[1].Value)
+}
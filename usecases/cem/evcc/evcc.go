@@ -0,0 +1,141 @@
+package evcc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// EV Commissioning and Configuration, actor CEM
+//
+// Used e.g. by a HEMS to get informed about connected EVs and their
+// capabilities
+type EVCC struct {
+	*usecases.UseCase
+}
+
+// creates a new EVCC CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote EVs
+func NewEVCC(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *EVCC {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeEV,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeDeviceClassification,
+		model.FeatureTypeTypeDeviceDiagnosis,
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeIdentification,
+	}
+
+	e := &EVCC{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeEVCommissioningAndConfiguration,
+		"1.0.1",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3, 4, 5, 6, 7, 8},
+		[]model.UseCaseActorType{model.UseCaseActorTypeEV},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*EVCC)(nil)
+var _ usecases.EntityHandlerInterface = (*EVCC)(nil)
+
+// report the connected EV and request its initial data
+func (e *EVCC) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	e.ReportEvent(entity, EvConnected)
+
+	if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, entity); err == nil {
+		if _, err := deviceConfiguration.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if deviceClassification, err := features.NewDeviceClassification(e.LocalEntity, entity); err == nil {
+		if _, err := deviceClassification.RequestManufacturerDetails(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if deviceDiagnosis, err := features.NewDeviceDiagnosis(e.LocalEntity, entity); err == nil {
+		if _, err := deviceDiagnosis.RequestState(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestParameterDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if identification, err := features.NewIdentification(e.LocalEntity, entity); err == nil {
+		if _, err := identification.RequestValues(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *EVCC) EntityDisconnected(entity spineapi.EntityRemoteInterface) {
+	e.ReportEvent(entity, EvDisconnected)
+}
+
+func (e *EVCC) HandleDataChange(payload spineapi.EventPayload) {
+	switch payload.Data.(type) {
+	case *model.DeviceConfigurationKeyValueDescriptionListDataType:
+		if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := deviceConfiguration.RequestKeyValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.DeviceConfigurationKeyValueListDataType:
+		if _, err := e.CommunicationStandard(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateCommunicationStandard)
+		}
+
+		if _, err := e.AsymmetricChargingSupport(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateAsymmetricChargingSupport)
+		}
+
+	case *model.DeviceClassificationManufacturerDataType:
+		if _, err := e.ManufacturerData(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateManufacturerData)
+		}
+
+	case *model.DeviceDiagnosisStateDataType:
+		if _, err := e.IsInSleepMode(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateIsInSleepMode)
+		}
+
+	case *model.ElectricalConnectionParameterDescriptionListDataType:
+		if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := electricalConnection.RequestPermittedValueSets(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.ElectricalConnectionPermittedValueSetListDataType:
+		if _, _, _, err := e.ChargingPowerLimits(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateChargingPowerLimits)
+		}
+
+	case *model.IdentificationListDataType:
+		if _, err := e.Identifications(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateIdentifications)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package evcc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCemEVCCSuite(t *testing.T) {
+	suite.Run(t, new(CemEVCCSuite))
+}
+
+type CemEVCCSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *EVCC
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *CemEVCCSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CemEVCCSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *CemEVCCSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEV,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+					model.FunctionTypeDeviceConfigurationKeyValueListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceClassification,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceClassificationManufacturerData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceDiagnosis,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceDiagnosisStateData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+					model.FunctionTypeElectricalConnectionPermittedValueSetListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeIdentification,
+				Functions: []model.FunctionType{
+					model.FunctionTypeIdentificationListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewEVCC(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *CemEVCCSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeEV,
+		model.UseCaseNameTypeEVCommissioningAndConfiguration, true)
+	s.sut.HandleEvent(payload)
+}
+
+func (s *CemEVCCSuite) keyValues() spineapi.EventPayload {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+		&model.DeviceConfigurationKeyValueDescriptionListDataType{
+			DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeCommunicationsStandard),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeString),
+				},
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeAsymmetricChargingSupported),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeBoolean),
+				},
+			},
+		})
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData,
+		&model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						String: util.Ptr(model.DeviceConfigurationKeyValueStringTypeISO151182ED1),
+					},
+				},
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						Boolean: util.Ptr(true),
+					},
+				},
+			},
+		})
+}
+
+func (s *CemEVCCSuite) manufacturerData() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceClassification,
+		model.FunctionTypeDeviceClassificationManufacturerData,
+		&model.DeviceClassificationManufacturerDataType{
+			BrandName: util.Ptr(model.DeviceClassificationStringType("brand")),
+		})
+}
+
+func (s *CemEVCCSuite) state(state model.DeviceDiagnosisOperatingStateType) spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceDiagnosis,
+		model.FunctionTypeDeviceDiagnosisStateData,
+		&model.DeviceDiagnosisStateDataType{
+			OperatingState: util.Ptr(state),
+		})
+}
+
+func (s *CemEVCCSuite) parameterDescriptions() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+		&model.ElectricalConnectionParameterDescriptionListDataType{
+			ElectricalConnectionParameterDescriptionData: []model.ElectricalConnectionParameterDescriptionDataType{
+				{
+					ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+					ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(0)),
+					ScopeType:              util.Ptr(model.ScopeTypeTypeACPowerTotal),
+				},
+			},
+		})
+}
+
+func (s *CemEVCCSuite) permittedValueSets() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionPermittedValueSetListData,
+		&model.ElectricalConnectionPermittedValueSetListDataType{
+			ElectricalConnectionPermittedValueSetData: []model.ElectricalConnectionPermittedValueSetDataType{
+				{
+					ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+					ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(0)),
+					PermittedValueSet: []model.ScaledNumberSetType{
+						{
+							Value: []model.ScaledNumberType{
+								*model.NewScaledNumberType(0.1),
+							},
+							Range: []model.ScaledNumberRangeType{
+								{
+									Min: model.NewScaledNumberType(4140),
+									Max: model.NewScaledNumberType(22080),
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+}
+
+func (s *CemEVCCSuite) identifications() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeIdentification,
+		model.FunctionTypeIdentificationListData,
+		&model.IdentificationListDataType{
+			IdentificationData: []model.IdentificationDataType{
+				{
+					IdentificationId:    util.Ptr(model.IdentificationIdType(0)),
+					IdentificationType:  util.Ptr(model.IdentificationTypeTypeEui48),
+					IdentificationValue: util.Ptr(model.IdentificationValueType("00:11:22:33:44:55")),
+				},
+			},
+		})
+}
+
+func (s *CemEVCCSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeDeviceClassification,
+		model.FeatureTypeTypeDeviceDiagnosis,
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeIdentification,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeCEM, model.UseCaseNameTypeEVCommissioningAndConfiguration))
+}
+
+func (s *CemEVCCSuite) Test_EVConnected() {
+	assert.False(s.T(), s.sut.EVConnected(s.remoteEntity))
+
+	s.connect()
+
+	assert.True(s.T(), s.sut.EVConnected(s.remoteEntity))
+	assert.True(s.T(), s.hasEvent(EvConnected))
+	// subscriptions and requests got sent
+	assert.True(s.T(), s.writeHandler.Count() >= 10)
+
+	s.sut.HandleEvent(spineapi.EventPayload{
+		Ski:        testhelper.RemoteSki,
+		EventType:  spineapi.EventTypeEntityChange,
+		ChangeType: spineapi.ElementChangeRemove,
+		Device:     s.remoteEntity.Device(),
+		Entity:     s.remoteEntity,
+	})
+
+	assert.False(s.T(), s.sut.EVConnected(s.remoteEntity))
+	assert.True(s.T(), s.hasEvent(EvDisconnected))
+}
+
+func (s *CemEVCCSuite) Test_HandleDataChange() {
+	s.connect()
+
+	s.sut.HandleEvent(s.keyValues())
+	assert.True(s.T(), s.hasEvent(DataUpdateCommunicationStandard))
+	assert.True(s.T(), s.hasEvent(DataUpdateAsymmetricChargingSupport))
+
+	s.sut.HandleEvent(s.manufacturerData())
+	assert.True(s.T(), s.hasEvent(DataUpdateManufacturerData))
+
+	s.sut.HandleEvent(s.state(model.DeviceDiagnosisOperatingStateTypeNormalOperation))
+	assert.True(s.T(), s.hasEvent(DataUpdateIsInSleepMode))
+
+	payload := s.parameterDescriptions()
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the permitted value sets got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.permittedValueSets())
+	assert.True(s.T(), s.hasEvent(DataUpdateChargingPowerLimits))
+
+	s.sut.HandleEvent(s.identifications())
+	assert.True(s.T(), s.hasEvent(DataUpdateIdentifications))
+}
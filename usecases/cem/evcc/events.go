@@ -0,0 +1,41 @@
+package evcc
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// An EV got connected
+	EvConnected api.EventType = "cem-evcc-EvConnected"
+
+	// An EV got disconnected
+	EvDisconnected api.EventType = "cem-evcc-EvDisconnected"
+
+	// EV communication standard data was updated
+	//
+	// Use `CommunicationStandard` to get the current data
+	DataUpdateCommunicationStandard api.EventType = "cem-evcc-DataUpdateCommunicationStandard"
+
+	// EV asymmetric charging data was updated
+	//
+	// Use `AsymmetricChargingSupport` to get the current data
+	DataUpdateAsymmetricChargingSupport api.EventType = "cem-evcc-DataUpdateAsymmetricChargingSupport"
+
+	// EV identification data was updated
+	//
+	// Use `Identifications` to get the current data
+	DataUpdateIdentifications api.EventType = "cem-evcc-DataUpdateIdentifications"
+
+	// EV manufacturer data was updated
+	//
+	// Use `ManufacturerData` to get the current data
+	DataUpdateManufacturerData api.EventType = "cem-evcc-DataUpdateManufacturerData"
+
+	// EV charging power limits were updated
+	//
+	// Use `ChargingPowerLimits` to get the current data
+	DataUpdateChargingPowerLimits api.EventType = "cem-evcc-DataUpdateChargingPowerLimits"
+
+	// EV sleep mode state was updated
+	//
+	// Use `IsInSleepMode` to get the current data
+	DataUpdateIsInSleepMode api.EventType = "cem-evcc-DataUpdateIsInSleepMode"
+)
@@ -0,0 +1,219 @@
+package evcc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Scenario 1 & 8
+
+// return if the EV is connected
+//
+// parameters:
+//   - entity: the entity of the EV
+func (e *EVCC) EVConnected(entity spineapi.EntityRemoteInterface) bool {
+	return e.HasRemoteEntity(entity)
+}
+
+// Scenario 2
+
+// return the communication standard used by the EV
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - the standard, e.g. iec61851 or iso15118-2ed1
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no description or value is available
+//   - and others
+func (e *EVCC) CommunicationStandard(entity spineapi.EntityRemoteInterface) (model.DeviceConfigurationKeyValueStringType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return "", api.ErrUsecCaseNotSupported
+	}
+
+	return internal.KeyValueString(e.LocalEntity, entity, model.DeviceConfigurationKeyNameTypeCommunicationsStandard)
+}
+
+// Scenario 3
+
+// return if the EV supports asymmetric charging
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no description or value is available
+//   - and others
+func (e *EVCC) AsymmetricChargingSupport(entity spineapi.EntityRemoteInterface) (bool, error) {
+	if !e.HasRemoteEntity(entity) {
+		return false, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.KeyValueBoolean(e.LocalEntity, entity, model.DeviceConfigurationKeyNameTypeAsymmetricChargingSupported)
+}
+
+// Scenario 4
+
+// return the identifications of the EV
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - the identifications, e.g. the MAC address of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no identification is available
+//   - and others
+func (e *EVCC) Identifications(entity spineapi.EntityRemoteInterface) ([]usecases.IdentificationItem, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	identification, err := features.NewIdentification(e.LocalEntity, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := identification.GetValues()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []usecases.IdentificationItem
+	for _, item := range values {
+		if item.IdentificationValue == nil {
+			continue
+		}
+
+		identification := usecases.IdentificationItem{
+			Value: string(*item.IdentificationValue),
+		}
+		if item.IdentificationType != nil {
+			identification.ValueType = *item.IdentificationType
+		}
+
+		result = append(result, identification)
+	}
+
+	if len(result) == 0 {
+		return nil, api.ErrDataNotAvailable
+	}
+
+	return result, nil
+}
+
+// Scenario 5
+
+// return the manufacturer details of the EV
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no manufacturer details are available
+//   - and others
+func (e *EVCC) ManufacturerData(entity spineapi.EntityRemoteInterface) (usecases.ManufacturerData, error) {
+	if !e.HasRemoteEntity(entity) {
+		return usecases.ManufacturerData{}, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.ManufacturerData(e.LocalEntity, entity)
+}
+
+// Scenario 6
+
+// return the minimum, maximum and standby charging power of the EV in W
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no parameter description is available
+//   - ErrDataNotAvailable if no permitted value set is available
+//   - and others
+func (e *EVCC) ChargingPowerLimits(entity spineapi.EntityRemoteInterface) (float64, float64, float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, 0, 0, api.ErrUsecCaseNotSupported
+	}
+
+	electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	param, err := electricalConnection.GetParameterDescriptionForScopeType(model.ScopeTypeTypeACPowerTotal)
+	if err != nil || param.ParameterId == nil {
+		return 0, 0, 0, api.ErrMetadataNotAvailable
+	}
+
+	data, err := electricalConnection.GetPermittedValueSetForParameterId(*param.ParameterId)
+	if err != nil || len(data.PermittedValueSet) == 0 {
+		return 0, 0, 0, api.ErrDataNotAvailable
+	}
+
+	var minimum, maximum, standby float64
+	found := false
+	for _, set := range data.PermittedValueSet {
+		if len(set.Value) > 0 {
+			standby = set.Value[0].GetValue()
+		}
+
+		for _, item := range set.Range {
+			if item.Min != nil {
+				minimum = item.Min.GetValue()
+				found = true
+			}
+			if item.Max != nil {
+				maximum = item.Max.GetValue()
+				found = true
+			}
+		}
+	}
+
+	if !found {
+		return 0, 0, 0, api.ErrDataNotAvailable
+	}
+
+	return minimum, maximum, standby, nil
+}
+
+// Scenario 7
+
+// return if the EV is in sleep mode
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no operating state is available
+//   - and others
+func (e *EVCC) IsInSleepMode(entity spineapi.EntityRemoteInterface) (bool, error) {
+	if !e.HasRemoteEntity(entity) {
+		return false, api.ErrUsecCaseNotSupported
+	}
+
+	deviceDiagnosis, err := features.NewDeviceDiagnosis(e.LocalEntity, entity)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := deviceDiagnosis.GetState()
+	if err != nil || data.OperatingState == nil {
+		return false, api.ErrDataNotAvailable
+	}
+
+	return *data.OperatingState == model.DeviceDiagnosisOperatingStateTypeStandby, nil
+}
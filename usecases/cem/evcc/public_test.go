@@ -0,0 +1,122 @@
+package evcc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CemEVCCSuite) Test_CommunicationStandard() {
+	_, err := s.sut.CommunicationStandard(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.CommunicationStandard(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	s.keyValues()
+
+	value, err := s.sut.CommunicationStandard(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), model.DeviceConfigurationKeyValueStringTypeISO151182ED1, value)
+}
+
+func (s *CemEVCCSuite) Test_AsymmetricChargingSupport() {
+	_, err := s.sut.AsymmetricChargingSupport(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.AsymmetricChargingSupport(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	s.keyValues()
+
+	value, err := s.sut.AsymmetricChargingSupport(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), value)
+}
+
+func (s *CemEVCCSuite) Test_Identifications() {
+	_, err := s.sut.Identifications(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.Identifications(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	s.identifications()
+
+	values, err := s.sut.Identifications(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []usecases.IdentificationItem{
+		{
+			Value:     "00:11:22:33:44:55",
+			ValueType: model.IdentificationTypeTypeEui48,
+		},
+	}, values)
+}
+
+func (s *CemEVCCSuite) Test_ManufacturerData() {
+	_, err := s.sut.ManufacturerData(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.ManufacturerData(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	s.manufacturerData()
+
+	data, err := s.sut.ManufacturerData(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "brand", data.BrandName)
+}
+
+func (s *CemEVCCSuite) Test_ChargingPowerLimits() {
+	_, _, _, err := s.sut.ChargingPowerLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, _, _, err = s.sut.ChargingPowerLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.parameterDescriptions()
+
+	_, _, _, err = s.sut.ChargingPowerLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.permittedValueSets()
+
+	minimum, maximum, standby, err := s.sut.ChargingPowerLimits(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4140.0, minimum)
+	assert.Equal(s.T(), 22080.0, maximum)
+	assert.Equal(s.T(), 0.1, standby)
+}
+
+func (s *CemEVCCSuite) Test_IsInSleepMode() {
+	_, err := s.sut.IsInSleepMode(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.IsInSleepMode(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.state(model.DeviceDiagnosisOperatingStateTypeNormalOperation)
+
+	value, err := s.sut.IsInSleepMode(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), value)
+
+	s.state(model.DeviceDiagnosisOperatingStateTypeStandby)
+
+	value, err = s.sut.IsInSleepMode(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), value)
+}
@@ -0,0 +1,155 @@
+package evcem
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Measurement of Electricity during EV Charging, actor CEM
+//
+// Used e.g. by a HEMS to monitor the charging currents, power and energy
+// of a connected EV
+type EVCEM struct {
+	*usecases.UseCase
+
+	// provides the grid voltage used to calculate the power if the
+	// EVSE only reports currents
+	service api.ServiceInterface
+}
+
+// creates a new EVCEM CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote EVs
+func NewEVCEM(service api.ServiceInterface, localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *EVCEM {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeEV,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	}
+
+	e := &EVCEM{
+		service: service,
+	}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeMeasurementOfElectricityDuringEVCharging,
+		"1.0.1",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3},
+		[]model.UseCaseActorType{model.UseCaseActorTypeEV},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*EVCEM)(nil)
+var _ usecases.EntityHandlerInterface = (*EVCEM)(nil)
+
+// request the descriptions required to interpret the measurements
+func (e *EVCEM) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := electricalConnection.RequestParameterDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if measurement, err := features.NewMeasurement(e.LocalEntity, entity); err == nil {
+		if _, err := measurement.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := measurement.RequestConstraints(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *EVCEM) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *EVCEM) HandleDataChange(payload spineapi.EventPayload) {
+	switch data := payload.Data.(type) {
+	case *model.MeasurementDescriptionListDataType:
+		if measurement, err := features.NewMeasurement(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := measurement.RequestValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.MeasurementListDataType:
+		e.handleMeasurements(payload.Entity, data)
+	}
+}
+
+// report the events for the updated measurements
+//
+// if the power is calculated from the currents, updated currents
+// are also reported as updated power
+func (e *EVCEM) handleMeasurements(entity spineapi.EntityRemoteInterface, data *model.MeasurementListDataType) {
+	var events []api.EventType
+	addEvent := func(event api.EventType) {
+		for _, item := range events {
+			if item == event {
+				return
+			}
+		}
+		events = append(events, event)
+	}
+
+	for _, scope := range internal.MeasurementScopesOfData(e.LocalEntity, entity, data) {
+		switch scope {
+		case model.ScopeTypeTypeACCurrent:
+			if _, err := e.CurrentPerPhase(entity); err != nil {
+				continue
+			}
+			addEvent(DataUpdateCurrentPerPhase)
+
+			if _, err := e.measuredPowerPerPhase(entity); err != nil {
+				addEvent(DataUpdatePowerPerPhase)
+				if _, err := e.measuredPower(entity); err != nil {
+					addEvent(DataUpdatePower)
+				}
+			}
+
+		case model.ScopeTypeTypeACPower:
+			if _, err := e.PowerPerPhase(entity); err != nil {
+				continue
+			}
+			addEvent(DataUpdatePowerPerPhase)
+
+			if _, err := e.measuredPower(entity); err != nil {
+				addEvent(DataUpdatePower)
+			}
+
+		case model.ScopeTypeTypeACPowerTotal:
+			if _, err := e.Power(entity); err == nil {
+				addEvent(DataUpdatePower)
+			}
+
+		case model.ScopeTypeTypeCharge:
+			if _, err := e.EnergyCharged(entity); err == nil {
+				addEvent(DataUpdateEnergyCharged)
+			}
+		}
+	}
+
+	for _, event := range events {
+		e.ReportEvent(entity, event)
+	}
+}
@@ -0,0 +1,205 @@
+package evcem
+
+import (
+	"crypto/tls"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/mocks"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCemEVCEMSuite(t *testing.T) {
+	suite.Run(t, new(CemEVCEMSuite))
+}
+
+type CemEVCEMSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *EVCEM
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *CemEVCEMSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CemEVCEMSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *CemEVCEMSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEV,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeMeasurement,
+				Functions: []model.FunctionType{
+					model.FunctionTypeMeasurementDescriptionListData,
+					model.FunctionTypeMeasurementConstraintsListData,
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionDescriptionListData,
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+				},
+			},
+		},
+	)
+
+	configuration, err := api.NewConfiguration("vendor", "brand", "model", "serial",
+		model.DeviceTypeTypeEnergyManagementSystem, []model.EntityTypeType{model.EntityTypeTypeCEM},
+		4567, tls.Certificate{}, 230, time.Second*4)
+	assert.Nil(s.T(), err)
+
+	service := mocks.NewServiceInterface(s.T())
+	service.EXPECT().Configuration().Return(configuration).Maybe()
+
+	s.sut = NewEVCEM(service, s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *CemEVCEMSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeEV,
+		model.UseCaseNameTypeMeasurementOfElectricityDuringEVCharging, true)
+	s.sut.HandleEvent(payload)
+}
+
+// the measurements of the remote entity, ordered by their ids
+var measurements = []struct {
+	measurementType model.MeasurementTypeType
+	scope           model.ScopeTypeType
+	phase           model.ElectricalConnectionPhaseNameType
+	value           float64
+}{
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeA, 10},
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeB, 11},
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeC, 12},
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPower, model.ElectricalConnectionPhaseNameTypeA, 2300},
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPower, model.ElectricalConnectionPhaseNameTypeB, 2530},
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPower, model.ElectricalConnectionPhaseNameTypeC, 2760},
+	{model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeCharge, model.ElectricalConnectionPhaseNameTypeAbc, 1500},
+}
+
+// add the descriptions of the first count measurements
+func (s *CemEVCEMSuite) descriptions(count int) spineapi.EventPayload {
+	params := &model.ElectricalConnectionParameterDescriptionListDataType{}
+	descriptions := &model.MeasurementDescriptionListDataType{}
+	for id, item := range measurements[:count] {
+		params.ElectricalConnectionParameterDescriptionData = append(params.ElectricalConnectionParameterDescriptionData,
+			model.ElectricalConnectionParameterDescriptionDataType{
+				ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				MeasurementId:          util.Ptr(model.MeasurementIdType(id)),
+				AcMeasuredPhases:       util.Ptr(item.phase),
+			})
+		descriptions.MeasurementDescriptionData = append(descriptions.MeasurementDescriptionData,
+			model.MeasurementDescriptionDataType{
+				MeasurementId:   util.Ptr(model.MeasurementIdType(id)),
+				MeasurementType: util.Ptr(item.measurementType),
+				CommodityType:   util.Ptr(model.CommodityTypeTypeElectricity),
+				ScopeType:       util.Ptr(item.scope),
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData, params)
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementDescriptionListData, descriptions)
+}
+
+func (s *CemEVCEMSuite) values() spineapi.EventPayload {
+	data := &model.MeasurementListDataType{}
+	for id, item := range measurements {
+		data.MeasurementData = append(data.MeasurementData, model.MeasurementDataType{
+			MeasurementId: util.Ptr(model.MeasurementIdType(id)),
+			Value:         model.NewScaledNumberType(item.value),
+		})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData, data)
+}
+
+func (s *CemEVCEMSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeCEM, model.UseCaseNameTypeMeasurementOfElectricityDuringEVCharging))
+}
+
+func (s *CemEVCEMSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := s.descriptions(len(measurements))
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the measurement values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.values())
+	for _, event := range []api.EventType{
+		DataUpdateCurrentPerPhase,
+		DataUpdatePowerPerPhase,
+		DataUpdatePower,
+		DataUpdateEnergyCharged,
+	} {
+		assert.True(s.T(), s.hasEvent(event), event)
+	}
+}
+
+func (s *CemEVCEMSuite) Test_HandleDataChange_CurrentsOnly() {
+	s.connect()
+	s.descriptions(3)
+
+	s.sut.HandleEvent(s.values())
+	for _, event := range []api.EventType{
+		DataUpdateCurrentPerPhase,
+		DataUpdatePowerPerPhase,
+		DataUpdatePower,
+	} {
+		assert.True(s.T(), s.hasEvent(event), event)
+	}
+	assert.False(s.T(), s.hasEvent(DataUpdateEnergyCharged))
+}
@@ -0,0 +1,25 @@
+package evcem
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// EV phase specific charging current data was updated
+	//
+	// Use `CurrentPerPhase` to get the current data
+	DataUpdateCurrentPerPhase api.EventType = "cem-evcem-DataUpdateCurrentPerPhase"
+
+	// EV phase specific charging power data was updated
+	//
+	// Use `PowerPerPhase` to get the current data
+	DataUpdatePowerPerPhase api.EventType = "cem-evcem-DataUpdatePowerPerPhase"
+
+	// EV total charging power data was updated
+	//
+	// Use `Power` to get the current data
+	DataUpdatePower api.EventType = "cem-evcem-DataUpdatePower"
+
+	// EV charged energy data was updated
+	//
+	// Use `EnergyCharged` to get the current data
+	DataUpdateEnergyCharged api.EventType = "cem-evcem-DataUpdateEnergyCharged"
+)
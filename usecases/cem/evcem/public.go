@@ -0,0 +1,171 @@
+package evcem
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the phases of phase specific measurements
+var phases = []model.ElectricalConnectionPhaseNameType{
+	model.ElectricalConnectionPhaseNameTypeA,
+	model.ElectricalConnectionPhaseNameTypeB,
+	model.ElectricalConnectionPhaseNameTypeC,
+}
+
+// return the phase specific power measured by the EVSE
+func (e *EVCEM) measuredPowerPerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypePower,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACPower,
+	}
+
+	return internal.MeasurementPhaseValues(e.LocalEntity, entity, filter, phases,
+		model.ElectricalConnectionPhaseNameTypeNone, model.EnergyDirectionTypeConsume)
+}
+
+// return the total power measured by the EVSE
+func (e *EVCEM) measuredPower(entity spineapi.EntityRemoteInterface) (float64, error) {
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypePower,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACPowerTotal,
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, model.EnergyDirectionTypeConsume)
+}
+
+// Scenario 1
+
+// return the phase specific charging currents of the EV in A
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - the values of the available phases, ordered by phase A, B and C
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *EVCEM) CurrentPerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeCurrent,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACCurrent,
+	}
+
+	return internal.MeasurementPhaseValues(e.LocalEntity, entity, filter, phases,
+		model.ElectricalConnectionPhaseNameTypeNone, model.EnergyDirectionTypeConsume)
+}
+
+// Scenario 2
+
+// return the phase specific charging power of the EV in W
+//
+// if the EVSE does not provide power measurements, the power is calculated
+// from the phase specific currents and the configured grid voltage
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - the values of the available phases, ordered by phase A, B and C
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *EVCEM) PowerPerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	if values, err := e.measuredPowerPerPhase(entity); err == nil {
+		return values, nil
+	}
+
+	currents, err := e.CurrentPerPhase(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	voltage := e.service.Configuration().Voltage()
+
+	result := make([]float64, 0, len(currents))
+	for _, current := range currents {
+		result = append(result, current*voltage)
+	}
+
+	return result, nil
+}
+
+// return the total charging power of the EV in W
+//
+// if the EVSE does not provide a total power measurement, the phase
+// specific power is summed up
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *EVCEM) Power(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	if value, err := e.measuredPower(entity); err == nil {
+		return value, nil
+	}
+
+	values, err := e.PowerPerPhase(entity)
+	if err != nil {
+		return 0, err
+	}
+
+	var result float64
+	for _, value := range values {
+		result += value
+	}
+
+	return result, nil
+}
+
+// Scenario 3
+
+// return the energy charged by the EV in the current charging session in Wh
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *EVCEM) EnergyCharged(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeEnergy,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeCharge,
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, "")
+}
@@ -0,0 +1,79 @@
+package evcem
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CemEVCEMSuite) Test_CurrentPerPhase() {
+	_, err := s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions(len(measurements))
+
+	_, err = s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values()
+
+	values, err := s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{10, 11, 12}, values)
+}
+
+func (s *CemEVCEMSuite) Test_Power() {
+	_, err := s.sut.PowerPerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.PowerPerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions(len(measurements))
+	s.values()
+
+	values, err := s.sut.PowerPerPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{2300, 2530, 2760}, values)
+
+	value, err := s.sut.Power(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 7590.0, value)
+}
+
+func (s *CemEVCEMSuite) Test_Power_VoltageFallback() {
+	s.connect()
+	s.descriptions(3)
+	s.values()
+
+	values, err := s.sut.PowerPerPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{2300, 2530, 2760}, values)
+
+	value, err := s.sut.Power(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 7590.0, value)
+}
+
+func (s *CemEVCEMSuite) Test_EnergyCharged() {
+	_, err := s.sut.EnergyCharged(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions(len(measurements))
+	s.values()
+
+	value, err := s.sut.EnergyCharged(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1500.0, value)
+}
@@ -0,0 +1,21 @@
+package evsecc
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// An EVSE got connected
+	EvseConnected api.EventType = "cem-evsecc-EvseConnected"
+
+	// An EVSE got disconnected
+	EvseDisconnected api.EventType = "cem-evsecc-EvseDisconnected"
+
+	// EVSE manufacturer data was updated
+	//
+	// Use `ManufacturerData` to get the current data
+	DataUpdateManufacturerData api.EventType = "cem-evsecc-DataUpdateManufacturerData"
+
+	// EVSE operating state was updated
+	//
+	// Use `OperatingState` to get the current data
+	DataUpdateOperatingState api.EventType = "cem-evsecc-DataUpdateOperatingState"
+)
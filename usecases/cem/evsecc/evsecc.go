@@ -0,0 +1,124 @@
+package evsecc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// interface for applications to get notified about data changes of EVSEs
+//
+// implemented by the application, each method is invoked once the
+// corresponding data of a remote EVSE entity was updated
+type EVSECCHandlerInterface interface {
+	// the manufacturer details of the EVSE were updated
+	HandleEVSEManufacturerData(ski string, entity spineapi.EntityRemoteInterface, data usecases.ManufacturerData)
+
+	// the operating state of the EVSE was updated
+	//
+	// failure is true if the EVSE reports a failure, errorCode contains
+	// the last error code reported by the EVSE and may be empty
+	HandleEVSEDeviceState(ski string, entity spineapi.EntityRemoteInterface, failure bool, errorCode string)
+}
+
+// EVSE Commissioning and Configuration, actor CEM
+//
+// Used e.g. by a HEMS to get informed about connected EVSEs, their
+// manufacturer details and their operating state
+type EVSECC struct {
+	*usecases.UseCase
+
+	handler EVSECCHandlerInterface
+}
+
+// creates a new EVSECC CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote EVSEs, handler is optional
+// and invoked with the updated data of remote EVSEs
+func NewEVSECC(
+	localEntity spineapi.EntityLocalInterface,
+	eventCB api.EntityEventCallback,
+	handler EVSECCHandlerInterface,
+) *EVSECC {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeEVSE,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeDeviceClassification,
+		model.FeatureTypeTypeDeviceDiagnosis,
+	}
+
+	e := &EVSECC{
+		handler: handler,
+	}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeEVSECommissioningAndConfiguration,
+		"1.0.1",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2},
+		[]model.UseCaseActorType{model.UseCaseActorTypeEVSE},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*EVSECC)(nil)
+var _ usecases.EntityHandlerInterface = (*EVSECC)(nil)
+
+// report the connected EVSE and request its initial data
+func (e *EVSECC) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	e.ReportEvent(entity, EvseConnected)
+
+	if deviceClassification, err := features.NewDeviceClassification(e.LocalEntity, entity); err == nil {
+		if _, err := deviceClassification.RequestManufacturerDetails(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if deviceDiagnosis, err := features.NewDeviceDiagnosis(e.LocalEntity, entity); err == nil {
+		if _, err := deviceDiagnosis.RequestState(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *EVSECC) EntityDisconnected(entity spineapi.EntityRemoteInterface) {
+	e.ReportEvent(entity, EvseDisconnected)
+}
+
+func (e *EVSECC) HandleDataChange(payload spineapi.EventPayload) {
+	switch payload.Data.(type) {
+	case *model.DeviceClassificationManufacturerDataType:
+		data, err := e.ManufacturerData(payload.Entity)
+		if err != nil {
+			return
+		}
+
+		e.ReportEvent(payload.Entity, DataUpdateManufacturerData)
+		if e.handler != nil {
+			e.handler.HandleEVSEManufacturerData(payload.Ski, payload.Entity, data)
+		}
+
+	case *model.DeviceDiagnosisStateDataType:
+		state, errorCode, err := e.OperatingState(payload.Entity)
+		if err != nil {
+			return
+		}
+
+		e.ReportEvent(payload.Entity, DataUpdateOperatingState)
+		if e.handler != nil {
+			failure := state == model.DeviceDiagnosisOperatingStateTypeFailure
+			e.handler.HandleEVSEDeviceState(payload.Ski, payload.Entity, failure, errorCode)
+		}
+	}
+}
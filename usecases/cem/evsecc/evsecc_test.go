@@ -0,0 +1,192 @@
+package evsecc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCemEVSECCSuite(t *testing.T) {
+	suite.Run(t, new(CemEVSECCSuite))
+}
+
+type CemEVSECCSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *EVSECC
+
+	events []api.EventType
+	mux    sync.Mutex
+
+	// the data passed to the EVSECCHandlerInterface methods
+	manufacturerData *usecases.ManufacturerData
+	failure          *bool
+	errorCode        string
+}
+
+func (s *CemEVSECCSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CemEVSECCSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+var _ EVSECCHandlerInterface = (*CemEVSECCSuite)(nil)
+
+func (s *CemEVSECCSuite) HandleEVSEManufacturerData(ski string, entity spineapi.EntityRemoteInterface, data usecases.ManufacturerData) {
+	s.manufacturerData = &data
+}
+
+func (s *CemEVSECCSuite) HandleEVSEDeviceState(ski string, entity spineapi.EntityRemoteInterface, failure bool, errorCode string) {
+	s.failure = &failure
+	s.errorCode = errorCode
+}
+
+func (s *CemEVSECCSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.manufacturerData = nil
+	s.failure = nil
+	s.errorCode = ""
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEVSE,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeDeviceClassification,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceClassificationManufacturerData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceDiagnosis,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceDiagnosisStateData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewEVSECC(s.localEntity, s.Event, s)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *CemEVSECCSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeEVSE,
+		model.UseCaseNameTypeEVSECommissioningAndConfiguration, true)
+	s.sut.HandleEvent(payload)
+}
+
+func (s *CemEVSECCSuite) manufacturer() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceClassification,
+		model.FunctionTypeDeviceClassificationManufacturerData,
+		&model.DeviceClassificationManufacturerDataType{
+			BrandName:    util.Ptr(model.DeviceClassificationStringType("brand")),
+			SerialNumber: util.Ptr(model.DeviceClassificationStringType("12345")),
+		})
+}
+
+func (s *CemEVSECCSuite) state(state model.DeviceDiagnosisOperatingStateType, errorCode string) spineapi.EventPayload {
+	data := &model.DeviceDiagnosisStateDataType{
+		OperatingState: util.Ptr(state),
+	}
+	if errorCode != "" {
+		data.LastErrorCode = util.Ptr(model.LastErrorCodeType(errorCode))
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceDiagnosis,
+		model.FunctionTypeDeviceDiagnosisStateData, data)
+}
+
+func (s *CemEVSECCSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeDeviceClassification,
+		model.FeatureTypeTypeDeviceDiagnosis,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeCEM, model.UseCaseNameTypeEVSECommissioningAndConfiguration))
+}
+
+func (s *CemEVSECCSuite) Test_EntityConnected() {
+	s.connect()
+
+	assert.True(s.T(), s.hasEvent(EvseConnected))
+	// subscriptions and requests got sent
+	assert.True(s.T(), s.writeHandler.Count() >= 4)
+
+	s.sut.HandleEvent(spineapi.EventPayload{
+		Ski:        testhelper.RemoteSki,
+		EventType:  spineapi.EventTypeEntityChange,
+		ChangeType: spineapi.ElementChangeRemove,
+		Device:     s.remoteEntity.Device(),
+		Entity:     s.remoteEntity,
+	})
+
+	assert.True(s.T(), s.hasEvent(EvseDisconnected))
+}
+
+func (s *CemEVSECCSuite) Test_HandleDataChange() {
+	s.connect()
+
+	s.sut.HandleEvent(s.manufacturer())
+	assert.True(s.T(), s.hasEvent(DataUpdateManufacturerData))
+	if assert.NotNil(s.T(), s.manufacturerData) {
+		assert.Equal(s.T(), "brand", s.manufacturerData.BrandName)
+		assert.Equal(s.T(), "12345", s.manufacturerData.SerialNumber)
+	}
+
+	s.sut.HandleEvent(s.state(model.DeviceDiagnosisOperatingStateTypeFailure, "E42"))
+	assert.True(s.T(), s.hasEvent(DataUpdateOperatingState))
+	if assert.NotNil(s.T(), s.failure) {
+		assert.True(s.T(), *s.failure)
+	}
+	assert.Equal(s.T(), "E42", s.errorCode)
+
+	s.sut.HandleEvent(s.state(model.DeviceDiagnosisOperatingStateTypeNormalOperation, ""))
+	if assert.NotNil(s.T(), s.failure) {
+		assert.False(s.T(), *s.failure)
+	}
+	assert.Equal(s.T(), "", s.errorCode)
+}
+
+func (s *CemEVSECCSuite) Test_HandleDataChange_WithoutHandler() {
+	s.sut = NewEVSECC(s.localEntity, s.Event, nil)
+	s.connect()
+
+	s.sut.HandleEvent(s.manufacturer())
+	assert.True(s.T(), s.hasEvent(DataUpdateManufacturerData))
+	assert.Nil(s.T(), s.manufacturerData)
+}
@@ -0,0 +1,68 @@
+package evsecc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Scenario 1
+
+// return the manufacturer details of the EVSE
+//
+// parameters:
+//   - entity: the entity of the EVSE
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no manufacturer details are available
+//   - and others
+func (e *EVSECC) ManufacturerData(entity spineapi.EntityRemoteInterface) (usecases.ManufacturerData, error) {
+	if !e.HasRemoteEntity(entity) {
+		return usecases.ManufacturerData{}, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.ManufacturerData(e.LocalEntity, entity)
+}
+
+// Scenario 2
+
+// return the operating state and the last error code of the EVSE
+//
+// parameters:
+//   - entity: the entity of the EVSE
+//
+// return values:
+//   - the operating state of the EVSE
+//   - the last error code reported by the EVSE, empty if none is reported
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no operating state is available
+//   - and others
+func (e *EVSECC) OperatingState(entity spineapi.EntityRemoteInterface) (
+	model.DeviceDiagnosisOperatingStateType, string, error) {
+	if !e.HasRemoteEntity(entity) {
+		return "", "", api.ErrUsecCaseNotSupported
+	}
+
+	deviceDiagnosis, err := features.NewDeviceDiagnosis(e.LocalEntity, entity)
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err := deviceDiagnosis.GetState()
+	if err != nil || data.OperatingState == nil {
+		return "", "", api.ErrDataNotAvailable
+	}
+
+	var errorCode string
+	if data.LastErrorCode != nil {
+		errorCode = string(*data.LastErrorCode)
+	}
+
+	return *data.OperatingState, errorCode, nil
+}
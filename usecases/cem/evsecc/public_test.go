@@ -0,0 +1,48 @@
+package evsecc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CemEVSECCSuite) Test_ManufacturerData() {
+	_, err := s.sut.ManufacturerData(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.ManufacturerData(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	s.manufacturer()
+
+	data, err := s.sut.ManufacturerData(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "brand", data.BrandName)
+	assert.Equal(s.T(), "12345", data.SerialNumber)
+}
+
+func (s *CemEVSECCSuite) Test_OperatingState() {
+	_, _, err := s.sut.OperatingState(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, _, err = s.sut.OperatingState(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.state(model.DeviceDiagnosisOperatingStateTypeNormalOperation, "")
+
+	state, errorCode, err := s.sut.OperatingState(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), model.DeviceDiagnosisOperatingStateTypeNormalOperation, state)
+	assert.Equal(s.T(), "", errorCode)
+
+	s.state(model.DeviceDiagnosisOperatingStateTypeFailure, "E42")
+
+	state, errorCode, err = s.sut.OperatingState(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), model.DeviceDiagnosisOperatingStateTypeFailure, state)
+	assert.Equal(s.T(), "E42", errorCode)
+}
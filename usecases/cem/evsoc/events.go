@@ -0,0 +1,20 @@
+package evsoc
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// EV state of charge data was updated
+	//
+	// Use `StateOfCharge` to get the current data
+	DataUpdateStateOfCharge api.EventType = "cem-evsoc-DataUpdateStateOfCharge"
+
+	// EV nominal capacity data was updated
+	//
+	// Use `NominalCapacity` to get the current data
+	DataUpdateNominalCapacity api.EventType = "cem-evsoc-DataUpdateNominalCapacity"
+
+	// EV actual range data was updated
+	//
+	// Use `ActualRange` to get the current data
+	DataUpdateActualRange api.EventType = "cem-evsoc-DataUpdateActualRange"
+)
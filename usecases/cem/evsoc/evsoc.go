@@ -0,0 +1,111 @@
+package evsoc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// EV State Of Charge, actor CEM
+//
+// Used e.g. by a HEMS to get the state of charge, the nominal capacity
+// and the actual range of a connected EV
+type EVSOC struct {
+	*usecases.UseCase
+}
+
+// creates a new EVSOC CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote EVs
+func NewEVSOC(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *EVSOC {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeEV,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	}
+
+	e := &EVSOC{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeEVStateOfCharge,
+		"1.0.0",
+		"RC1",
+		[]model.UseCaseScenarioSupportType{1, 2, 4},
+		[]model.UseCaseActorType{model.UseCaseActorTypeEV},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*EVSOC)(nil)
+var _ usecases.EntityHandlerInterface = (*EVSOC)(nil)
+
+// request the descriptions required to interpret the measurements
+func (e *EVSOC) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestCharacteristics(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if measurement, err := features.NewMeasurement(e.LocalEntity, entity); err == nil {
+		if _, err := measurement.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *EVSOC) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *EVSOC) HandleDataChange(payload spineapi.EventPayload) {
+	switch data := payload.Data.(type) {
+	case *model.MeasurementDescriptionListDataType:
+		if measurement, err := features.NewMeasurement(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := measurement.RequestValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.MeasurementListDataType:
+		e.handleMeasurements(payload.Entity, data)
+
+	case *model.ElectricalConnectionCharacteristicListDataType:
+		if _, err := e.NominalCapacity(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateNominalCapacity)
+		}
+	}
+}
+
+// report the events for the updated measurements
+func (e *EVSOC) handleMeasurements(entity spineapi.EntityRemoteInterface, data *model.MeasurementListDataType) {
+	for _, scope := range internal.MeasurementScopesOfData(e.LocalEntity, entity, data) {
+		switch scope {
+		case model.ScopeTypeTypeStateOfCharge:
+			if _, err := e.StateOfCharge(entity); err == nil {
+				e.ReportEvent(entity, DataUpdateStateOfCharge)
+			}
+
+		case model.ScopeTypeTypeNominalEnergyCapacity:
+			if _, err := e.NominalCapacity(entity); err == nil {
+				e.ReportEvent(entity, DataUpdateNominalCapacity)
+			}
+
+		case model.ScopeTypeTypeTravelRange:
+			if _, err := e.ActualRange(entity); err == nil {
+				e.ReportEvent(entity, DataUpdateActualRange)
+			}
+		}
+	}
+}
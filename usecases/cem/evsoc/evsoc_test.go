@@ -0,0 +1,190 @@
+package evsoc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCemEVSOCSuite(t *testing.T) {
+	suite.Run(t, new(CemEVSOCSuite))
+}
+
+type CemEVSOCSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *EVSOC
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *CemEVSOCSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CemEVSOCSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *CemEVSOCSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEV,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeMeasurement,
+				Functions: []model.FunctionType{
+					model.FunctionTypeMeasurementDescriptionListData,
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionCharacteristicListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewEVSOC(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity with all scenarios
+func (s *CemEVSOCSuite) connect() {
+	s.connectScenarios(nil)
+}
+
+// announce the use case support of the remote entity with the scenarios
+func (s *CemEVSOCSuite) connectScenarios(scenarios []model.UseCaseScenarioSupportType) {
+	payload := testhelper.SetRemoteUseCaseScenarios(s.remoteEntity, model.UseCaseActorTypeEV,
+		model.UseCaseNameTypeEVStateOfCharge, true, scenarios)
+	s.sut.HandleEvent(payload)
+}
+
+// the measurements of the remote entity, the ids are the index
+var measurements = []struct {
+	measurementType model.MeasurementTypeType
+	commodityType   *model.CommodityTypeType
+	scope           model.ScopeTypeType
+	value           float64
+}{
+	{model.MeasurementTypeTypePercentage, util.Ptr(model.CommodityTypeTypeElectricity), model.ScopeTypeTypeStateOfCharge, 80},
+	{model.MeasurementTypeTypeEnergy, util.Ptr(model.CommodityTypeTypeElectricity), model.ScopeTypeTypeNominalEnergyCapacity, 64000},
+	{model.MeasurementTypeTypeDistance, nil, model.ScopeTypeTypeTravelRange, 250000},
+}
+
+// add the descriptions of the first count measurements
+func (s *CemEVSOCSuite) descriptions(count int) spineapi.EventPayload {
+	data := &model.MeasurementDescriptionListDataType{}
+	for id, item := range measurements[:count] {
+		data.MeasurementDescriptionData = append(data.MeasurementDescriptionData,
+			model.MeasurementDescriptionDataType{
+				MeasurementId:   util.Ptr(model.MeasurementIdType(id)),
+				MeasurementType: util.Ptr(item.measurementType),
+				CommodityType:   item.commodityType,
+				ScopeType:       util.Ptr(item.scope),
+			})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementDescriptionListData, data)
+}
+
+func (s *CemEVSOCSuite) values() spineapi.EventPayload {
+	data := &model.MeasurementListDataType{}
+	for id, item := range measurements {
+		data.MeasurementData = append(data.MeasurementData, model.MeasurementDataType{
+			MeasurementId: util.Ptr(model.MeasurementIdType(id)),
+			Value:         model.NewScaledNumberType(item.value),
+		})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData, data)
+}
+
+func (s *CemEVSOCSuite) characteristics() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionCharacteristicListData,
+		&model.ElectricalConnectionCharacteristicListDataType{
+			ElectricalConnectionCharacteristicListData: []model.ElectricalConnectionCharacteristicDataType{
+				{
+					ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+					CharacteristicId:       util.Ptr(model.ElectricalConnectionCharaceteristicIdType(0)),
+					CharacteristicContext:  util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+					CharacteristicType:     util.Ptr(model.ElectricalConnectionCharacteristicTypeTypeEnergyCapacityNominalMax),
+					Value:                  model.NewScaledNumberType(77000),
+				},
+			},
+		})
+}
+
+func (s *CemEVSOCSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeCEM, model.UseCaseNameTypeEVStateOfCharge))
+}
+
+func (s *CemEVSOCSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := s.descriptions(len(measurements))
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the measurement values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.values())
+	for _, event := range []api.EventType{
+		DataUpdateStateOfCharge,
+		DataUpdateNominalCapacity,
+		DataUpdateActualRange,
+	} {
+		assert.True(s.T(), s.hasEvent(event), event)
+	}
+}
+
+func (s *CemEVSOCSuite) Test_HandleDataChange_Characteristics() {
+	s.connect()
+
+	s.sut.HandleEvent(s.characteristics())
+	assert.True(s.T(), s.hasEvent(DataUpdateNominalCapacity))
+}
@@ -0,0 +1,122 @@
+package evsoc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// return an error if the entity does not support the use case or
+// did not announce support for the scenario
+func (e *EVSOC) checkScenario(entity spineapi.EntityRemoteInterface, scenario model.UseCaseScenarioSupportType) error {
+	if !e.HasRemoteEntity(entity) {
+		return api.ErrUsecCaseNotSupported
+	}
+
+	if !e.IsScenarioAvailableAtEntity(entity, scenario) {
+		return api.ErrNotSupported
+	}
+
+	return nil
+}
+
+// Scenario 1
+
+// return the state of charge of the EV in %
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrNotSupported if the EV does not support the scenario
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *EVSOC) StateOfCharge(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if err := e.checkScenario(entity, 1); err != nil {
+		return 0, err
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypePercentage,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeStateOfCharge,
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, "")
+}
+
+// Scenario 2
+
+// return the nominal capacity of the EV battery in Wh
+//
+// if the EV does not provide a measurement for the capacity, the nominal
+// maximum energy capacity of its electrical connection is used
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrNotSupported if the EV does not support the scenario
+//   - ErrDataNotAvailable if no value is available
+//   - and others
+func (e *EVSOC) NominalCapacity(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if err := e.checkScenario(entity, 2); err != nil {
+		return 0, err
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeEnergy,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeNominalEnergyCapacity,
+	}
+
+	if value, err := internal.MeasurementValue(e.LocalEntity, entity, filter, ""); err == nil {
+		return value, nil
+	}
+
+	electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity)
+	if err != nil {
+		return 0, err
+	}
+
+	characteristic, err := electricalConnection.GetCharacteristicForContextType(
+		model.ElectricalConnectionCharacteristicContextTypeEntity,
+		model.ElectricalConnectionCharacteristicTypeTypeEnergyCapacityNominalMax,
+	)
+	if err != nil || characteristic.Value == nil {
+		return 0, api.ErrDataNotAvailable
+	}
+
+	return characteristic.Value.GetValue(), nil
+}
+
+// Scenario 4
+
+// return the actual range of the EV in m
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrNotSupported if the EV does not support the scenario
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *EVSOC) ActualRange(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if err := e.checkScenario(entity, 4); err != nil {
+		return 0, err
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeDistance,
+		ScopeType:       model.ScopeTypeTypeTravelRange,
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, "")
+}
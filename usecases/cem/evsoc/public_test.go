@@ -0,0 +1,81 @@
+package evsoc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CemEVSOCSuite) Test_StateOfCharge() {
+	_, err := s.sut.StateOfCharge(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.StateOfCharge(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions(len(measurements))
+
+	_, err = s.sut.StateOfCharge(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values()
+
+	value, err := s.sut.StateOfCharge(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 80.0, value)
+}
+
+func (s *CemEVSOCSuite) Test_NominalCapacity() {
+	_, err := s.sut.NominalCapacity(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.NominalCapacity(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	// the electrical connection characteristic is used as a fallback
+	s.characteristics()
+
+	value, err := s.sut.NominalCapacity(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 77000.0, value)
+
+	s.descriptions(len(measurements))
+	s.values()
+
+	value, err = s.sut.NominalCapacity(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 64000.0, value)
+}
+
+func (s *CemEVSOCSuite) Test_ActualRange() {
+	_, err := s.sut.ActualRange(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions(len(measurements))
+	s.values()
+
+	value, err := s.sut.ActualRange(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 250000.0, value)
+}
+
+func (s *CemEVSOCSuite) Test_ScenarioAvailability() {
+	s.connectScenarios([]model.UseCaseScenarioSupportType{1})
+	s.descriptions(len(measurements))
+	s.values()
+
+	value, err := s.sut.StateOfCharge(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 80.0, value)
+
+	_, err = s.sut.NominalCapacity(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrNotSupported, err)
+
+	_, err = s.sut.ActualRange(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrNotSupported, err)
+}
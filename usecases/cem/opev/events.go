@@ -0,0 +1,15 @@
+package opev
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// EV load control obligation limit data was updated
+	//
+	// Use `LoadControlLimits` to get the current data
+	DataUpdateLimit api.EventType = "cem-opev-DataUpdateLimit"
+
+	// EV permitted current limits were updated
+	//
+	// Use `CurrentLimits` to get the current data
+	DataUpdateCurrentLimits api.EventType = "cem-opev-DataUpdateCurrentLimits"
+)
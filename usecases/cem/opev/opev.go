@@ -0,0 +1,98 @@
+package opev
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Overload Protection by EV Charging Current Curtailment, actor CEM
+//
+// Used e.g. by a HEMS to protect the fuses of a household by limiting
+// the phase specific charging currents of a connected EV
+type OPEV struct {
+	*usecases.UseCase
+}
+
+// creates a new OPEV CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote EVs
+func NewOPEV(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *OPEV {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeEV,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeLoadControl,
+		model.FeatureTypeTypeElectricalConnection,
+	}
+
+	e := &OPEV{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeOverloadProtectionByEVChargingCurrentCurtailment,
+		"1.0.1b",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3},
+		[]model.UseCaseActorType{model.UseCaseActorTypeEV},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*OPEV)(nil)
+var _ usecases.EntityHandlerInterface = (*OPEV)(nil)
+
+// bind to the load control required for writing and request the initial data
+func (e *OPEV) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if loadControl, err := features.NewLoadControl(e.LocalEntity, entity); err == nil {
+		if !loadControl.HasBinding() {
+			_, _ = loadControl.Bind()
+		}
+
+		if _, err := loadControl.RequestLimitDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestParameterDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := electricalConnection.RequestPermittedValueSets(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *OPEV) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *OPEV) HandleDataChange(payload spineapi.EventPayload) {
+	switch payload.Data.(type) {
+	case *model.LoadControlLimitDescriptionListDataType:
+		if loadControl, err := features.NewLoadControl(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := loadControl.RequestLimitValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.LoadControlLimitListDataType:
+		if _, err := e.LoadControlLimits(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateLimit)
+		}
+
+	case *model.ElectricalConnectionPermittedValueSetListDataType:
+		if _, _, _, err := e.CurrentLimits(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateCurrentLimits)
+		}
+	}
+}
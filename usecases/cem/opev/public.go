@@ -0,0 +1,92 @@
+package opev
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the filter describing the phase specific obligation limits
+var obligationLimitFilter = internal.LimitFilter{
+	LimitType: model.LoadControlLimitTypeTypeMaxValueLimit,
+	Category:  model.LoadControlCategoryTypeObligation,
+	Direction: model.EnergyDirectionTypeConsume,
+	Scope:     model.ScopeTypeTypeOverloadProtection,
+}
+
+// Scenario 1
+
+// return the permitted phase specific current limits of the EV in A
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - minimums: the minimum current limits, ordered by phase A, B and C
+//   - maximums: the maximum current limits, ordered by phase A, B and C
+//   - pauses: the current limits to pause charging, ordered by phase A, B and C
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit or parameter description is available
+//   - ErrDataNotAvailable if no permitted values are available
+//   - and others
+func (e *OPEV) CurrentLimits(entity spineapi.EntityRemoteInterface) ([]float64, []float64, []float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, nil, nil, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.PhaseLimitRanges(e.LocalEntity, entity, obligationLimitFilter)
+}
+
+// return the phase specific obligation current limits of the EV
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - the limits of the available phases, ordered by phase A, B and C
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit or parameter description is available
+//   - ErrDataNotAvailable if no limit value is available
+//   - and others
+func (e *OPEV) LoadControlLimits(entity spineapi.EntityRemoteInterface) ([]usecases.LoadLimitsPhase, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.PhaseLimits(e.LocalEntity, entity, obligationLimitFilter)
+}
+
+// send new phase specific obligation current limits to the EV
+//
+// the values are adjusted to be within the permitted current limits of
+// the EV, limits of phases not provided or not supported by the EV are
+// not changed
+//
+// parameters:
+//   - entity: the entity of the EV
+//   - limits: the phase specific limits, IsChangeable is ignored
+//   - resultCB: invoked with the result of the write, also if it arrives before this method returns, may be nil
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit or parameter description is available
+//   - ErrNotSupported if the EV does not allow to change the limits
+//   - ErrMissingData if none of the limits is for a phase supported by the EV
+//   - and others
+func (e *OPEV) WriteLoadControlLimits(
+	entity spineapi.EntityRemoteInterface,
+	limits []usecases.LoadLimitsPhase,
+	resultCB func(result model.ResultDataType),
+) (*model.MsgCounterType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.WritePhaseLimits(e.LocalEntity, entity, obligationLimitFilter, limits, resultCB)
+}
@@ -0,0 +1,21 @@
+package oscev
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// EV load control recommendation limit data was updated
+	//
+	// Use `LoadControlLimits` to get the current data
+	// Use `IsRecommendationActive` to check if the EV honors the recommendations
+	DataUpdateLimit api.EventType = "cem-oscev-DataUpdateLimit"
+
+	// EV permitted current limits were updated
+	//
+	// Use `CurrentLimits` to get the current data
+	DataUpdateCurrentLimits api.EventType = "cem-oscev-DataUpdateCurrentLimits"
+
+	// EV data defining the support of recommendations was updated
+	//
+	// Use `IsRecommendationSupported` to get the current data
+	DataUpdateRecommendationSupport api.EventType = "cem-oscev-DataUpdateRecommendationSupport"
+)
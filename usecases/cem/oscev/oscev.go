@@ -0,0 +1,121 @@
+package oscev
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Optimization of Self Consumption during EV Charging, actor CEM
+//
+// Used e.g. by a HEMS to charge a connected EV with surplus PV power by
+// recommending phase specific charging currents
+type OSCEV struct {
+	*usecases.UseCase
+}
+
+// creates a new OSCEV CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote EVs
+func NewOSCEV(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *OSCEV {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeEV,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeLoadControl,
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeDeviceConfiguration,
+	}
+
+	e := &OSCEV{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeOptimizationOfSelfConsumptionDuringEVCharging,
+		"1.0.1b",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3},
+		[]model.UseCaseActorType{model.UseCaseActorTypeEV},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*OSCEV)(nil)
+var _ usecases.EntityHandlerInterface = (*OSCEV)(nil)
+
+// bind to the load control required for writing and request the initial data
+func (e *OSCEV) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if loadControl, err := features.NewLoadControl(e.LocalEntity, entity); err == nil {
+		if !loadControl.HasBinding() {
+			_, _ = loadControl.Bind()
+		}
+
+		if _, err := loadControl.RequestLimitDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestParameterDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := electricalConnection.RequestPermittedValueSets(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, entity); err == nil {
+		if _, err := deviceConfiguration.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *OSCEV) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *OSCEV) HandleDataChange(payload spineapi.EventPayload) {
+	switch payload.Data.(type) {
+	case *model.LoadControlLimitDescriptionListDataType:
+		if loadControl, err := features.NewLoadControl(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := loadControl.RequestLimitValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+		if _, err := e.IsRecommendationSupported(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateRecommendationSupport)
+		}
+
+	case *model.LoadControlLimitListDataType:
+		if _, err := e.LoadControlLimits(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateLimit)
+		}
+
+	case *model.ElectricalConnectionPermittedValueSetListDataType:
+		if _, _, _, err := e.CurrentLimits(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateCurrentLimits)
+		}
+
+	case *model.DeviceConfigurationKeyValueDescriptionListDataType:
+		if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := deviceConfiguration.RequestKeyValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.DeviceConfigurationKeyValueListDataType:
+		if _, err := e.IsRecommendationSupported(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdateRecommendationSupport)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package oscev
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCemOSCEVSuite(t *testing.T) {
+	suite.Run(t, new(CemOSCEVSuite))
+}
+
+type CemOSCEVSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *OSCEV
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *CemOSCEVSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CemOSCEVSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *CemOSCEVSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEV,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeLoadControl,
+				Functions: []model.FunctionType{
+					model.FunctionTypeLoadControlLimitDescriptionListData,
+					model.FunctionTypeLoadControlLimitListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+					model.FunctionTypeElectricalConnectionPermittedValueSetListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+					model.FunctionTypeDeviceConfigurationKeyValueListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewOSCEV(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *CemOSCEVSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeEV,
+		model.UseCaseNameTypeOptimizationOfSelfConsumptionDuringEVCharging, true)
+	s.sut.HandleEvent(payload)
+}
+
+// the phases of the EV, the limit, measurement and parameter ids are the index of the phase
+var phases = []model.ElectricalConnectionPhaseNameType{
+	model.ElectricalConnectionPhaseNameTypeA,
+	model.ElectricalConnectionPhaseNameTypeB,
+	model.ElectricalConnectionPhaseNameTypeC,
+}
+
+func (s *CemOSCEVSuite) descriptions() spineapi.EventPayload {
+	params := &model.ElectricalConnectionParameterDescriptionListDataType{}
+	descriptions := &model.LoadControlLimitDescriptionListDataType{}
+	for id, phase := range phases {
+		params.ElectricalConnectionParameterDescriptionData = append(params.ElectricalConnectionParameterDescriptionData,
+			model.ElectricalConnectionParameterDescriptionDataType{
+				ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				MeasurementId:          util.Ptr(model.MeasurementIdType(id)),
+				AcMeasuredPhases:       util.Ptr(phase),
+			})
+		descriptions.LoadControlLimitDescriptionData = append(descriptions.LoadControlLimitDescriptionData,
+			model.LoadControlLimitDescriptionDataType{
+				LimitId:        util.Ptr(model.LoadControlLimitIdType(id)),
+				LimitType:      util.Ptr(model.LoadControlLimitTypeTypeMaxValueLimit),
+				LimitCategory:  util.Ptr(model.LoadControlCategoryTypeRecommendation),
+				LimitDirection: util.Ptr(model.EnergyDirectionTypeConsume),
+				MeasurementId:  util.Ptr(model.MeasurementIdType(id)),
+				ScopeType:      util.Ptr(model.ScopeTypeTypeSelfConsumption),
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData, params)
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitDescriptionListData, descriptions)
+}
+
+func (s *CemOSCEVSuite) values(changeable bool) spineapi.EventPayload {
+	data := &model.LoadControlLimitListDataType{}
+	for id := range phases {
+		data.LoadControlLimitData = append(data.LoadControlLimitData, model.LoadControlLimitDataType{
+			LimitId:           util.Ptr(model.LoadControlLimitIdType(id)),
+			IsLimitChangeable: util.Ptr(changeable),
+			IsLimitActive:     util.Ptr(true),
+			Value:             model.NewScaledNumberType(16),
+		})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitListData, data)
+}
+
+// permitted values of 6 to 16 A with a pause value of 0.1 A for all phases
+func (s *CemOSCEVSuite) permittedValues() spineapi.EventPayload {
+	data := &model.ElectricalConnectionPermittedValueSetListDataType{}
+	for id := range phases {
+		data.ElectricalConnectionPermittedValueSetData = append(data.ElectricalConnectionPermittedValueSetData,
+			model.ElectricalConnectionPermittedValueSetDataType{
+				ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				PermittedValueSet: []model.ScaledNumberSetType{
+					{
+						Value: []model.ScaledNumberType{*model.NewScaledNumberType(0.1)},
+						Range: []model.ScaledNumberRangeType{
+							{
+								Min: model.NewScaledNumberType(6),
+								Max: model.NewScaledNumberType(16),
+							},
+						},
+					},
+				},
+			})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionPermittedValueSetListData, data)
+}
+
+// set the communication standard of the EV, with VAS support if vas is true
+func (s *CemOSCEVSuite) communicationStandard(
+	standard model.DeviceConfigurationKeyValueStringType, vas bool) spineapi.EventPayload {
+	descriptions := &model.DeviceConfigurationKeyValueDescriptionListDataType{
+		DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+			{
+				KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+				KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeCommunicationsStandard),
+				ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeString),
+			},
+		},
+	}
+	if vas {
+		descriptions.DeviceConfigurationKeyValueDescriptionData = append(descriptions.DeviceConfigurationKeyValueDescriptionData,
+			model.DeviceConfigurationKeyValueDescriptionDataType{
+				KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+				KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeIncentivesWaitIncentiveWriteable),
+				ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData, descriptions)
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData,
+		&model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						String: util.Ptr(standard),
+					},
+				},
+			},
+		})
+}
+
+func (s *CemOSCEVSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeLoadControl,
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeDeviceConfiguration,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeCEM, model.UseCaseNameTypeOptimizationOfSelfConsumptionDuringEVCharging))
+}
+
+func (s *CemOSCEVSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := s.descriptions()
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the limit values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.values(true))
+	assert.True(s.T(), s.hasEvent(DataUpdateLimit))
+
+	s.sut.HandleEvent(s.communicationStandard(model.DeviceConfigurationKeyValueStringTypeISO151182ED2, false))
+	assert.True(s.T(), s.hasEvent(DataUpdateRecommendationSupport))
+
+	s.sut.HandleEvent(s.permittedValues())
+	assert.True(s.T(), s.hasEvent(DataUpdateCurrentLimits))
+}
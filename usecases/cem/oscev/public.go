@@ -0,0 +1,175 @@
+package oscev
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the filter describing the phase specific recommendation limits
+var recommendationLimitFilter = internal.LimitFilter{
+	LimitType: model.LoadControlLimitTypeTypeMaxValueLimit,
+	Category:  model.LoadControlCategoryTypeRecommendation,
+	Direction: model.EnergyDirectionTypeConsume,
+	Scope:     model.ScopeTypeTypeSelfConsumption,
+}
+
+// Scenario 1
+
+// return if the EV supports recommendation limits
+//
+// IEC 61851 does not support recommendations, ISO 15118-2 edition 1 only
+// supports them via value added services (VAS), which the EV announces by
+// providing the incentive keys of its device configuration
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if the communication standard is not available
+//   - and others
+func (e *OSCEV) IsRecommendationSupported(entity spineapi.EntityRemoteInterface) (bool, error) {
+	if !e.HasRemoteEntity(entity) {
+		return false, api.ErrUsecCaseNotSupported
+	}
+
+	standard, err := internal.KeyValueString(e.LocalEntity, entity, model.DeviceConfigurationKeyNameTypeCommunicationsStandard)
+	if err != nil {
+		return false, err
+	}
+
+	switch standard {
+	case model.DeviceConfigurationKeyValueStringTypeIEC61851:
+		return false, nil
+
+	case model.DeviceConfigurationKeyValueStringTypeISO151182ED1:
+		deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, entity)
+		if err != nil {
+			return false, err
+		}
+
+		if _, err := deviceConfiguration.GetDescriptionForKeyName(
+			model.DeviceConfigurationKeyNameTypeIncentivesWaitIncentiveWriteable); err != nil {
+			return false, nil
+		}
+	}
+
+	loadControl, err := features.NewLoadControl(e.LocalEntity, entity)
+	if err != nil {
+		return false, err
+	}
+
+	descriptions, err := loadControl.GetLimitDescriptionsForCategory(model.LoadControlCategoryTypeRecommendation)
+	if err != nil || len(descriptions) == 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// return the permitted phase specific current limits of the EV in A
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - minimums: the minimum current limits, ordered by phase A, B and C
+//   - maximums: the maximum current limits, ordered by phase A, B and C
+//   - pauses: the current limits to pause charging, ordered by phase A, B and C
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit or parameter description is available
+//   - ErrDataNotAvailable if no permitted values are available
+//   - and others
+func (e *OSCEV) CurrentLimits(entity spineapi.EntityRemoteInterface) ([]float64, []float64, []float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, nil, nil, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.PhaseLimitRanges(e.LocalEntity, entity, recommendationLimitFilter)
+}
+
+// return the phase specific recommendation current limits of the EV
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - the limits of the available phases, ordered by phase A, B and C
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit or parameter description is available
+//   - ErrDataNotAvailable if no limit value is available
+//   - and others
+func (e *OSCEV) LoadControlLimits(entity spineapi.EntityRemoteInterface) ([]usecases.LoadLimitsPhase, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.PhaseLimits(e.LocalEntity, entity, recommendationLimitFilter)
+}
+
+// send new phase specific recommendation current limits to the EV
+//
+// the values are adjusted to be within the permitted current limits of
+// the EV, limits of phases not provided or not supported by the EV are
+// not changed
+//
+// parameters:
+//   - entity: the entity of the EV
+//   - limits: the phase specific limits, IsChangeable is ignored
+//   - resultCB: invoked with the result of the write, also if it arrives before this method returns, may be nil
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit or parameter description is available
+//   - ErrNotSupported if the EV does not allow to change the limits
+//   - ErrMissingData if none of the limits is for a phase supported by the EV
+//   - and others
+func (e *OSCEV) WriteLoadControlLimits(
+	entity spineapi.EntityRemoteInterface,
+	limits []usecases.LoadLimitsPhase,
+	resultCB func(result model.ResultDataType),
+) (*model.MsgCounterType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.WritePhaseLimits(e.LocalEntity, entity, recommendationLimitFilter, limits, resultCB)
+}
+
+// Scenario 2
+
+// return if the EV currently honors the recommendation limits
+//
+// parameters:
+//   - entity: the entity of the EV
+//
+// return values:
+//   - true if any of the phase specific recommendation limits is active
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit or parameter description is available
+//   - ErrDataNotAvailable if no limit value is available
+//   - and others
+func (e *OSCEV) IsRecommendationActive(entity spineapi.EntityRemoteInterface) (bool, error) {
+	limits, err := e.LoadControlLimits(entity)
+	if err != nil {
+		return false, err
+	}
+
+	for _, limit := range limits {
+		if limit.IsActive {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,177 @@
+package oscev
+
+import (
+	"sync"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CemOSCEVSuite) Test_CurrentLimits() {
+	_, _, _, err := s.sut.CurrentLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, _, _, err = s.sut.CurrentLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions()
+
+	_, _, _, err = s.sut.CurrentLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.permittedValues()
+
+	minimums, maximums, pauses, err := s.sut.CurrentLimits(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{6, 6, 6}, minimums)
+	assert.Equal(s.T(), []float64{16, 16, 16}, maximums)
+	assert.Equal(s.T(), []float64{0.1, 0.1, 0.1}, pauses)
+}
+
+func (s *CemOSCEVSuite) Test_LoadControlLimits() {
+	_, err := s.sut.LoadControlLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.LoadControlLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions()
+
+	_, err = s.sut.LoadControlLimits(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values(true)
+
+	limits, err := s.sut.LoadControlLimits(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 3, len(limits))
+	for index, limit := range limits {
+		assert.Equal(s.T(), phases[index], limit.Phase)
+		assert.Equal(s.T(), 16.0, limit.Value)
+		assert.True(s.T(), limit.IsActive)
+		assert.True(s.T(), limit.IsChangeable)
+	}
+}
+
+func (s *CemOSCEVSuite) Test_WriteLoadControlLimits() {
+	limits := []usecases.LoadLimitsPhase{
+		{Phase: model.ElectricalConnectionPhaseNameTypeA, IsActive: true, Value: 10},
+		{Phase: model.ElectricalConnectionPhaseNameTypeB, IsActive: true, Value: 20},
+		{Phase: model.ElectricalConnectionPhaseNameTypeC, IsActive: true, Value: 2},
+	}
+
+	_, err := s.sut.WriteLoadControlLimits(s.remoteEntity, limits, nil)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.WriteLoadControlLimits(s.remoteEntity, limits, nil)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions()
+	s.permittedValues()
+
+	var mux sync.Mutex
+	var results []model.ResultDataType
+	resultCB := func(result model.ResultDataType) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		results = append(results, result)
+	}
+
+	msgCounter, err := s.sut.WriteLoadControlLimits(s.remoteEntity, limits, resultCB)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	// the values got clamped to the permitted values of the EV
+	datagram := s.writeHandler.LastDatagram()
+	assert.NotNil(s.T(), datagram)
+	data := datagram.Payload.Cmd[0].LoadControlLimitListData
+	assert.NotNil(s.T(), data)
+	assert.Equal(s.T(), 3, len(data.LoadControlLimitData))
+	assert.Equal(s.T(), 10.0, data.LoadControlLimitData[0].Value.GetValue())
+	assert.Equal(s.T(), 16.0, data.LoadControlLimitData[1].Value.GetValue())
+	assert.Equal(s.T(), 0.1, data.LoadControlLimitData[2].Value.GetValue())
+
+	testhelper.SetRemoteResult(s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		*msgCounter, model.ErrorNumberTypeCommandRejected)
+	assert.Eventually(s.T(), func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+
+		return len(results) == 1 && results[0].ErrorNumber != nil &&
+			*results[0].ErrorNumber == model.ErrorNumberTypeCommandRejected
+	}, time.Second, time.Millisecond*10)
+
+	s.values(false)
+
+	_, err = s.sut.WriteLoadControlLimits(s.remoteEntity, limits, resultCB)
+	assert.Equal(s.T(), api.ErrNotSupported, err)
+}
+
+func (s *CemOSCEVSuite) Test_IsRecommendationSupported() {
+	_, err := s.sut.IsRecommendationSupported(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.IsRecommendationSupported(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	s.communicationStandard(model.DeviceConfigurationKeyValueStringTypeISO151182ED2, false)
+
+	// no recommendation limits are provided
+	value, err := s.sut.IsRecommendationSupported(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), value)
+
+	s.descriptions()
+
+	value, err = s.sut.IsRecommendationSupported(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), value)
+
+	s.communicationStandard(model.DeviceConfigurationKeyValueStringTypeIEC61851, false)
+
+	value, err = s.sut.IsRecommendationSupported(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), value)
+
+	s.communicationStandard(model.DeviceConfigurationKeyValueStringTypeISO151182ED1, false)
+
+	value, err = s.sut.IsRecommendationSupported(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), value)
+
+	s.communicationStandard(model.DeviceConfigurationKeyValueStringTypeISO151182ED1, true)
+
+	value, err = s.sut.IsRecommendationSupported(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), value)
+}
+
+func (s *CemOSCEVSuite) Test_IsRecommendationActive() {
+	_, err := s.sut.IsRecommendationActive(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+
+	_, err = s.sut.IsRecommendationActive(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values(true)
+
+	value, err := s.sut.IsRecommendationActive(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), value)
+}
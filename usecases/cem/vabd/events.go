@@ -0,0 +1,25 @@
+package vabd
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// Battery System (dis)charge power data updated
+	//
+	// Use `Power` to get the current data
+	DataUpdatePower api.EventType = "cem-vabd-DataUpdatePower"
+
+	// Battery System cumulated charge energy data updated
+	//
+	// Use `EnergyCharged` to get the current data
+	DataUpdateEnergyCharged api.EventType = "cem-vabd-DataUpdateEnergyCharged"
+
+	// Battery System cumulated discharge energy data updated
+	//
+	// Use `EnergyDischarged` to get the current data
+	DataUpdateEnergyDischarged api.EventType = "cem-vabd-DataUpdateEnergyDischarged"
+
+	// Battery System state of charge data updated
+	//
+	// Use `StateOfCharge` to get the current data
+	DataUpdateStateOfCharge api.EventType = "cem-vabd-DataUpdateStateOfCharge"
+)
@@ -0,0 +1,112 @@
+package vabd
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// return the measurement value of the battery system matching the filter
+func (e *VABD) value(
+	entity spineapi.EntityRemoteInterface,
+	filter internal.MeasurementFilter,
+	energyDirection model.EnergyDirectionType,
+) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, energyDirection)
+}
+
+// Scenario 1
+
+// return the current (dis)charging power of the battery system in W
+//
+// parameters:
+//   - entity: the entity of the battery system
+//
+// return values:
+//   - positive values are used for charging, negative values for discharging
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *VABD) Power(entity spineapi.EntityRemoteInterface) (float64, error) {
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypePower,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACPowerTotal,
+	}
+
+	return e.value(entity, filter, model.EnergyDirectionTypeConsume)
+}
+
+// Scenario 2
+
+// return the cumulated energy charged into the battery system in Wh
+//
+// parameters:
+//   - entity: the entity of the battery system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *VABD) EnergyCharged(entity spineapi.EntityRemoteInterface) (float64, error) {
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeEnergy,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeCharge,
+	}
+
+	return e.value(entity, filter, "")
+}
+
+// Scenario 3
+
+// return the cumulated energy discharged from the battery system in Wh
+//
+// parameters:
+//   - entity: the entity of the battery system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *VABD) EnergyDischarged(entity spineapi.EntityRemoteInterface) (float64, error) {
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeEnergy,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeDischarge,
+	}
+
+	return e.value(entity, filter, "")
+}
+
+// Scenario 4
+
+// return the state of charge of the battery system in %
+//
+// parameters:
+//   - entity: the entity of the battery system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *VABD) StateOfCharge(entity spineapi.EntityRemoteInterface) (float64, error) {
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypePercentage,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeStateOfCharge,
+	}
+
+	return e.value(entity, filter, "")
+}
@@ -0,0 +1,67 @@
+package vabd
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CemVABDSuite) Test_Power() {
+	_, err := s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions()
+
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values()
+
+	// the battery system is discharging
+	value, err := s.sut.Power(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), -2000.0, value)
+}
+
+func (s *CemVABDSuite) Test_EnergyCharged() {
+	_, err := s.sut.EnergyCharged(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	value, err := s.sut.EnergyCharged(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1500.0, value)
+}
+
+func (s *CemVABDSuite) Test_EnergyDischarged() {
+	_, err := s.sut.EnergyDischarged(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	value, err := s.sut.EnergyDischarged(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 500.0, value)
+}
+
+func (s *CemVABDSuite) Test_StateOfCharge() {
+	_, err := s.sut.StateOfCharge(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	value, err := s.sut.StateOfCharge(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 60.0, value)
+}
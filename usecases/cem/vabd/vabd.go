@@ -0,0 +1,118 @@
+package vabd
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Visualization of Aggregated Battery Data, actor CEM
+//
+// Used e.g. by a HEMS to visualize the power, the charged and discharged
+// energy and the state of charge of a battery system
+type VABD struct {
+	*usecases.UseCase
+}
+
+// creates a new VABD CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote battery systems
+func NewVABD(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *VABD {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeBatterySystem,
+		model.EntityTypeTypePVESHybrid,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	}
+
+	e := &VABD{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeVisualizationOfAggregatedBatteryData,
+		"1.0.1",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3, 4},
+		[]model.UseCaseActorType{model.UseCaseActorTypeBatterySystem},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*VABD)(nil)
+var _ usecases.EntityHandlerInterface = (*VABD)(nil)
+
+// request the descriptions required to interpret the measurements
+func (e *VABD) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := electricalConnection.RequestParameterDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if measurement, err := features.NewMeasurement(e.LocalEntity, entity); err == nil {
+		if _, err := measurement.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *VABD) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *VABD) HandleDataChange(payload spineapi.EventPayload) {
+	switch data := payload.Data.(type) {
+	case *model.MeasurementDescriptionListDataType:
+		if measurement, err := features.NewMeasurement(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := measurement.RequestValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.MeasurementListDataType:
+		e.handleMeasurements(payload.Entity, data)
+	}
+}
+
+// report the events for the updated measurements
+func (e *VABD) handleMeasurements(entity spineapi.EntityRemoteInterface, data *model.MeasurementListDataType) {
+	for _, scope := range internal.MeasurementScopesOfData(e.LocalEntity, entity, data) {
+		var err error
+		var event api.EventType
+
+		switch scope {
+		case model.ScopeTypeTypeACPowerTotal:
+			_, err = e.Power(entity)
+			event = DataUpdatePower
+		case model.ScopeTypeTypeCharge:
+			_, err = e.EnergyCharged(entity)
+			event = DataUpdateEnergyCharged
+		case model.ScopeTypeTypeDischarge:
+			_, err = e.EnergyDischarged(entity)
+			event = DataUpdateEnergyDischarged
+		case model.ScopeTypeTypeStateOfCharge:
+			_, err = e.StateOfCharge(entity)
+			event = DataUpdateStateOfCharge
+		default:
+			continue
+		}
+
+		if err == nil {
+			e.ReportEvent(entity, event)
+		}
+	}
+}
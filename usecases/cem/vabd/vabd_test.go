@@ -0,0 +1,185 @@
+package vabd
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCemVABDSuite(t *testing.T) {
+	suite.Run(t, new(CemVABDSuite))
+}
+
+type CemVABDSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *VABD
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *CemVABDSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CemVABDSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *CemVABDSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeBatterySystem,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeMeasurement,
+				Functions: []model.FunctionType{
+					model.FunctionTypeMeasurementDescriptionListData,
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionDescriptionListData,
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewVABD(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *CemVABDSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeBatterySystem,
+		model.UseCaseNameTypeVisualizationOfAggregatedBatteryData, true)
+	s.sut.HandleEvent(payload)
+}
+
+// the measurements of the remote entity, ordered by their ids
+var measurements = []struct {
+	measurementType model.MeasurementTypeType
+	scope           model.ScopeTypeType
+	value           float64
+}{
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPowerTotal, 2000},
+	{model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeCharge, 1500},
+	{model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeDischarge, 500},
+	{model.MeasurementTypeTypePercentage, model.ScopeTypeTypeStateOfCharge, 60},
+}
+
+// the battery system reports with the producer perspective,
+// so discharging power is positive
+func (s *CemVABDSuite) descriptions() spineapi.EventPayload {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionDescriptionListData,
+		&model.ElectricalConnectionDescriptionListDataType{
+			ElectricalConnectionDescriptionData: []model.ElectricalConnectionDescriptionDataType{
+				{
+					ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+					PositiveEnergyDirection: util.Ptr(model.EnergyDirectionTypeProduce),
+				},
+			},
+		})
+
+	params := &model.ElectricalConnectionParameterDescriptionListDataType{}
+	descriptions := &model.MeasurementDescriptionListDataType{}
+	for id, item := range measurements {
+		params.ElectricalConnectionParameterDescriptionData = append(params.ElectricalConnectionParameterDescriptionData,
+			model.ElectricalConnectionParameterDescriptionDataType{
+				ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				MeasurementId:          util.Ptr(model.MeasurementIdType(id)),
+			})
+		descriptions.MeasurementDescriptionData = append(descriptions.MeasurementDescriptionData,
+			model.MeasurementDescriptionDataType{
+				MeasurementId:   util.Ptr(model.MeasurementIdType(id)),
+				MeasurementType: util.Ptr(item.measurementType),
+				CommodityType:   util.Ptr(model.CommodityTypeTypeElectricity),
+				ScopeType:       util.Ptr(item.scope),
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData, params)
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementDescriptionListData, descriptions)
+}
+
+func (s *CemVABDSuite) values() spineapi.EventPayload {
+	data := &model.MeasurementListDataType{}
+	for id, item := range measurements {
+		data.MeasurementData = append(data.MeasurementData, model.MeasurementDataType{
+			MeasurementId: util.Ptr(model.MeasurementIdType(id)),
+			Value:         model.NewScaledNumberType(item.value),
+		})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData, data)
+}
+
+func (s *CemVABDSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeCEM, model.UseCaseNameTypeVisualizationOfAggregatedBatteryData))
+}
+
+func (s *CemVABDSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := s.descriptions()
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the measurement values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.values())
+	for _, event := range []api.EventType{
+		DataUpdatePower,
+		DataUpdateEnergyCharged,
+		DataUpdateEnergyDischarged,
+		DataUpdateStateOfCharge,
+	} {
+		assert.True(s.T(), s.hasEvent(event), event)
+	}
+}
@@ -0,0 +1,20 @@
+package vapd
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// PV System total power data updated
+	//
+	// Use `Power` to get the current data
+	DataUpdatePower api.EventType = "cem-vapd-DataUpdatePower"
+
+	// PV System nominal peak power data updated
+	//
+	// Use `PowerNominalPeak` to get the current data
+	DataUpdatePowerNominalPeak api.EventType = "cem-vapd-DataUpdatePowerNominalPeak"
+
+	// PV System total yield data updated
+	//
+	// Use `PVYieldTotal` to get the current data
+	DataUpdatePVYieldTotal api.EventType = "cem-vapd-DataUpdatePVYieldTotal"
+)
@@ -0,0 +1,105 @@
+package vapd
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Scenario 1
+
+// return the current production power of the PV system in W
+//
+// parameters:
+//   - entity: the entity of the PV system
+//
+// return values:
+//   - positive values are used for production
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *VAPD) Power(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypePower,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACPowerTotal,
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, model.EnergyDirectionTypeProduce)
+}
+
+// Scenario 2
+
+// return the nominal peak power of the PV system in W
+//
+// the peak power configured at the device configuration is preferred,
+// the nominal maximum production power of the electrical connection
+// is used otherwise
+//
+// parameters:
+//   - entity: the entity of the PV system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no nominal peak power is available
+//   - and others
+func (e *VAPD) PowerNominalPeak(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	if value, err := internal.KeyValueScaledNumber(e.LocalEntity, entity,
+		model.DeviceConfigurationKeyNameTypePeakPowerOfPVSystem); err == nil {
+		return value, nil
+	}
+
+	electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity)
+	if err != nil {
+		return 0, err
+	}
+
+	characteristic, err := electricalConnection.GetCharacteristicForContextType(
+		model.ElectricalConnectionCharacteristicContextTypeEntity,
+		model.ElectricalConnectionCharacteristicTypeTypePowerProductionNominalMax,
+	)
+	if err != nil || characteristic.Value == nil {
+		return 0, api.ErrDataNotAvailable
+	}
+
+	return characteristic.Value.GetValue(), nil
+}
+
+// Scenario 3
+
+// return the total yield of the PV system in Wh
+//
+// parameters:
+//   - entity: the entity of the PV system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *VAPD) PVYieldTotal(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeEnergy,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACYieldTotal,
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, "")
+}
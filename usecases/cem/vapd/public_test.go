@@ -0,0 +1,63 @@
+package vapd
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CemVAPDSuite) Test_Power() {
+	_, err := s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions()
+
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values()
+
+	value, err := s.sut.Power(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 5000.0, value)
+}
+
+func (s *CemVAPDSuite) Test_PowerNominalPeak() {
+	_, err := s.sut.PowerNominalPeak(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.PowerNominalPeak(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	// the electrical connection characteristic is used as a fallback
+	s.characteristics()
+
+	value, err := s.sut.PowerNominalPeak(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 9800.0, value)
+
+	s.keyValues()
+
+	value, err = s.sut.PowerNominalPeak(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 10000.0, value)
+}
+
+func (s *CemVAPDSuite) Test_PVYieldTotal() {
+	_, err := s.sut.PVYieldTotal(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	value, err := s.sut.PVYieldTotal(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 123000.0, value)
+}
@@ -0,0 +1,136 @@
+package vapd
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Visualization of Aggregated Photovoltaic Data, actor CEM
+//
+// Used e.g. by a HEMS to visualize the power, the nominal peak power
+// and the yield of a PV system
+type VAPD struct {
+	*usecases.UseCase
+}
+
+// creates a new VAPD CEM use case for the local entity
+//
+// eventCB is invoked for all events of remote PV systems
+func NewVAPD(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *VAPD {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypePVSystem,
+		model.EntityTypeTypePVESHybrid,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	}
+
+	e := &VAPD{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeCEM,
+		model.UseCaseNameTypeVisualizationOfAggregatedPhotovoltaicData,
+		"1.0.1",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3},
+		[]model.UseCaseActorType{model.UseCaseActorTypePVSystem},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*VAPD)(nil)
+var _ usecases.EntityHandlerInterface = (*VAPD)(nil)
+
+// request the descriptions required to interpret the data
+func (e *VAPD) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, entity); err == nil {
+		if _, err := deviceConfiguration.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := electricalConnection.RequestParameterDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := electricalConnection.RequestCharacteristics(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if measurement, err := features.NewMeasurement(e.LocalEntity, entity); err == nil {
+		if _, err := measurement.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *VAPD) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *VAPD) HandleDataChange(payload spineapi.EventPayload) {
+	switch data := payload.Data.(type) {
+	case *model.DeviceConfigurationKeyValueDescriptionListDataType:
+		if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := deviceConfiguration.RequestKeyValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.DeviceConfigurationKeyValueListDataType,
+		*model.ElectricalConnectionCharacteristicListDataType:
+		if _, err := e.PowerNominalPeak(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdatePowerNominalPeak)
+		}
+
+	case *model.MeasurementDescriptionListDataType:
+		if measurement, err := features.NewMeasurement(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := measurement.RequestValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.MeasurementListDataType:
+		e.handleMeasurements(payload.Entity, data)
+	}
+}
+
+// report the events for the updated measurements
+func (e *VAPD) handleMeasurements(entity spineapi.EntityRemoteInterface, data *model.MeasurementListDataType) {
+	for _, scope := range internal.MeasurementScopesOfData(e.LocalEntity, entity, data) {
+		var err error
+		var event api.EventType
+
+		switch scope {
+		case model.ScopeTypeTypeACPowerTotal:
+			_, err = e.Power(entity)
+			event = DataUpdatePower
+		case model.ScopeTypeTypeACYieldTotal:
+			_, err = e.PVYieldTotal(entity)
+			event = DataUpdatePVYieldTotal
+		default:
+			continue
+		}
+
+		if err == nil {
+			e.ReportEvent(entity, event)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package vapd
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCemVAPDSuite(t *testing.T) {
+	suite.Run(t, new(CemVAPDSuite))
+}
+
+type CemVAPDSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *VAPD
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *CemVAPDSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CemVAPDSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *CemVAPDSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypePVSystem,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+					model.FunctionTypeDeviceConfigurationKeyValueListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeMeasurement,
+				Functions: []model.FunctionType{
+					model.FunctionTypeMeasurementDescriptionListData,
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionDescriptionListData,
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+					model.FunctionTypeElectricalConnectionCharacteristicListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewVAPD(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *CemVAPDSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypePVSystem,
+		model.UseCaseNameTypeVisualizationOfAggregatedPhotovoltaicData, true)
+	s.sut.HandleEvent(payload)
+}
+
+// the measurements of the remote entity, ordered by their ids
+var measurements = []struct {
+	measurementType model.MeasurementTypeType
+	scope           model.ScopeTypeType
+	value           float64
+}{
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPowerTotal, 5000},
+	{model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeACYieldTotal, 123000},
+}
+
+func (s *CemVAPDSuite) descriptions() spineapi.EventPayload {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionDescriptionListData,
+		&model.ElectricalConnectionDescriptionListDataType{
+			ElectricalConnectionDescriptionData: []model.ElectricalConnectionDescriptionDataType{
+				{
+					ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+					PositiveEnergyDirection: util.Ptr(model.EnergyDirectionTypeProduce),
+				},
+			},
+		})
+
+	params := &model.ElectricalConnectionParameterDescriptionListDataType{}
+	descriptions := &model.MeasurementDescriptionListDataType{}
+	for id, item := range measurements {
+		params.ElectricalConnectionParameterDescriptionData = append(params.ElectricalConnectionParameterDescriptionData,
+			model.ElectricalConnectionParameterDescriptionDataType{
+				ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				MeasurementId:          util.Ptr(model.MeasurementIdType(id)),
+			})
+		descriptions.MeasurementDescriptionData = append(descriptions.MeasurementDescriptionData,
+			model.MeasurementDescriptionDataType{
+				MeasurementId:   util.Ptr(model.MeasurementIdType(id)),
+				MeasurementType: util.Ptr(item.measurementType),
+				CommodityType:   util.Ptr(model.CommodityTypeTypeElectricity),
+				ScopeType:       util.Ptr(item.scope),
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData, params)
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementDescriptionListData, descriptions)
+}
+
+func (s *CemVAPDSuite) keyValues() spineapi.EventPayload {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+		&model.DeviceConfigurationKeyValueDescriptionListDataType{
+			DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypePeakPowerOfPVSystem),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+				},
+			},
+		})
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData,
+		&model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						ScaledNumber: model.NewScaledNumberType(10000),
+					},
+				},
+			},
+		})
+}
+
+func (s *CemVAPDSuite) characteristics() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionCharacteristicListData,
+		&model.ElectricalConnectionCharacteristicListDataType{
+			ElectricalConnectionCharacteristicListData: []model.ElectricalConnectionCharacteristicDataType{
+				{
+					ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+					CharacteristicId:       util.Ptr(model.ElectricalConnectionCharaceteristicIdType(0)),
+					CharacteristicContext:  util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+					CharacteristicType:     util.Ptr(model.ElectricalConnectionCharacteristicTypeTypePowerProductionNominalMax),
+					Value:                  model.NewScaledNumberType(9800),
+				},
+			},
+		})
+}
+
+func (s *CemVAPDSuite) values() spineapi.EventPayload {
+	data := &model.MeasurementListDataType{}
+	for id, item := range measurements {
+		data.MeasurementData = append(data.MeasurementData, model.MeasurementDataType{
+			MeasurementId: util.Ptr(model.MeasurementIdType(id)),
+			Value:         model.NewScaledNumberType(item.value),
+		})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData, data)
+}
+
+func (s *CemVAPDSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeCEM, model.UseCaseNameTypeVisualizationOfAggregatedPhotovoltaicData))
+}
+
+func (s *CemVAPDSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := s.descriptions()
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the measurement values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.values())
+	for _, event := range []api.EventType{
+		DataUpdatePower,
+		DataUpdatePVYieldTotal,
+	} {
+		assert.True(s.T(), s.hasEvent(event), event)
+	}
+}
+
+func (s *CemVAPDSuite) Test_HandleDataChange_PowerNominalPeak() {
+	s.connect()
+
+	payload := testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+		&model.DeviceConfigurationKeyValueDescriptionListDataType{})
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the key values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.characteristics())
+	assert.True(s.T(), s.hasEvent(DataUpdatePowerNominalPeak))
+}
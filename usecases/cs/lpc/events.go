@@ -0,0 +1,28 @@
+package lpc
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// A new consumption limit was written by the energy guard and accepted
+	//
+	// Use `ConsumptionLimit` to get the current data
+	DataUpdateLimit api.EventType = "cs-lpc-DataUpdateLimit"
+
+	// A write of the energy guard was rejected before it got applied, e.g.
+	// because of an invalid value or as the energy guard does not support
+	// the use case. The write is answered with an error result.
+	WriteRejected api.EventType = "cs-lpc-WriteRejected"
+
+	// A new failsafe consumption active power limit was written by the energy guard and accepted
+	//
+	// Use `FailsafeConsumptionActivePowerLimit` to get the current data
+	DataUpdateFailsafeConsumptionActivePowerLimit api.EventType = "cs-lpc-DataUpdateFailsafeConsumptionActivePowerLimit"
+
+	// A new failsafe duration minimum was written by the energy guard and accepted
+	//
+	// Use `FailsafeDurationMinimum` to get the current data
+	DataUpdateFailsafeDurationMinimum api.EventType = "cs-lpc-DataUpdateFailsafeDurationMinimum"
+
+	// A heartbeat of the energy guard was received
+	DataUpdateHeartbeat api.EventType = "cs-lpc-DataUpdateHeartbeat"
+)
@@ -0,0 +1,61 @@
+package lpc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+)
+
+// Limitation of Power Consumption, actor Controllable System
+//
+// Used by controllable systems, e.g. an EVSE or a heat pump, whose power
+// consumption can be limited by the grid operator's energy guard
+type LPC struct {
+	*usecases.UseCase
+
+	cs *internal.ControllableSystem
+}
+
+// creates a new LPC controllable system use case for the local entity
+//
+// eventCB is invoked for all events of remote energy guards,
+// stateCB is optional and invoked whenever the control state changed
+func NewLPC(
+	localEntity spineapi.EntityLocalInterface,
+	eventCB api.EntityEventCallback,
+	stateCB usecases.ControlStateCallback,
+) *LPC {
+	events := internal.ControllableSystemEvents{
+		DataUpdateLimit:                   DataUpdateLimit,
+		WriteRejected:                     WriteRejected,
+		DataUpdateFailsafeLimit:           DataUpdateFailsafeConsumptionActivePowerLimit,
+		DataUpdateFailsafeDurationMinimum: DataUpdateFailsafeDurationMinimum,
+		DataUpdateHeartbeat:               DataUpdateHeartbeat,
+	}
+
+	cs := internal.NewControllableSystem(localEntity, internal.PowerConsumptionLimitScope, events, eventCB, stateCB)
+
+	return &LPC{
+		UseCase: cs.UseCase,
+		cs:      cs,
+	}
+}
+
+var _ api.UseCaseInterface = (*LPC)(nil)
+
+// add the client features and the server features providing the limit,
+// the failsafe values, the heartbeat and the nominal maximum power consumption
+//
+// this also starts the control state machine in the Init state
+func (c *LPC) AddFeatures() {
+	c.cs.AddFeatures()
+}
+
+// handle SPINE events
+//
+// remote writes on the local server features are reported, all other
+// events are handled by the use case
+func (c *LPC) HandleEvent(payload spineapi.EventPayload) {
+	c.cs.HandleEvent(payload)
+}
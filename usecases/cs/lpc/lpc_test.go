@@ -0,0 +1,185 @@
+package lpc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCsLPCSuite(t *testing.T) {
+	suite.Run(t, new(CsLPCSuite))
+}
+
+// the use case behaviour is tested with the controllable system in
+// the internal package, these tests cover the use case specific parts
+type CsLPCSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	sut *LPC
+
+	events []api.EventType
+	states []usecases.ControlState
+	mux    sync.Mutex
+}
+
+func (s *CsLPCSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *CsLPCSuite) StateChanged(state usecases.ControlState) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.states = append(s.states, state)
+}
+
+func (s *CsLPCSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *CsLPCSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.states = nil
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		&testhelper.WriteMessageHandler{},
+		model.EntityTypeTypeEVSE,
+		model.EntityTypeTypeCEM,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeDeviceDiagnosis,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceDiagnosisHeartbeatData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeLoadControl,
+				Role:        model.RoleTypeClient,
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Role:        model.RoleTypeClient,
+			},
+		},
+	)
+
+	s.sut = NewLPC(s.localEntity, s.Event, s.StateChanged)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+func (s *CsLPCSuite) AfterTest(suiteName, testName string) {
+	s.sut.StopHeartbeat()
+}
+
+// announce the use case support of the remote entity
+func (s *CsLPCSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeEnergyGuard,
+		model.UseCaseNameTypeLimitationOfPowerConsumption, true)
+	s.sut.HandleEvent(payload)
+}
+
+// let the energy guard write and return if the write was accepted
+func (s *CsLPCSuite) write(featureType model.FeatureTypeType, cmd model.CmdType) bool {
+	payload, err := testhelper.WriteLocalData(s.localEntity, s.remoteEntity, featureType, cmd)
+	if err != nil {
+		return false
+	}
+
+	s.sut.HandleEvent(payload)
+	return true
+}
+
+func (s *CsLPCSuite) Test_AddFeatures() {
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeControllableSystem, model.UseCaseNameTypeLimitationOfPowerConsumption))
+	assert.Equal(s.T(), usecases.ControlStateInit, s.sut.State())
+}
+
+func (s *CsLPCSuite) Test_Events() {
+	s.connect()
+	assert.True(s.T(), s.sut.HasRemoteEntity(s.remoteEntity))
+
+	payload := testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceDiagnosis,
+		model.FunctionTypeDeviceDiagnosisHeartbeatData,
+		&model.DeviceDiagnosisHeartbeatDataType{
+			Timestamp: model.NewAbsoluteOrRelativeTimeTypeFromTime(time.Now()),
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(DataUpdateHeartbeat))
+
+	accepted := s.write(model.FeatureTypeTypeLoadControl, model.CmdType{
+		LoadControlLimitListData: &model.LoadControlLimitListDataType{
+			LoadControlLimitData: []model.LoadControlLimitDataType{
+				{
+					LimitId:       util.Ptr(model.LoadControlLimitIdType(0)),
+					IsLimitActive: util.Ptr(true),
+					Value:         model.NewScaledNumberType(4200),
+				},
+			},
+		},
+	})
+	assert.True(s.T(), accepted)
+	assert.True(s.T(), s.hasEvent(DataUpdateLimit))
+	assert.Equal(s.T(), usecases.ControlStateLimited, s.sut.State())
+
+	accepted = s.write(model.FeatureTypeTypeDeviceConfiguration, model.CmdType{
+		DeviceConfigurationKeyValueListData: &model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						ScaledNumber: model.NewScaledNumberType(4200),
+					},
+				},
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						Duration: model.NewDurationType(time.Hour * 3),
+					},
+				},
+			},
+		},
+	})
+	assert.True(s.T(), accepted)
+	assert.True(s.T(), s.hasEvent(DataUpdateFailsafeConsumptionActivePowerLimit))
+	assert.True(s.T(), s.hasEvent(DataUpdateFailsafeDurationMinimum))
+	assert.False(s.T(), s.hasEvent(WriteRejected))
+
+	accepted = s.write(model.FeatureTypeTypeLoadControl, model.CmdType{
+		LoadControlLimitListData: &model.LoadControlLimitListDataType{
+			LoadControlLimitData: []model.LoadControlLimitDataType{
+				{
+					LimitId: util.Ptr(model.LoadControlLimitIdType(0)),
+					Value:   model.NewScaledNumberType(-1),
+				},
+			},
+		},
+	})
+	assert.False(s.T(), accepted)
+	assert.True(s.T(), s.hasEvent(WriteRejected))
+}
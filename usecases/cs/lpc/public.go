@@ -0,0 +1,131 @@
+package lpc
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/usecases"
+)
+
+// return the current control state
+func (c *LPC) State() usecases.ControlState {
+	return c.cs.State()
+}
+
+// return the power consumption limit which currently applies according to the control state
+//
+// return values:
+//   - value: the limit in W
+//   - limited: false if no limit applies
+func (c *LPC) EffectiveConsumptionLimit() (value float64, limited bool) {
+	return c.cs.EffectiveLimit()
+}
+
+// Scenario 1
+
+// return the current consumption limit data
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no limit is available
+func (c *LPC) ConsumptionLimit() (usecases.LoadLimit, error) {
+	return c.cs.Limit()
+}
+
+// set the current consumption limit data
+//
+// IsChangeable defines if the energy guard may write the limit. The control
+// state is not changed, as only limits of the energy guard are considered
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+func (c *LPC) SetConsumptionLimit(limit usecases.LoadLimit) error {
+	return c.cs.SetLimit(limit)
+}
+
+// Scenario 2
+
+// return the failsafe limit for the consumed active (real) power of the
+// controllable system. This limit becomes activated in "init" state or "failsafe state".
+//
+// return values:
+//   - value: the limit in W
+//   - isChangeable: if the energy guard may write the value
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no value is available
+func (c *LPC) FailsafeConsumptionActivePowerLimit() (value float64, isChangeable bool, err error) {
+	return c.cs.FailsafeLimit()
+}
+
+// set the failsafe limit for the consumed active (real) power of the controllable system
+//
+// parameters:
+//   - value: the limit in W
+//   - changeable: if the energy guard may write the value
+func (c *LPC) SetFailsafeConsumptionActivePowerLimit(value float64, changeable bool) error {
+	return c.cs.SetFailsafeLimit(value, changeable)
+}
+
+// return the minimum time the controllable system remains in "failsafe state" unless conditions
+// specified in this Use Case permit leaving the "failsafe state"
+//
+// the value is shared with LPP, energy guards of both use cases may write it
+//
+// return values:
+//   - duration: the failsafe duration minimum
+//   - isChangeable: if the energy guard may write the value
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no value is available
+func (c *LPC) FailsafeDurationMinimum() (duration time.Duration, isChangeable bool, err error) {
+	return c.cs.FailsafeDurationMinimum()
+}
+
+// set the minimum time the controllable system remains in "failsafe state"
+//
+// parameters:
+//   - duration: has to be >= 2h and <= 24h
+//   - changeable: if the energy guard may write the value
+func (c *LPC) SetFailsafeDurationMinimum(duration time.Duration, changeable bool) error {
+	return c.cs.SetFailsafeDurationMinimum(duration, changeable)
+}
+
+// Scenario 3
+
+// start sending heartbeats to all remote entities subscribed to the local heartbeat
+func (c *LPC) StartHeartbeat() error {
+	return c.cs.StartHeartbeat()
+}
+
+// stop sending heartbeats
+func (c *LPC) StopHeartbeat() {
+	c.cs.StopHeartbeat()
+}
+
+// return if the last heartbeat of an energy guard was received
+// within the heartbeat timeout
+func (c *LPC) IsHeartbeatWithinDuration() bool {
+	return c.cs.IsHeartbeatWithinDuration()
+}
+
+// Scenario 4
+
+// return nominal maximum active (real) power the controllable system is
+// able to consume according to the device label or data sheet.
+//
+// possible errors:
+//   - ErrDataNotAvailable if no value was set
+func (c *LPC) PowerConsumptionNominalMax() (float64, error) {
+	return c.cs.PowerNominalMax()
+}
+
+// set nominal maximum active (real) power the controllable system is
+// able to consume according to the device label or data sheet.
+//
+// parameters:
+//   - value: the power in W
+func (c *LPC) SetPowerConsumptionNominalMax(value float64) error {
+	return c.cs.SetPowerNominalMax(value)
+}
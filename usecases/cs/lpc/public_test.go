@@ -0,0 +1,88 @@
+package lpc
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CsLPCSuite) Test_ConsumptionLimit() {
+	sut := NewLPC(s.localEntity, nil, nil)
+	_, err := sut.ConsumptionLimit()
+	assert.NotNil(s.T(), err)
+
+	err = s.sut.SetFailsafeConsumptionActivePowerLimit(3000, true)
+	assert.Nil(s.T(), err)
+	value, limited := s.sut.EffectiveConsumptionLimit()
+	assert.True(s.T(), limited)
+	assert.Equal(s.T(), 3000.0, value)
+
+	err = s.sut.SetConsumptionLimit(usecases.LoadLimit{
+		IsChangeable: true,
+		IsActive:     true,
+		Value:        4200,
+	})
+	assert.Nil(s.T(), err)
+
+	limit, err := s.sut.ConsumptionLimit()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, limit.Value)
+
+	// the limit is described as consumption limit
+	feature := s.localEntity.FeatureOfTypeAndRole(model.FeatureTypeTypeLoadControl, model.RoleTypeServer)
+	descriptions, err := spine.LocalFeatureDataCopyOfType[*model.LoadControlLimitDescriptionListDataType](
+		feature, model.FunctionTypeLoadControlLimitDescriptionListData)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(descriptions.LoadControlLimitDescriptionData))
+	assert.Equal(s.T(), model.EnergyDirectionTypeConsume, *descriptions.LoadControlLimitDescriptionData[0].LimitDirection)
+}
+
+func (s *CsLPCSuite) Test_Failsafe() {
+	err := s.sut.SetFailsafeConsumptionActivePowerLimit(4200, false)
+	assert.Nil(s.T(), err)
+
+	value, isChangeable, err := s.sut.FailsafeConsumptionActivePowerLimit()
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), isChangeable)
+	assert.Equal(s.T(), 4200.0, value)
+
+	err = s.sut.SetFailsafeDurationMinimum(time.Hour*3, false)
+	assert.Nil(s.T(), err)
+
+	duration, isChangeable, err := s.sut.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), isChangeable)
+	assert.Equal(s.T(), time.Hour*3, duration)
+}
+
+func (s *CsLPCSuite) Test_Heartbeat() {
+	s.sut.StopHeartbeat()
+	assert.False(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	err := s.sut.StartHeartbeat()
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	assert.False(s.T(), s.sut.IsHeartbeatWithinDuration())
+}
+
+func (s *CsLPCSuite) Test_PowerConsumptionNominalMax() {
+	_, err := s.sut.PowerConsumptionNominalMax()
+	assert.NotNil(s.T(), err)
+
+	err = s.sut.SetPowerConsumptionNominalMax(11000)
+	assert.Nil(s.T(), err)
+
+	value, err := s.sut.PowerConsumptionNominalMax()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 11000.0, value)
+
+	value, err = internal.LocalCharacteristic(s.localEntity,
+		internal.PowerConsumptionLimitScope.NominalMaxCharacteristic)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 11000.0, value)
+}
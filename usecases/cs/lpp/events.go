@@ -0,0 +1,28 @@
+package lpp
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// A new production limit was written by the energy guard and accepted
+	//
+	// Use `ProductionLimit` to get the current data
+	DataUpdateLimit api.EventType = "cs-lpp-DataUpdateLimit"
+
+	// A write of the energy guard was rejected before it got applied, e.g.
+	// because of an invalid value or as the energy guard does not support
+	// the use case. The write is answered with an error result.
+	WriteRejected api.EventType = "cs-lpp-WriteRejected"
+
+	// A new failsafe production active power limit was written by the energy guard and accepted
+	//
+	// Use `FailsafeProductionActivePowerLimit` to get the current data
+	DataUpdateFailsafeProductionActivePowerLimit api.EventType = "cs-lpp-DataUpdateFailsafeProductionActivePowerLimit"
+
+	// A new failsafe duration minimum was written by the energy guard and accepted
+	//
+	// Use `FailsafeDurationMinimum` to get the current data
+	DataUpdateFailsafeDurationMinimum api.EventType = "cs-lpp-DataUpdateFailsafeDurationMinimum"
+
+	// A heartbeat of the energy guard was received
+	DataUpdateHeartbeat api.EventType = "cs-lpp-DataUpdateHeartbeat"
+)
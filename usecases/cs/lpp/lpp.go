@@ -0,0 +1,61 @@
+package lpp
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+)
+
+// Limitation of Power Production, actor Controllable System
+//
+// Used by controllable systems, e.g. a PV or battery inverter, whose power
+// production can be limited by the grid operator's energy guard
+type LPP struct {
+	*usecases.UseCase
+
+	cs *internal.ControllableSystem
+}
+
+// creates a new LPP controllable system use case for the local entity
+//
+// eventCB is invoked for all events of remote energy guards,
+// stateCB is optional and invoked whenever the control state changed
+func NewLPP(
+	localEntity spineapi.EntityLocalInterface,
+	eventCB api.EntityEventCallback,
+	stateCB usecases.ControlStateCallback,
+) *LPP {
+	events := internal.ControllableSystemEvents{
+		DataUpdateLimit:                   DataUpdateLimit,
+		WriteRejected:                     WriteRejected,
+		DataUpdateFailsafeLimit:           DataUpdateFailsafeProductionActivePowerLimit,
+		DataUpdateFailsafeDurationMinimum: DataUpdateFailsafeDurationMinimum,
+		DataUpdateHeartbeat:               DataUpdateHeartbeat,
+	}
+
+	cs := internal.NewControllableSystem(localEntity, internal.PowerProductionLimitScope, events, eventCB, stateCB)
+
+	return &LPP{
+		UseCase: cs.UseCase,
+		cs:      cs,
+	}
+}
+
+var _ api.UseCaseInterface = (*LPP)(nil)
+
+// add the client features and the server features providing the limit,
+// the failsafe values, the heartbeat and the nominal maximum power production
+//
+// this also starts the control state machine in the Init state
+func (c *LPP) AddFeatures() {
+	c.cs.AddFeatures()
+}
+
+// handle SPINE events
+//
+// remote writes on the local server features are reported, all other
+// events are handled by the use case
+func (c *LPP) HandleEvent(payload spineapi.EventPayload) {
+	c.cs.HandleEvent(payload)
+}
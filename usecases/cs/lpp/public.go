@@ -0,0 +1,131 @@
+package lpp
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/usecases"
+)
+
+// return the current control state
+func (c *LPP) State() usecases.ControlState {
+	return c.cs.State()
+}
+
+// return the power production limit which currently applies according to the control state
+//
+// return values:
+//   - value: the limit in W
+//   - limited: false if no limit applies
+func (c *LPP) EffectiveProductionLimit() (value float64, limited bool) {
+	return c.cs.EffectiveLimit()
+}
+
+// Scenario 1
+
+// return the current production limit data
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no limit is available
+func (c *LPP) ProductionLimit() (usecases.LoadLimit, error) {
+	return c.cs.Limit()
+}
+
+// set the current production limit data
+//
+// IsChangeable defines if the energy guard may write the limit. The control
+// state is not changed, as only limits of the energy guard are considered
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+func (c *LPP) SetProductionLimit(limit usecases.LoadLimit) error {
+	return c.cs.SetLimit(limit)
+}
+
+// Scenario 2
+
+// return the failsafe limit for the produced active (real) power of the
+// controllable system. This limit becomes activated in "init" state or "failsafe state".
+//
+// return values:
+//   - value: the limit in W
+//   - isChangeable: if the energy guard may write the value
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no value is available
+func (c *LPP) FailsafeProductionActivePowerLimit() (value float64, isChangeable bool, err error) {
+	return c.cs.FailsafeLimit()
+}
+
+// set the failsafe limit for the produced active (real) power of the controllable system
+//
+// parameters:
+//   - value: the limit in W
+//   - changeable: if the energy guard may write the value
+func (c *LPP) SetFailsafeProductionActivePowerLimit(value float64, changeable bool) error {
+	return c.cs.SetFailsafeLimit(value, changeable)
+}
+
+// return the minimum time the controllable system remains in "failsafe state" unless conditions
+// specified in this Use Case permit leaving the "failsafe state"
+//
+// the value is shared with LPC, energy guards of both use cases may write it
+//
+// return values:
+//   - duration: the failsafe duration minimum
+//   - isChangeable: if the energy guard may write the value
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no value is available
+func (c *LPP) FailsafeDurationMinimum() (duration time.Duration, isChangeable bool, err error) {
+	return c.cs.FailsafeDurationMinimum()
+}
+
+// set the minimum time the controllable system remains in "failsafe state"
+//
+// parameters:
+//   - duration: has to be >= 2h and <= 24h
+//   - changeable: if the energy guard may write the value
+func (c *LPP) SetFailsafeDurationMinimum(duration time.Duration, changeable bool) error {
+	return c.cs.SetFailsafeDurationMinimum(duration, changeable)
+}
+
+// Scenario 3
+
+// start sending heartbeats to all remote entities subscribed to the local heartbeat
+func (c *LPP) StartHeartbeat() error {
+	return c.cs.StartHeartbeat()
+}
+
+// stop sending heartbeats
+func (c *LPP) StopHeartbeat() {
+	c.cs.StopHeartbeat()
+}
+
+// return if the last heartbeat of an energy guard was received
+// within the heartbeat timeout
+func (c *LPP) IsHeartbeatWithinDuration() bool {
+	return c.cs.IsHeartbeatWithinDuration()
+}
+
+// Scenario 4
+
+// return nominal maximum active (real) power the controllable system is
+// able to produce according to the device label or data sheet.
+//
+// possible errors:
+//   - ErrDataNotAvailable if no value was set
+func (c *LPP) PowerProductionNominalMax() (float64, error) {
+	return c.cs.PowerNominalMax()
+}
+
+// set nominal maximum active (real) power the controllable system is
+// able to produce according to the device label or data sheet.
+//
+// parameters:
+//   - value: the power in W
+func (c *LPP) SetPowerProductionNominalMax(value float64) error {
+	return c.cs.SetPowerNominalMax(value)
+}
@@ -0,0 +1,88 @@
+package lpp
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *CsLPPSuite) Test_ProductionLimit() {
+	sut := NewLPP(s.localEntity, nil, nil)
+	_, err := sut.ProductionLimit()
+	assert.NotNil(s.T(), err)
+
+	err = s.sut.SetFailsafeProductionActivePowerLimit(3000, true)
+	assert.Nil(s.T(), err)
+	value, limited := s.sut.EffectiveProductionLimit()
+	assert.True(s.T(), limited)
+	assert.Equal(s.T(), 3000.0, value)
+
+	err = s.sut.SetProductionLimit(usecases.LoadLimit{
+		IsChangeable: true,
+		IsActive:     true,
+		Value:        4200,
+	})
+	assert.Nil(s.T(), err)
+
+	limit, err := s.sut.ProductionLimit()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, limit.Value)
+
+	// the limit is described as production limit
+	feature := s.localEntity.FeatureOfTypeAndRole(model.FeatureTypeTypeLoadControl, model.RoleTypeServer)
+	descriptions, err := spine.LocalFeatureDataCopyOfType[*model.LoadControlLimitDescriptionListDataType](
+		feature, model.FunctionTypeLoadControlLimitDescriptionListData)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(descriptions.LoadControlLimitDescriptionData))
+	assert.Equal(s.T(), model.EnergyDirectionTypeProduce, *descriptions.LoadControlLimitDescriptionData[0].LimitDirection)
+}
+
+func (s *CsLPPSuite) Test_Failsafe() {
+	err := s.sut.SetFailsafeProductionActivePowerLimit(4200, false)
+	assert.Nil(s.T(), err)
+
+	value, isChangeable, err := s.sut.FailsafeProductionActivePowerLimit()
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), isChangeable)
+	assert.Equal(s.T(), 4200.0, value)
+
+	err = s.sut.SetFailsafeDurationMinimum(time.Hour*3, false)
+	assert.Nil(s.T(), err)
+
+	duration, isChangeable, err := s.sut.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), isChangeable)
+	assert.Equal(s.T(), time.Hour*3, duration)
+}
+
+func (s *CsLPPSuite) Test_Heartbeat() {
+	s.sut.StopHeartbeat()
+	assert.False(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	err := s.sut.StartHeartbeat()
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	assert.False(s.T(), s.sut.IsHeartbeatWithinDuration())
+}
+
+func (s *CsLPPSuite) Test_PowerProductionNominalMax() {
+	_, err := s.sut.PowerProductionNominalMax()
+	assert.NotNil(s.T(), err)
+
+	err = s.sut.SetPowerProductionNominalMax(11000)
+	assert.Nil(s.T(), err)
+
+	value, err := s.sut.PowerProductionNominalMax()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 11000.0, value)
+
+	value, err = internal.LocalCharacteristic(s.localEntity,
+		internal.PowerProductionLimitScope.NominalMaxCharacteristic)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 11000.0, value)
+}
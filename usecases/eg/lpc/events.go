@@ -0,0 +1,40 @@
+package lpc
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// Load control limit data was updated
+	//
+	// Use `ConsumptionLimit` to get the current data
+	DataUpdateLimit api.EventType = "eg-lpc-DataUpdateLimit"
+
+	// A written consumption limit was accepted by the controllable system
+	LimitAccepted api.EventType = "eg-lpc-LimitAccepted"
+
+	// A written consumption limit was rejected by the controllable system
+	LimitRejected api.EventType = "eg-lpc-LimitRejected"
+
+	// Failsafe consumption active power limit value was updated
+	//
+	// Use `FailsafeConsumptionActivePowerLimit` to get the current data
+	DataUpdateFailsafeConsumptionActivePowerLimit api.EventType = "eg-lpc-DataUpdateFailsafeConsumptionActivePowerLimit"
+
+	// Minimum time the controllable system remains in "failsafe state" unless conditions
+	// specified in this Use Case permit leaving the "failsafe state" was updated
+	//
+	// Use `FailsafeDurationMinimum` to get the current data
+	DataUpdateFailsafeDurationMinimum api.EventType = "eg-lpc-DataUpdateFailsafeDurationMinimum"
+
+	// The nominal maximum power consumption of the controllable system was updated
+	//
+	// Use `PowerConsumptionNominalMax` to get the current data
+	DataUpdatePowerConsumptionNominalMax api.EventType = "eg-lpc-DataUpdatePowerConsumptionNominalMax"
+
+	// A heartbeat of the controllable system was received
+	DataUpdateHeartbeat api.EventType = "eg-lpc-DataUpdateHeartbeat"
+
+	// No heartbeat of the controllable system was received within the heartbeat timeout
+	//
+	// Use `IsHeartbeatWithinDuration` to check if heartbeats are received again
+	HeartbeatTimeout api.EventType = "eg-lpc-HeartbeatTimeout"
+)
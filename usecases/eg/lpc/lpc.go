@@ -0,0 +1,59 @@
+package lpc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Limitation of Power Consumption, actor Energy Guard
+//
+// Used by the grid operator's energy guard, e.g. a HEMS, to limit the power
+// consumption of controllable systems
+type LPC struct {
+	*usecases.UseCase
+
+	eg *internal.EnergyGuard
+}
+
+// creates a new LPC energy guard use case for the local entity
+//
+// eventCB is invoked for all events of remote controllable systems
+func NewLPC(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *LPC {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeCompressor,
+		model.EntityTypeTypeEVSE,
+		model.EntityTypeTypeHeatPumpAppliance,
+		model.EntityTypeTypeInverter,
+		model.EntityTypeTypeSmartEnergyAppliance,
+		model.EntityTypeTypeSubMeterElectricity,
+	}
+
+	events := internal.EnergyGuardEvents{
+		DataUpdateLimit:                   DataUpdateLimit,
+		LimitAccepted:                     LimitAccepted,
+		LimitRejected:                     LimitRejected,
+		DataUpdateFailsafeLimit:           DataUpdateFailsafeConsumptionActivePowerLimit,
+		DataUpdateFailsafeDurationMinimum: DataUpdateFailsafeDurationMinimum,
+		DataUpdateNominalMax:              DataUpdatePowerConsumptionNominalMax,
+		DataUpdateHeartbeat:               DataUpdateHeartbeat,
+		HeartbeatTimeout:                  HeartbeatTimeout,
+	}
+
+	eg := internal.NewEnergyGuard(localEntity, internal.PowerConsumptionLimitScope, events, validEntityTypes, eventCB)
+
+	return &LPC{
+		UseCase: eg.UseCase,
+		eg:      eg,
+	}
+}
+
+var _ api.UseCaseInterface = (*LPC)(nil)
+
+// add the client features and the device diagnosis server feature
+// providing the heartbeats of the energy guard
+func (e *LPC) AddFeatures() {
+	e.eg.AddFeatures()
+}
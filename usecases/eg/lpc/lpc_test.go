@@ -0,0 +1,212 @@
+package lpc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestEgLPCSuite(t *testing.T) {
+	suite.Run(t, new(EgLPCSuite))
+}
+
+// the use case behaviour is tested with the energy guard in
+// the internal package, these tests cover the use case specific parts
+type EgLPCSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *LPC
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *EgLPCSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *EgLPCSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *EgLPCSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEVSE,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeLoadControl,
+				Functions: []model.FunctionType{
+					model.FunctionTypeLoadControlLimitDescriptionListData,
+					model.FunctionTypeLoadControlLimitListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+					model.FunctionTypeDeviceConfigurationKeyValueListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceDiagnosis,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceDiagnosisHeartbeatData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionCharacteristicListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewLPC(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+func (s *EgLPCSuite) AfterTest(suiteName, testName string) {
+	s.sut.StopHeartbeat()
+}
+
+// announce the use case support of the remote entity
+func (s *EgLPCSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeControllableSystem,
+		model.UseCaseNameTypeLimitationOfPowerConsumption, true)
+	s.sut.HandleEvent(payload)
+}
+
+// set the consumption limit description of the controllable system
+func (s *EgLPCSuite) setLimitDescription() {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitDescriptionListData,
+		&model.LoadControlLimitDescriptionListDataType{
+			LoadControlLimitDescriptionData: []model.LoadControlLimitDescriptionDataType{
+				{
+					LimitId:        util.Ptr(model.LoadControlLimitIdType(0)),
+					LimitType:      util.Ptr(model.LoadControlLimitTypeTypeSignDependentAbsValueLimit),
+					LimitCategory:  util.Ptr(model.LoadControlCategoryTypeObligation),
+					LimitDirection: util.Ptr(model.EnergyDirectionTypeConsume),
+					ScopeType:      util.Ptr(model.ScopeTypeTypeActivePowerLimit),
+				},
+			},
+		})
+}
+
+func (s *EgLPCSuite) Test_AddFeatures() {
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeEnergyGuard, model.UseCaseNameTypeLimitationOfPowerConsumption))
+	assert.True(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+}
+
+func (s *EgLPCSuite) Test_HandleDataChange() {
+	s.connect()
+	assert.True(s.T(), s.sut.HasRemoteEntity(s.remoteEntity))
+
+	s.setLimitDescription()
+	payload := testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitListData,
+		&model.LoadControlLimitListDataType{
+			LoadControlLimitData: []model.LoadControlLimitDataType{
+				{
+					LimitId: util.Ptr(model.LoadControlLimitIdType(0)),
+					Value:   model.NewScaledNumberType(4200),
+				},
+			},
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(DataUpdateLimit))
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+		&model.DeviceConfigurationKeyValueDescriptionListDataType{
+			DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeConsumptionActivePowerLimit),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+				},
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+				},
+			},
+		})
+	payload = testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData,
+		&model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						ScaledNumber: model.NewScaledNumberType(4200),
+					},
+				},
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						Duration: model.NewDurationType(time.Hour * 2),
+					},
+				},
+			},
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(DataUpdateFailsafeConsumptionActivePowerLimit))
+	assert.True(s.T(), s.hasEvent(DataUpdateFailsafeDurationMinimum))
+
+	payload = testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionCharacteristicListData,
+		&model.ElectricalConnectionCharacteristicListDataType{
+			ElectricalConnectionCharacteristicListData: []model.ElectricalConnectionCharacteristicDataType{
+				{
+					CharacteristicId:      util.Ptr(model.ElectricalConnectionCharaceteristicIdType(0)),
+					CharacteristicContext: util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+					CharacteristicType:    util.Ptr(model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax),
+					Value:                 model.NewScaledNumberType(11000),
+				},
+			},
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(DataUpdatePowerConsumptionNominalMax))
+
+	payload = testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceDiagnosis,
+		model.FunctionTypeDeviceDiagnosisHeartbeatData,
+		&model.DeviceDiagnosisHeartbeatDataType{
+			Timestamp: model.NewAbsoluteOrRelativeTimeTypeFromTime(time.Now()),
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(DataUpdateHeartbeat))
+}
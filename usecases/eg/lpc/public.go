@@ -0,0 +1,112 @@
+package lpc
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/usecases"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Scenario 1
+
+// return the current consumption limit data
+//
+// parameters:
+//   - entity: the entity of the controllable system
+//
+// return values:
+//   - limit: load limit data
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit description is available
+//   - ErrDataNotAvailable if no limit value is available
+//   - and others
+func (e *LPC) ConsumptionLimit(entity spineapi.EntityRemoteInterface) (usecases.LoadLimit, error) {
+	return e.eg.Limit(entity)
+}
+
+// send a new consumption limit
+//
+// the result of the write is reported via the LimitAccepted or LimitRejected event
+//
+// parameters:
+//   - entity: the entity of the controllable system
+//   - limit: load limit data, IsChangeable is ignored
+func (e *LPC) WriteConsumptionLimit(entity spineapi.EntityRemoteInterface, limit usecases.LoadLimit) (*model.MsgCounterType, error) {
+	return e.eg.WriteLimit(entity, limit)
+}
+
+// Scenario 2
+
+// return the failsafe limit for the consumed active (real) power of the
+// controllable system. This limit becomes activated in "init" state or "failsafe state".
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no value is available
+//   - and others
+func (e *LPC) FailsafeConsumptionActivePowerLimit(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.eg.FailsafeLimit(entity)
+}
+
+// send new failsafe consumption active power limit
+//
+// parameters:
+//   - entity: the entity of the controllable system
+//   - value: the new limit in W
+func (e *LPC) WriteFailsafeConsumptionActivePowerLimit(entity spineapi.EntityRemoteInterface, value float64) (*model.MsgCounterType, error) {
+	return e.eg.WriteFailsafeLimit(entity, value)
+}
+
+// return the minimum time the controllable system remains in "failsafe state" unless conditions
+// specified in this Use Case permit leaving the "failsafe state"
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no value is available
+//   - and others
+func (e *LPC) FailsafeDurationMinimum(entity spineapi.EntityRemoteInterface) (time.Duration, error) {
+	return e.eg.FailsafeDurationMinimum(entity)
+}
+
+// send new failsafe duration minimum
+//
+// parameters:
+//   - entity: the entity of the controllable system
+//   - duration: has to be >= 2h and <= 24h
+func (e *LPC) WriteFailsafeDurationMinimum(entity spineapi.EntityRemoteInterface, duration time.Duration) (*model.MsgCounterType, error) {
+	return e.eg.WriteFailsafeDurationMinimum(entity, duration)
+}
+
+// Scenario 3
+
+// start sending heartbeats to all remote entities subscribed to the local heartbeat
+func (e *LPC) StartHeartbeat() error {
+	return e.eg.StartHeartbeat()
+}
+
+// stop sending heartbeats
+func (e *LPC) StopHeartbeat() {
+	e.eg.StopHeartbeat()
+}
+
+// return if the last heartbeat of the controllable system was received
+// within the heartbeat timeout
+func (e *LPC) IsHeartbeatWithinDuration(entity spineapi.EntityRemoteInterface) bool {
+	return e.eg.IsHeartbeatWithinDuration(entity)
+}
+
+// Scenario 4
+
+// return nominal maximum active (real) power the controllable system is
+// able to consume according to the device label or data sheet.
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no value is available
+//   - and others
+func (e *LPC) PowerConsumptionNominalMax(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.eg.PowerNominalMax(entity)
+}
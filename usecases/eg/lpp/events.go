@@ -0,0 +1,40 @@
+package lpp
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// Load control limit data was updated
+	//
+	// Use `ProductionLimit` to get the current data
+	DataUpdateLimit api.EventType = "eg-lpp-DataUpdateLimit"
+
+	// A written production limit was accepted by the controllable system
+	LimitAccepted api.EventType = "eg-lpp-LimitAccepted"
+
+	// A written production limit was rejected by the controllable system
+	LimitRejected api.EventType = "eg-lpp-LimitRejected"
+
+	// Failsafe production active power limit value was updated
+	//
+	// Use `FailsafeProductionActivePowerLimit` to get the current data
+	DataUpdateFailsafeProductionActivePowerLimit api.EventType = "eg-lpp-DataUpdateFailsafeProductionActivePowerLimit"
+
+	// Minimum time the controllable system remains in "failsafe state" unless conditions
+	// specified in this Use Case permit leaving the "failsafe state" was updated
+	//
+	// Use `FailsafeDurationMinimum` to get the current data
+	DataUpdateFailsafeDurationMinimum api.EventType = "eg-lpp-DataUpdateFailsafeDurationMinimum"
+
+	// The nominal maximum power production of the controllable system was updated
+	//
+	// Use `PowerProductionNominalMax` to get the current data
+	DataUpdatePowerProductionNominalMax api.EventType = "eg-lpp-DataUpdatePowerProductionNominalMax"
+
+	// A heartbeat of the controllable system was received
+	DataUpdateHeartbeat api.EventType = "eg-lpp-DataUpdateHeartbeat"
+
+	// No heartbeat of the controllable system was received within the heartbeat timeout
+	//
+	// Use `IsHeartbeatWithinDuration` to check if heartbeats are received again
+	HeartbeatTimeout api.EventType = "eg-lpp-HeartbeatTimeout"
+)
@@ -0,0 +1,56 @@
+package lpp
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Limitation of Power Production, actor Energy Guard
+//
+// Used by the grid operator's energy guard, e.g. a HEMS, to limit the power
+// production of controllable systems
+type LPP struct {
+	*usecases.UseCase
+
+	eg *internal.EnergyGuard
+}
+
+// creates a new LPP energy guard use case for the local entity
+//
+// eventCB is invoked for all events of remote controllable systems
+func NewLPP(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *LPP {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeInverter,
+		model.EntityTypeTypeSmartEnergyAppliance,
+		model.EntityTypeTypeSubMeterElectricity,
+	}
+
+	events := internal.EnergyGuardEvents{
+		DataUpdateLimit:                   DataUpdateLimit,
+		LimitAccepted:                     LimitAccepted,
+		LimitRejected:                     LimitRejected,
+		DataUpdateFailsafeLimit:           DataUpdateFailsafeProductionActivePowerLimit,
+		DataUpdateFailsafeDurationMinimum: DataUpdateFailsafeDurationMinimum,
+		DataUpdateNominalMax:              DataUpdatePowerProductionNominalMax,
+		DataUpdateHeartbeat:               DataUpdateHeartbeat,
+		HeartbeatTimeout:                  HeartbeatTimeout,
+	}
+
+	eg := internal.NewEnergyGuard(localEntity, internal.PowerProductionLimitScope, events, validEntityTypes, eventCB)
+
+	return &LPP{
+		UseCase: eg.UseCase,
+		eg:      eg,
+	}
+}
+
+var _ api.UseCaseInterface = (*LPP)(nil)
+
+// add the client features and the device diagnosis server feature
+// providing the heartbeats of the energy guard
+func (e *LPP) AddFeatures() {
+	e.eg.AddFeatures()
+}
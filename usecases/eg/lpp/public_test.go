@@ -0,0 +1,145 @@
+package lpp
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *EgLPPSuite) Test_ProductionLimit() {
+	limit := usecases.LoadLimit{
+		IsActive: true,
+		Value:    4200,
+	}
+
+	s.connect()
+	s.setLimitDescription()
+
+	msgCounter, err := s.sut.WriteProductionLimit(s.remoteEntity, limit)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	testhelper.SetRemoteResult(s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		*msgCounter, model.ErrorNumberTypeCommandRejected)
+	assert.Eventually(s.T(), func() bool {
+		return s.hasEvent(LimitRejected)
+	}, time.Second, time.Millisecond*10)
+
+	msgCounter, err = s.sut.WriteProductionLimit(s.remoteEntity, limit)
+	assert.Nil(s.T(), err)
+	testhelper.SetRemoteResult(s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		*msgCounter, model.ErrorNumberTypeNoError)
+	assert.Eventually(s.T(), func() bool {
+		return s.hasEvent(LimitAccepted)
+	}, time.Second, time.Millisecond*10)
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitListData,
+		&model.LoadControlLimitListDataType{
+			LoadControlLimitData: []model.LoadControlLimitDataType{
+				{
+					LimitId:       util.Ptr(model.LoadControlLimitIdType(0)),
+					IsLimitActive: util.Ptr(true),
+					Value:         model.NewScaledNumberType(4200),
+				},
+			},
+		})
+
+	data, err := s.sut.ProductionLimit(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, data.Value)
+	assert.True(s.T(), data.IsActive)
+}
+
+func (s *EgLPPSuite) Test_Failsafe() {
+	s.connect()
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+		&model.DeviceConfigurationKeyValueDescriptionListDataType{
+			DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeProductionActivePowerLimit),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+				},
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+				},
+			},
+		})
+
+	msgCounter, err := s.sut.WriteFailsafeProductionActivePowerLimit(s.remoteEntity, 4200)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	msgCounter, err = s.sut.WriteFailsafeDurationMinimum(s.remoteEntity, time.Hour*2)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData,
+		&model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						ScaledNumber: model.NewScaledNumberType(4200),
+					},
+				},
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						Duration: model.NewDurationType(time.Hour * 2),
+					},
+				},
+			},
+		})
+
+	value, err := s.sut.FailsafeProductionActivePowerLimit(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, value)
+
+	duration, err := s.sut.FailsafeDurationMinimum(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*2, duration)
+}
+
+func (s *EgLPPSuite) Test_Heartbeat() {
+	s.sut.StopHeartbeat()
+	assert.False(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	err := s.sut.StartHeartbeat()
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	s.connect()
+	assert.False(s.T(), s.sut.IsHeartbeatWithinDuration(s.remoteEntity))
+}
+
+func (s *EgLPPSuite) Test_PowerProductionNominalMax() {
+	s.connect()
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionCharacteristicListData,
+		&model.ElectricalConnectionCharacteristicListDataType{
+			ElectricalConnectionCharacteristicListData: []model.ElectricalConnectionCharacteristicDataType{
+				{
+					CharacteristicId:      util.Ptr(model.ElectricalConnectionCharaceteristicIdType(0)),
+					CharacteristicContext: util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+					CharacteristicType:    util.Ptr(model.ElectricalConnectionCharacteristicTypeTypePowerProductionNominalMax),
+					Value:                 model.NewScaledNumberType(11000),
+				},
+			},
+		})
+
+	value, err := s.sut.PowerProductionNominalMax(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 11000.0, value)
+}
@@ -0,0 +1,542 @@
+package internal
+
+import (
+	"slices"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/util"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the events reported by the controllable system of LPC or LPP
+type ControllableSystemEvents struct {
+	// a new limit was written by the energy guard and accepted
+	DataUpdateLimit api.EventType
+
+	// a write of the energy guard was rejected before it got applied
+	WriteRejected api.EventType
+
+	// a new failsafe active power limit was written by the energy guard and accepted
+	DataUpdateFailsafeLimit api.EventType
+
+	// a new failsafe duration minimum was written by the energy guard and accepted
+	DataUpdateFailsafeDurationMinimum api.EventType
+
+	// a heartbeat of the energy guard was received
+	DataUpdateHeartbeat api.EventType
+}
+
+// the actor Controllable System of LPC and LPP
+//
+// Both use cases share the local load control and device configuration server
+// features, including the failsafe duration minimum key. Remote writes are
+// approved before they are applied, only energy guards supporting the use case
+// may write the limit and the failsafe limit, energy guards supporting LPC or
+// LPP may write the failsafe duration minimum.
+type ControllableSystem struct {
+	*usecases.UseCase
+
+	scope  PowerLimitScope
+	events ControllableSystemEvents
+
+	// the local server features, set once the features were added
+	loadControl         *features.LoadControlServer
+	deviceConfiguration *features.DeviceConfigurationServer
+
+	limitId               model.LoadControlLimitIdType
+	failsafeLimitKeyId    model.DeviceConfigurationKeyIdType
+	failsafeDurationKeyId model.DeviceConfigurationKeyIdType
+
+	// LPC and LPP, whose energy guards may write the failsafe duration minimum
+	failsafeDurationUseCases []*usecases.UseCase
+
+	heartbeatMonitor *HeartbeatMonitor
+	stateMachine     *ControlStateMachine
+}
+
+// creates a new controllable system of the use case of the scope for the local entity
+//
+// eventCB is invoked for all events of remote energy guards,
+// stateCB is optional and invoked whenever the control state changed
+func NewControllableSystem(
+	localEntity spineapi.EntityLocalInterface,
+	scope PowerLimitScope,
+	events ControllableSystemEvents,
+	eventCB api.EntityEventCallback,
+	stateCB usecases.ControlStateCallback,
+) *ControllableSystem {
+	newUseCase := func(name model.UseCaseNameType, handler usecases.EntityHandlerInterface) *usecases.UseCase {
+		return usecases.NewUseCase(
+			localEntity,
+			model.UseCaseActorTypeControllableSystem,
+			name,
+			"1.0.0",
+			"release",
+			[]model.UseCaseScenarioSupportType{1, 2, 3, 4},
+			[]model.UseCaseActorType{model.UseCaseActorTypeEnergyGuard},
+			[]model.EntityTypeType{
+				model.EntityTypeTypeCEM,
+				model.EntityTypeTypeGridGuard,
+			},
+			[]model.FeatureTypeType{
+				model.FeatureTypeTypeDeviceDiagnosis,
+			},
+			eventCB,
+			handler,
+		)
+	}
+
+	c := &ControllableSystem{
+		scope:  scope,
+		events: events,
+	}
+	c.UseCase = newUseCase(scope.UseCaseName, c)
+	c.failsafeDurationUseCases = []*usecases.UseCase{
+		newUseCase(PowerConsumptionLimitScope.UseCaseName, nil),
+		newUseCase(PowerProductionLimitScope.UseCaseName, nil),
+	}
+	c.heartbeatMonitor = NewHeartbeatMonitor(powerLimitHeartbeatTimeout, c.heartbeatTimeout)
+	c.stateMachine = NewControlStateMachine(powerLimitInitTimeout, c.currentFailsafeDuration, c.isHeartbeatAlive, stateCB)
+
+	return c
+}
+
+var _ api.UseCaseInterface = (*ControllableSystem)(nil)
+var _ usecases.EntityHandlerInterface = (*ControllableSystem)(nil)
+
+// add the client features and the server features providing the limit,
+// the failsafe values, the heartbeat and the nominal maximum power
+//
+// this also starts the control state machine in the Init state
+func (c *ControllableSystem) AddFeatures() {
+	c.UseCase.AddFeatures()
+
+	if err := c.addServerFeatures(); err != nil {
+		logging.Log().Error(c.scope.UseCaseName, err)
+	}
+
+	if err := AddLocalCharacteristics(c.LocalEntity); err != nil {
+		logging.Log().Error(c.scope.UseCaseName, err)
+	}
+
+	feature := c.LocalEntity.GetOrAddFeature(model.FeatureTypeTypeDeviceDiagnosis, model.RoleTypeServer)
+	feature.AddFunctionType(model.FunctionTypeDeviceDiagnosisHeartbeatData, true, false)
+
+	c.LocalEntity.Device().HeartbeatManager().SetLocalFeature(c.LocalEntity, feature)
+
+	c.stateMachine.Start()
+}
+
+// add the limit and the failsafe values to the local server features
+//
+// remote writes are approved before they are applied
+func (c *ControllableSystem) addServerFeatures() error {
+	loadControl, err := features.NewLoadControlServer(c.LocalEntity)
+	if err != nil {
+		return err
+	}
+
+	deviceConfiguration, err := features.NewDeviceConfigurationServer(c.LocalEntity)
+	if err != nil {
+		return err
+	}
+
+	filter := c.scope.LimitFilter()
+	c.limitId = loadControl.AddLimitDescription(model.LoadControlLimitDescriptionDataType{
+		LimitType:      util.Ptr(filter.LimitType),
+		LimitCategory:  util.Ptr(filter.Category),
+		LimitDirection: util.Ptr(filter.Direction),
+		Unit:           util.Ptr(model.UnitOfMeasurementTypeW),
+		ScopeType:      util.Ptr(filter.Scope),
+	})
+	// the limit is initially inactive and changeable
+	if err := loadControl.UpdateLimits(LocalLimitData(c.limitId, usecases.LoadLimit{IsChangeable: true})); err != nil {
+		return err
+	}
+	loadControl.AddWriteApproval(c.limitId, c.approveLimit)
+
+	c.failsafeLimitKeyId, err = AddLocalKeyValue(deviceConfiguration,
+		model.DeviceConfigurationKeyValueDescriptionDataType{
+			KeyName:   util.Ptr(c.scope.FailsafeLimitKeyName),
+			ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+			Unit:      util.Ptr(model.UnitOfMeasurementTypeW),
+		},
+		model.DeviceConfigurationKeyValueValueType{
+			ScaledNumber: model.NewScaledNumberType(defaultFailsafeActivePowerLimit),
+		})
+	if err != nil {
+		return err
+	}
+	deviceConfiguration.AddWriteApproval(c.failsafeLimitKeyId, c.approveFailsafeLimit)
+
+	// the key is shared by LPC and LPP
+	c.failsafeDurationKeyId, err = AddLocalKeyValue(deviceConfiguration,
+		model.DeviceConfigurationKeyValueDescriptionDataType{
+			KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum),
+			ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+		},
+		model.DeviceConfigurationKeyValueValueType{
+			Duration: model.NewDurationType(defaultFailsafeDurationMinimum),
+		})
+	if err != nil {
+		return err
+	}
+	deviceConfiguration.AddWriteApproval(c.failsafeDurationKeyId, c.approveFailsafeDuration)
+
+	c.loadControl = loadControl
+	c.deviceConfiguration = deviceConfiguration
+
+	return nil
+}
+
+// handle SPINE events
+//
+// remote writes on the local server features are reported, all other
+// events are handled by the use case
+func (c *ControllableSystem) HandleEvent(payload spineapi.EventPayload) {
+	if payload.EventType == spineapi.EventTypeDataChange &&
+		payload.LocalFeature != nil && payload.LocalFeature.Entity() == c.LocalEntity &&
+		payload.CmdClassifier != nil && *payload.CmdClassifier == model.CmdClassifierTypeWrite {
+		c.handleWrite(payload)
+		return
+	}
+
+	c.UseCase.HandleEvent(payload)
+}
+
+// request the heartbeat of the energy guard
+func (c *ControllableSystem) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if deviceDiagnosis, err := features.NewDeviceDiagnosis(c.LocalEntity, entity); err == nil {
+		if _, err := deviceDiagnosis.RequestHeartbeat(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+// a disconnected energy guard is handled like a heartbeat timeout
+func (c *ControllableSystem) EntityDisconnected(entity spineapi.EntityRemoteInterface) {
+	c.heartbeatMonitor.Stop(entity)
+	c.heartbeatTimeout(entity)
+}
+
+func (c *ControllableSystem) HandleDataChange(payload spineapi.EventPayload) {
+	if _, ok := payload.Data.(*model.DeviceDiagnosisHeartbeatDataType); ok {
+		c.heartbeatMonitor.Heartbeat(payload.Entity)
+		c.stateMachine.HeartbeatReceived()
+		c.ReportEvent(payload.Entity, c.events.DataUpdateHeartbeat)
+	}
+}
+
+// report the accepted remote writes on the local server features
+//
+// the writes were approved before they got applied
+func (c *ControllableSystem) handleWrite(payload spineapi.EventPayload) {
+	if c.loadControl == nil {
+		return
+	}
+
+	switch data := payload.Data.(type) {
+	case *model.LoadControlLimitListDataType:
+		index := slices.IndexFunc(data.LoadControlLimitData, func(item model.LoadControlLimitDataType) bool {
+			return item.LimitId != nil && *item.LimitId == c.limitId
+		})
+		if index < 0 {
+			return
+		}
+
+		limit, err := c.Limit()
+		if err != nil {
+			return
+		}
+		// a written relative end time is relative to the write
+		if data.LoadControlLimitData[index].TimePeriod != nil {
+			_ = c.SetLimit(limit)
+		}
+
+		c.ReportEvent(payload.Entity, c.events.DataUpdateLimit)
+		c.stateMachine.LimitUpdated(limit)
+
+	case *model.DeviceConfigurationKeyValueListDataType:
+		for _, item := range data.DeviceConfigurationKeyValueData {
+			switch {
+			case item.KeyId == nil:
+			case *item.KeyId == c.failsafeLimitKeyId:
+				c.ReportEvent(payload.Entity, c.events.DataUpdateFailsafeLimit)
+			case *item.KeyId == c.failsafeDurationKeyId:
+				c.ReportEvent(payload.Entity, c.events.DataUpdateFailsafeDurationMinimum)
+			}
+		}
+	}
+}
+
+// approve a remote write of the limit
+func (c *ControllableSystem) approveLimit(message *spineapi.Message, limit model.LoadControlLimitDataType) error {
+	err := c.approveWriter(message)
+	if err == nil {
+		err = validatePowerLimit(limit)
+	}
+
+	return c.reportRejection(message, err)
+}
+
+// approve a remote write of the failsafe active power limit
+func (c *ControllableSystem) approveFailsafeLimit(message *spineapi.Message, keyValue model.DeviceConfigurationKeyValueDataType) error {
+	err := c.approveWriter(message)
+	if err == nil {
+		err = validateFailsafeActivePowerLimit(*keyValue.Value)
+	}
+
+	return c.reportRejection(message, err)
+}
+
+// approve a remote write of the failsafe duration minimum
+//
+// the key is shared by LPC and LPP, so energy guards supporting either
+// use case may write it
+func (c *ControllableSystem) approveFailsafeDuration(message *spineapi.Message, keyValue model.DeviceConfigurationKeyValueDataType) error {
+	err := c.approveWriter(message)
+	if err != nil {
+		for _, useCase := range c.failsafeDurationUseCases {
+			if useCase.IsUseCaseSupported(message.EntityRemote) {
+				err = nil
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = validateFailsafeDurationKeyValue(*keyValue.Value)
+	}
+
+	return c.reportRejection(message, err)
+}
+
+// only remote entities supporting the use case may write
+func (c *ControllableSystem) approveWriter(message *spineapi.Message) error {
+	if !c.HasRemoteEntity(message.EntityRemote) {
+		return api.ErrUsecCaseNotSupported
+	}
+
+	return nil
+}
+
+// report a rejected remote write and return the error
+func (c *ControllableSystem) reportRejection(message *spineapi.Message, err error) error {
+	if err != nil {
+		logging.Log().Debug(c.scope.UseCaseName, "write rejected:", err)
+		c.ReportEvent(message.EntityRemote, c.events.WriteRejected)
+	}
+
+	return err
+}
+
+// invoked by the heartbeat monitor if no heartbeat was received in time
+func (c *ControllableSystem) heartbeatTimeout(entity spineapi.EntityRemoteInterface) {
+	if !c.isHeartbeatAlive() {
+		c.stateMachine.HeartbeatTimeout()
+	}
+}
+
+// return if a heartbeat of any energy guard is currently received
+func (c *ControllableSystem) isHeartbeatAlive() bool {
+	for _, entity := range c.RemoteEntities() {
+		if c.heartbeatMonitor.IsAlive(entity) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// return the current failsafe duration minimum
+func (c *ControllableSystem) currentFailsafeDuration() time.Duration {
+	if duration, _, err := c.FailsafeDurationMinimum(); err == nil {
+		return duration
+	}
+
+	return defaultFailsafeDurationMinimum
+}
+
+// return the current control state
+func (c *ControllableSystem) State() usecases.ControlState {
+	return c.stateMachine.State()
+}
+
+// return the limit which currently applies according to the control state
+//
+// return values:
+//   - value: the limit in W
+//   - limited: false if no limit applies
+func (c *ControllableSystem) EffectiveLimit() (value float64, limited bool) {
+	switch c.State() {
+	case usecases.ControlStateInit, usecases.ControlStateFailsafe:
+		if value, _, err := c.FailsafeLimit(); err == nil {
+			return value, true
+		}
+	case usecases.ControlStateLimited:
+		if limit, err := c.Limit(); err == nil {
+			return limit.Value, true
+		}
+	}
+
+	return 0, false
+}
+
+// return the current limit data
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no limit is available
+func (c *ControllableSystem) Limit() (usecases.LoadLimit, error) {
+	if c.loadControl == nil {
+		return usecases.LoadLimit{}, api.ErrFunctionNotSupported
+	}
+
+	data, err := c.loadControl.GetLimitForLimitId(c.limitId)
+	if err != nil {
+		return usecases.LoadLimit{}, err
+	}
+
+	return LoadLimitOfData(*data), nil
+}
+
+// set the current limit data
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+func (c *ControllableSystem) SetLimit(limit usecases.LoadLimit) error {
+	if c.loadControl == nil {
+		return api.ErrFunctionNotSupported
+	}
+
+	return c.loadControl.UpdateLimits(LocalLimitData(c.limitId, limit))
+}
+
+// return the failsafe active power limit
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no value is available
+func (c *ControllableSystem) FailsafeLimit() (value float64, isChangeable bool, err error) {
+	if c.deviceConfiguration == nil {
+		return 0, false, api.ErrFunctionNotSupported
+	}
+
+	keyValue, err := c.deviceConfiguration.GetKeyValueForKeyName(c.scope.FailsafeLimitKeyName)
+	if err != nil || keyValue.Value == nil || keyValue.Value.ScaledNumber == nil {
+		return 0, false, api.ErrDataNotAvailable
+	}
+	isChangeable = keyValue.IsValueChangeable != nil && *keyValue.IsValueChangeable
+
+	return keyValue.Value.ScaledNumber.GetValue(), isChangeable, nil
+}
+
+// set the failsafe active power limit
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - an error if the value is negative
+func (c *ControllableSystem) SetFailsafeLimit(value float64, changeable bool) error {
+	if c.deviceConfiguration == nil {
+		return api.ErrFunctionNotSupported
+	}
+
+	data := model.DeviceConfigurationKeyValueValueType{
+		ScaledNumber: model.NewScaledNumberType(value),
+	}
+	if err := validateFailsafeActivePowerLimit(data); err != nil {
+		return err
+	}
+
+	return c.deviceConfiguration.UpdateKeyValueForKeyName(c.scope.FailsafeLimitKeyName, data, changeable)
+}
+
+// return the failsafe duration minimum
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - ErrDataNotAvailable if no value is available
+func (c *ControllableSystem) FailsafeDurationMinimum() (duration time.Duration, isChangeable bool, err error) {
+	if c.deviceConfiguration == nil {
+		return 0, false, api.ErrFunctionNotSupported
+	}
+
+	keyValue, err := c.deviceConfiguration.GetKeyValueForKeyName(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum)
+	if err != nil || keyValue.Value == nil || keyValue.Value.Duration == nil {
+		return 0, false, api.ErrDataNotAvailable
+	}
+	isChangeable = keyValue.IsValueChangeable != nil && *keyValue.IsValueChangeable
+
+	duration, err = keyValue.Value.Duration.GetTimeDuration()
+	if err != nil {
+		return 0, false, api.ErrDataNotAvailable
+	}
+
+	return duration, isChangeable, nil
+}
+
+// set the failsafe duration minimum
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the use case was not added to the service
+//   - an error if the duration is not within the allowed range
+func (c *ControllableSystem) SetFailsafeDurationMinimum(duration time.Duration, changeable bool) error {
+	if c.deviceConfiguration == nil {
+		return api.ErrFunctionNotSupported
+	}
+
+	if err := ValidateFailsafeDurationMinimum(duration); err != nil {
+		return err
+	}
+
+	data := model.DeviceConfigurationKeyValueValueType{
+		Duration: model.NewDurationType(duration),
+	}
+
+	return c.deviceConfiguration.UpdateKeyValueForKeyName(
+		model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum, data, changeable)
+}
+
+// start sending heartbeats to all remote entities subscribed to the local heartbeat
+func (c *ControllableSystem) StartHeartbeat() error {
+	return c.LocalEntity.Device().HeartbeatManager().StartHeartbeat()
+}
+
+// stop sending heartbeats
+func (c *ControllableSystem) StopHeartbeat() {
+	c.LocalEntity.Device().HeartbeatManager().StopHeartbeat()
+}
+
+// return if the last heartbeat of an energy guard was received
+// within the heartbeat timeout
+func (c *ControllableSystem) IsHeartbeatWithinDuration() bool {
+	for _, entity := range c.RemoteEntities() {
+		deviceDiagnosis, err := features.NewDeviceDiagnosis(c.LocalEntity, entity)
+		if err != nil {
+			continue
+		}
+
+		if deviceDiagnosis.IsHeartbeatWithinDuration(powerLimitHeartbeatTimeout) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// return the nominal maximum active power
+//
+// possible errors:
+//   - ErrDataNotAvailable if no value was set
+func (c *ControllableSystem) PowerNominalMax() (float64, error) {
+	return LocalCharacteristic(c.LocalEntity, c.scope.NominalMaxCharacteristic)
+}
+
+// set the nominal maximum active power in W
+func (c *ControllableSystem) SetPowerNominalMax(value float64) error {
+	return SetLocalCharacteristic(c.LocalEntity, c.scope.NominalMaxCharacteristic, value)
+}
@@ -0,0 +1,544 @@
+package internal
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var testControllableSystemEvents = ControllableSystemEvents{
+	DataUpdateLimit:                   "DataUpdateLimit",
+	WriteRejected:                     "WriteRejected",
+	DataUpdateFailsafeLimit:           "DataUpdateFailsafeLimit",
+	DataUpdateFailsafeDurationMinimum: "DataUpdateFailsafeDurationMinimum",
+	DataUpdateHeartbeat:               "DataUpdateHeartbeat",
+}
+
+func TestControllableSystemSuite(t *testing.T) {
+	suite.Run(t, new(ControllableSystemSuite))
+}
+
+type ControllableSystemSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *ControllableSystem
+
+	events []api.EventType
+	states []usecases.ControlState
+	mux    sync.Mutex
+}
+
+func (s *ControllableSystemSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *ControllableSystemSuite) StateChanged(state usecases.ControlState) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.states = append(s.states, state)
+}
+
+func (s *ControllableSystemSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *ControllableSystemSuite) lastState() usecases.ControlState {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if len(s.states) == 0 {
+		return ""
+	}
+
+	return s.states[len(s.states)-1]
+}
+
+func (s *ControllableSystemSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.states = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeEVSE,
+		model.EntityTypeTypeCEM,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeDeviceDiagnosis,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceDiagnosisHeartbeatData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeLoadControl,
+				Role:        model.RoleTypeClient,
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Role:        model.RoleTypeClient,
+			},
+		},
+	)
+
+	s.sut = NewControllableSystem(s.localEntity, PowerConsumptionLimitScope, testControllableSystemEvents, s.Event, s.StateChanged)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+func (s *ControllableSystemSuite) AfterTest(suiteName, testName string) {
+	s.sut.StopHeartbeat()
+}
+
+// announce the use case support of the remote entity
+func (s *ControllableSystemSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeEnergyGuard,
+		model.UseCaseNameTypeLimitationOfPowerConsumption, true)
+	s.sut.HandleEvent(payload)
+}
+
+func (s *ControllableSystemSuite) heartbeat() {
+	payload := testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceDiagnosis,
+		model.FunctionTypeDeviceDiagnosisHeartbeatData,
+		&model.DeviceDiagnosisHeartbeatDataType{
+			Timestamp: model.NewAbsoluteOrRelativeTimeTypeFromTime(time.Now()),
+		})
+	s.sut.HandleEvent(payload)
+}
+
+// let the energy guard write the limit and return if the write was accepted
+func (s *ControllableSystemSuite) writeLimit(active bool, value float64) bool {
+	payload, err := testhelper.WriteLocalData(s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.CmdType{
+			LoadControlLimitListData: &model.LoadControlLimitListDataType{
+				LoadControlLimitData: []model.LoadControlLimitDataType{
+					{
+						LimitId:       util.Ptr(model.LoadControlLimitIdType(0)),
+						IsLimitActive: util.Ptr(active),
+						Value:         model.NewScaledNumberType(value),
+					},
+				},
+			},
+		})
+	if err != nil {
+		return false
+	}
+
+	s.sut.HandleEvent(payload)
+	return true
+}
+
+// let the energy guard write key values and return if the write was accepted
+func (s *ControllableSystemSuite) writeKeyValues(data []model.DeviceConfigurationKeyValueDataType) bool {
+	payload, err := testhelper.WriteLocalData(s.localEntity, s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.CmdType{
+			DeviceConfigurationKeyValueListData: &model.DeviceConfigurationKeyValueListDataType{
+				DeviceConfigurationKeyValueData: data,
+			},
+		})
+	if err != nil {
+		return false
+	}
+
+	s.sut.HandleEvent(payload)
+	return true
+}
+
+func (s *ControllableSystemSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeLoadControl,
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeDeviceDiagnosis,
+		model.FeatureTypeTypeElectricalConnection,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeServer))
+	}
+	assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(model.FeatureTypeTypeDeviceDiagnosis, model.RoleTypeClient))
+	assert.True(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeControllableSystem, model.UseCaseNameTypeLimitationOfPowerConsumption))
+
+	assert.Equal(s.T(), usecases.ControlStateInit, s.sut.State())
+}
+
+func (s *ControllableSystemSuite) Test_EntityConnected() {
+	s.connect()
+
+	assert.True(s.T(), s.sut.HasRemoteEntity(s.remoteEntity))
+	assert.True(s.T(), s.hasEvent(api.UseCaseSupportUpdate))
+	// subscription and heartbeat request
+	assert.Equal(s.T(), 2, s.writeHandler.Count())
+}
+
+func (s *ControllableSystemSuite) Test_Heartbeat() {
+	s.heartbeat()
+	assert.False(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateHeartbeat))
+
+	s.connect()
+	s.heartbeat()
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateHeartbeat))
+	assert.True(s.T(), s.sut.isHeartbeatAlive())
+}
+
+func (s *ControllableSystemSuite) Test_WriteLimit() {
+	// writes of entities not supporting the use case are rejected
+	assert.False(s.T(), s.writeLimit(true, 4200))
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.WriteRejected))
+	assert.False(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateLimit))
+	limit, err := s.sut.Limit()
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), limit.IsActive)
+
+	s.connect()
+
+	assert.True(s.T(), s.writeLimit(true, 4200))
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateLimit))
+	assert.Equal(s.T(), usecases.ControlStateLimited, s.sut.State())
+	assert.Equal(s.T(), usecases.ControlStateLimited, s.lastState())
+
+	limit, err = s.sut.Limit()
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), limit.IsActive)
+	assert.True(s.T(), limit.IsChangeable)
+	assert.Equal(s.T(), 4200.0, limit.Value)
+
+	s.writeLimit(false, 4200)
+	assert.Equal(s.T(), usecases.ControlStateUnlimitedControlled, s.sut.State())
+
+	s.events = nil
+	assert.False(s.T(), s.writeLimit(true, -1))
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.WriteRejected))
+	assert.False(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateLimit))
+	assert.Equal(s.T(), usecases.ControlStateUnlimitedControlled, s.sut.State())
+
+	// the application denies changes
+	s.events = nil
+	err = s.sut.SetLimit(usecases.LoadLimit{IsChangeable: false, Value: 1000})
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), s.writeLimit(true, 4200))
+	assert.False(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateLimit))
+	limit, err = s.sut.Limit()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1000.0, limit.Value)
+}
+
+func (s *ControllableSystemSuite) Test_WriteFailsafe() {
+	s.connect()
+
+	accepted := s.writeKeyValues([]model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				ScaledNumber: model.NewScaledNumberType(4200),
+			},
+		},
+		{
+			KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				Duration: model.NewDurationType(time.Hour * 3),
+			},
+		},
+	})
+	assert.True(s.T(), accepted)
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateFailsafeLimit))
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateFailsafeDurationMinimum))
+	assert.False(s.T(), s.hasEvent(testControllableSystemEvents.WriteRejected))
+
+	value, _, err := s.sut.FailsafeLimit()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, value)
+
+	duration, _, err := s.sut.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*3, duration)
+
+	s.events = nil
+	accepted = s.writeKeyValues([]model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				ScaledNumber: model.NewScaledNumberType(1000),
+			},
+		},
+		{
+			KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				Duration: model.NewDurationType(time.Hour),
+			},
+		},
+	})
+	assert.False(s.T(), accepted)
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.WriteRejected))
+	assert.False(s.T(), s.hasEvent(testControllableSystemEvents.DataUpdateFailsafeLimit))
+
+	// the whole write was rejected
+	value, _, err = s.sut.FailsafeLimit()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, value)
+
+	duration, _, err = s.sut.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*3, duration)
+}
+
+func (s *ControllableSystemSuite) Test_WriteFailsafeDurationOfOtherUseCase() {
+	duration := []model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				Duration: model.NewDurationType(time.Hour * 3),
+			},
+		},
+	}
+
+	// energy guards supporting neither LPC nor LPP may not write the duration
+	assert.False(s.T(), s.writeKeyValues(duration))
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.WriteRejected))
+
+	// the duration is shared with LPP, so its energy guard may write it
+	testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeEnergyGuard,
+		model.UseCaseNameTypeLimitationOfPowerProduction, true)
+	assert.False(s.T(), s.sut.HasRemoteEntity(s.remoteEntity))
+
+	s.events = nil
+	assert.True(s.T(), s.writeKeyValues(duration))
+	assert.False(s.T(), s.hasEvent(testControllableSystemEvents.WriteRejected))
+
+	value, _, err := s.sut.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*3, value)
+
+	// but not the failsafe limit of LPC
+	assert.False(s.T(), s.writeKeyValues([]model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				ScaledNumber: model.NewScaledNumberType(4200),
+			},
+		},
+	}))
+	assert.True(s.T(), s.hasEvent(testControllableSystemEvents.WriteRejected))
+}
+
+func (s *ControllableSystemSuite) Test_ProductionScope() {
+	lpp := NewControllableSystem(s.localEntity, PowerProductionLimitScope, testControllableSystemEvents, nil, nil)
+	lpp.AddFeatures()
+
+	// the limits and failsafe limits are separate
+	err := lpp.SetLimit(usecases.LoadLimit{IsChangeable: true, IsActive: true, Value: 1000})
+	assert.Nil(s.T(), err)
+	err = lpp.SetFailsafeLimit(2000, true)
+	assert.Nil(s.T(), err)
+
+	limit, err := s.sut.Limit()
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), limit.IsActive)
+	value, _, err := s.sut.FailsafeLimit()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), defaultFailsafeActivePowerLimit, value)
+
+	// the failsafe duration minimum is shared
+	err = lpp.SetFailsafeDurationMinimum(time.Hour*3, true)
+	assert.Nil(s.T(), err)
+	duration, _, err := s.sut.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*3, duration)
+
+	// an energy guard of LPC may write the shared duration
+	s.connect()
+	assert.True(s.T(), s.writeKeyValues([]model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: util.Ptr(lpp.failsafeDurationKeyId),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				Duration: model.NewDurationType(time.Hour * 4),
+			},
+		},
+	}))
+	duration, _, err = lpp.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*4, duration)
+
+	// but not the failsafe limit of LPP
+	assert.False(s.T(), s.writeKeyValues([]model.DeviceConfigurationKeyValueDataType{
+		{
+			KeyId: util.Ptr(lpp.failsafeLimitKeyId),
+			Value: &model.DeviceConfigurationKeyValueValueType{
+				ScaledNumber: model.NewScaledNumberType(4200),
+			},
+		},
+	}))
+
+	err = lpp.SetPowerNominalMax(5000)
+	assert.Nil(s.T(), err)
+	_, err = s.sut.PowerNominalMax()
+	assert.NotNil(s.T(), err)
+}
+
+func (s *ControllableSystemSuite) Test_HeartbeatTimeout() {
+	s.sut.heartbeatMonitor = NewHeartbeatMonitor(time.Millisecond*50, s.sut.heartbeatTimeout)
+
+	s.connect()
+	s.heartbeat()
+	s.writeLimit(true, 4200)
+	assert.Equal(s.T(), usecases.ControlStateLimited, s.sut.State())
+
+	assert.Eventually(s.T(), func() bool {
+		return s.lastState() == usecases.ControlStateFailsafe
+	}, time.Second, time.Millisecond*10)
+
+	// heartbeats received again within the failsafe duration minimum keep the failsafe state
+	s.heartbeat()
+	assert.Equal(s.T(), usecases.ControlStateFailsafe, s.sut.State())
+
+	s.writeLimit(false, 0)
+	assert.Equal(s.T(), usecases.ControlStateUnlimitedControlled, s.sut.State())
+
+	// a disconnected energy guard is handled like a heartbeat timeout
+	s.sut.HandleEvent(spineapi.EventPayload{
+		Ski:        testhelper.RemoteSki,
+		EventType:  spineapi.EventTypeDeviceChange,
+		ChangeType: spineapi.ElementChangeRemove,
+	})
+	assert.Equal(s.T(), usecases.ControlStateFailsafe, s.sut.State())
+}
+
+func (s *ControllableSystemSuite) Test_EffectiveLimit() {
+	err := s.sut.SetFailsafeLimit(3000, true)
+	assert.Nil(s.T(), err)
+
+	// init
+	value, limited := s.sut.EffectiveLimit()
+	assert.True(s.T(), limited)
+	assert.Equal(s.T(), 3000.0, value)
+
+	s.connect()
+
+	s.writeLimit(true, 4200)
+	value, limited = s.sut.EffectiveLimit()
+	assert.True(s.T(), limited)
+	assert.Equal(s.T(), 4200.0, value)
+
+	s.writeLimit(false, 4200)
+	_, limited = s.sut.EffectiveLimit()
+	assert.False(s.T(), limited)
+}
+
+func (s *ControllableSystemSuite) Test_Limit() {
+	sut := NewControllableSystem(s.localEntity, PowerConsumptionLimitScope, testControllableSystemEvents, nil, nil)
+	_, err := sut.Limit()
+	assert.NotNil(s.T(), err)
+	err = sut.SetLimit(usecases.LoadLimit{})
+	assert.NotNil(s.T(), err)
+
+	err = s.sut.SetLimit(usecases.LoadLimit{
+		IsChangeable: true,
+		IsActive:     true,
+		Value:        4200,
+		Duration:     time.Hour,
+	})
+	assert.Nil(s.T(), err)
+
+	limit, err := s.sut.Limit()
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), limit.IsChangeable)
+	assert.True(s.T(), limit.IsActive)
+	assert.Equal(s.T(), 4200.0, limit.Value)
+	assert.InDelta(s.T(), time.Hour.Seconds(), limit.Duration.Seconds(), 5)
+
+	// the control state is only changed by the energy guard
+	assert.Equal(s.T(), usecases.ControlStateInit, s.sut.State())
+}
+
+func (s *ControllableSystemSuite) Test_Failsafe() {
+	sut := NewControllableSystem(s.localEntity, PowerConsumptionLimitScope, testControllableSystemEvents, nil, nil)
+	_, _, err := sut.FailsafeLimit()
+	assert.NotNil(s.T(), err)
+	err = sut.SetFailsafeLimit(4200, true)
+	assert.NotNil(s.T(), err)
+	_, _, err = sut.FailsafeDurationMinimum()
+	assert.NotNil(s.T(), err)
+	err = sut.SetFailsafeDurationMinimum(time.Hour*2, true)
+	assert.NotNil(s.T(), err)
+
+	value, isChangeable, err := s.sut.FailsafeLimit()
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), isChangeable)
+	assert.Equal(s.T(), defaultFailsafeActivePowerLimit, value)
+
+	duration, isChangeable, err := s.sut.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), isChangeable)
+	assert.Equal(s.T(), defaultFailsafeDurationMinimum, duration)
+
+	err = s.sut.SetFailsafeLimit(-1, true)
+	assert.NotNil(s.T(), err)
+	err = s.sut.SetFailsafeLimit(4200, false)
+	assert.Nil(s.T(), err)
+
+	value, isChangeable, err = s.sut.FailsafeLimit()
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), isChangeable)
+	assert.Equal(s.T(), 4200.0, value)
+
+	err = s.sut.SetFailsafeDurationMinimum(time.Hour, true)
+	assert.NotNil(s.T(), err)
+	err = s.sut.SetFailsafeDurationMinimum(time.Hour*25, true)
+	assert.NotNil(s.T(), err)
+	err = s.sut.SetFailsafeDurationMinimum(time.Hour*3, false)
+	assert.Nil(s.T(), err)
+
+	duration, isChangeable, err = s.sut.FailsafeDurationMinimum()
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), isChangeable)
+	assert.Equal(s.T(), time.Hour*3, duration)
+	assert.Equal(s.T(), time.Hour*3, s.sut.currentFailsafeDuration())
+}
+
+func (s *ControllableSystemSuite) Test_IsHeartbeatWithinDuration() {
+	assert.False(s.T(), s.sut.IsHeartbeatWithinDuration())
+
+	s.connect()
+	assert.False(s.T(), s.sut.IsHeartbeatWithinDuration())
+
+	s.heartbeat()
+	assert.True(s.T(), s.sut.IsHeartbeatWithinDuration())
+}
+
+func (s *ControllableSystemSuite) Test_PowerNominalMax() {
+	_, err := s.sut.PowerNominalMax()
+	assert.NotNil(s.T(), err)
+
+	err = s.sut.SetPowerNominalMax(11000)
+	assert.Nil(s.T(), err)
+
+	value, err := s.sut.PowerNominalMax()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 11000.0, value)
+}
@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/enbility/eebus-go/usecases"
+)
+
+// the control state machine of a controllable system
+//
+// The transitions are:
+//   - Init: an accepted limit switches to Limited or UnlimitedControlled, after the
+//     init timeout to UnlimitedControlled if a heartbeat is received, otherwise to UnlimitedAutonomous
+//   - UnlimitedControlled and Limited: accepted limits switch between both states, an active
+//     limit ending switches to UnlimitedControlled, a heartbeat timeout switches to Failsafe
+//   - Failsafe: an accepted limit switches to Limited or UnlimitedControlled, after the failsafe
+//     duration minimum to UnlimitedControlled if a heartbeat is received, otherwise to UnlimitedAutonomous.
+//     A heartbeat received after the failsafe duration minimum switches to UnlimitedControlled
+//   - UnlimitedAutonomous: an accepted limit switches to Limited or UnlimitedControlled,
+//     a received heartbeat switches to UnlimitedControlled
+type ControlStateMachine struct {
+	state usecases.ControlState
+
+	initTimeout      time.Duration
+	failsafeDuration func() time.Duration
+	heartbeatAlive   func() bool
+	callback         usecases.ControlStateCallback
+
+	// the timer of the current state, if the state ends after a timeout
+	timer *time.Timer
+	// incremented on every transition to ignore timers of previous states
+	generation uint64
+	// the earliest time the Failsafe state may be left without a new limit
+	failsafeUntil time.Time
+
+	mux sync.Mutex
+}
+
+// creates a new state machine in the Init state
+//
+// failsafeDuration has to return the current failsafe duration minimum,
+// heartbeatAlive if a heartbeat of an energy guard is currently received.
+// callback is optional and invoked on every state change
+func NewControlStateMachine(
+	initTimeout time.Duration,
+	failsafeDuration func() time.Duration,
+	heartbeatAlive func() bool,
+	callback usecases.ControlStateCallback,
+) *ControlStateMachine {
+	return &ControlStateMachine{
+		state:            usecases.ControlStateInit,
+		initTimeout:      initTimeout,
+		failsafeDuration: failsafeDuration,
+		heartbeatAlive:   heartbeatAlive,
+		callback:         callback,
+	}
+}
+
+// start the init timeout
+func (m *ControlStateMachine) Start() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if m.state == usecases.ControlStateInit {
+		m.transition(usecases.ControlStateInit, m.initTimeout)
+	}
+}
+
+// return the current state
+func (m *ControlStateMachine) State() usecases.ControlState {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.state
+}
+
+// an accepted limit was written by an energy guard
+func (m *ControlStateMachine) LimitUpdated(limit usecases.LoadLimit) {
+	m.mux.Lock()
+
+	var changed bool
+	if limit.IsActive && limit.Duration >= 0 {
+		changed = m.transition(usecases.ControlStateLimited, limit.Duration)
+	} else {
+		changed = m.transition(usecases.ControlStateUnlimitedControlled, 0)
+	}
+
+	m.mux.Unlock()
+
+	m.notify(changed)
+}
+
+// the heartbeat of all energy guards timed out
+func (m *ControlStateMachine) HeartbeatTimeout() {
+	m.mux.Lock()
+
+	var changed bool
+	if m.state == usecases.ControlStateUnlimitedControlled || m.state == usecases.ControlStateLimited {
+		duration := m.failsafeDuration()
+		m.failsafeUntil = time.Now().Add(duration)
+		changed = m.transition(usecases.ControlStateFailsafe, duration)
+	}
+
+	m.mux.Unlock()
+
+	m.notify(changed)
+}
+
+// a heartbeat of an energy guard was received
+//
+// the Failsafe state is kept until the failsafe duration minimum elapsed,
+// which is then handled by the timer of the state
+func (m *ControlStateMachine) HeartbeatReceived() {
+	m.mux.Lock()
+
+	var changed bool
+	switch m.state {
+	case usecases.ControlStateUnlimitedAutonomous:
+		changed = m.transition(usecases.ControlStateUnlimitedControlled, 0)
+	case usecases.ControlStateFailsafe:
+		if !time.Now().Before(m.failsafeUntil) {
+			changed = m.transition(usecases.ControlStateUnlimitedControlled, 0)
+		}
+	}
+
+	m.mux.Unlock()
+
+	m.notify(changed)
+}
+
+// invoked when the timer of a state elapsed
+func (m *ControlStateMachine) timeout(generation uint64) {
+	m.mux.Lock()
+
+	// the state changed in the meantime
+	if m.generation != generation {
+		m.mux.Unlock()
+		return
+	}
+	m.timer = nil
+
+	var changed bool
+	switch m.state {
+	case usecases.ControlStateLimited:
+		changed = m.transition(usecases.ControlStateUnlimitedControlled, 0)
+	case usecases.ControlStateInit, usecases.ControlStateFailsafe:
+		if m.heartbeatAlive != nil && m.heartbeatAlive() {
+			changed = m.transition(usecases.ControlStateUnlimitedControlled, 0)
+		} else {
+			changed = m.transition(usecases.ControlStateUnlimitedAutonomous, 0)
+		}
+	}
+
+	m.mux.Unlock()
+
+	m.notify(changed)
+}
+
+// switch to a state which ends after the timeout, if it is > 0
+//
+// has to be invoked with the lock held, returns if the state changed
+func (m *ControlStateMachine) transition(state usecases.ControlState, timeout time.Duration) bool {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+
+	m.generation++
+	if timeout > 0 {
+		generation := m.generation
+		m.timer = time.AfterFunc(timeout, func() {
+			m.timeout(generation)
+		})
+	}
+
+	changed := m.state != state
+	m.state = state
+
+	return changed
+}
+
+func (m *ControlStateMachine) notify(changed bool) {
+	if changed && m.callback != nil {
+		m.callback(m.State())
+	}
+}
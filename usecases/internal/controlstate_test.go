@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/stretchr/testify/assert"
+)
+
+type stateRecorder struct {
+	states []usecases.ControlState
+	mux    sync.Mutex
+}
+
+func (r *stateRecorder) callback(state usecases.ControlState) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	r.states = append(r.states, state)
+}
+
+func (r *stateRecorder) last() usecases.ControlState {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if len(r.states) == 0 {
+		return ""
+	}
+
+	return r.states[len(r.states)-1]
+}
+
+func TestControlStateMachine(t *testing.T) {
+	recorder := &stateRecorder{}
+	alive := false
+	var aliveMux sync.Mutex
+	heartbeatAlive := func() bool {
+		aliveMux.Lock()
+		defer aliveMux.Unlock()
+		return alive
+	}
+	failsafeDuration := func() time.Duration { return time.Millisecond * 50 }
+
+	sut := NewControlStateMachine(time.Millisecond*50, failsafeDuration, heartbeatAlive, recorder.callback)
+	assert.Equal(t, usecases.ControlStateInit, sut.State())
+
+	// init timeout without heartbeat
+	sut.Start()
+	assert.Eventually(t, func() bool {
+		return sut.State() == usecases.ControlStateUnlimitedAutonomous
+	}, time.Second, time.Millisecond*10)
+	assert.Equal(t, usecases.ControlStateUnlimitedAutonomous, recorder.last())
+
+	// a heartbeat timeout in an autonomous state is ignored
+	sut.HeartbeatTimeout()
+	assert.Equal(t, usecases.ControlStateUnlimitedAutonomous, sut.State())
+
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: true, Value: 4200})
+	assert.Equal(t, usecases.ControlStateLimited, sut.State())
+	assert.Equal(t, usecases.ControlStateLimited, recorder.last())
+
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: false, Value: 4200})
+	assert.Equal(t, usecases.ControlStateUnlimitedControlled, sut.State())
+
+	// an active limit ending
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: true, Value: 4200, Duration: time.Millisecond * 50})
+	assert.Equal(t, usecases.ControlStateLimited, sut.State())
+	assert.Eventually(t, func() bool {
+		return sut.State() == usecases.ControlStateUnlimitedControlled
+	}, time.Second, time.Millisecond*10)
+
+	// an expired limit
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: true, Value: 4200, Duration: -time.Minute})
+	assert.Equal(t, usecases.ControlStateUnlimitedControlled, sut.State())
+
+	// failsafe without heartbeat
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: true, Value: 4200})
+	sut.HeartbeatTimeout()
+	assert.Equal(t, usecases.ControlStateFailsafe, sut.State())
+	assert.Eventually(t, func() bool {
+		return sut.State() == usecases.ControlStateUnlimitedAutonomous
+	}, time.Second, time.Millisecond*10)
+
+	// failsafe with heartbeat
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: false})
+	sut.HeartbeatTimeout()
+	assert.Equal(t, usecases.ControlStateFailsafe, sut.State())
+	aliveMux.Lock()
+	alive = true
+	aliveMux.Unlock()
+	assert.Eventually(t, func() bool {
+		return sut.State() == usecases.ControlStateUnlimitedControlled
+	}, time.Second, time.Millisecond*10)
+
+	// a new limit ends the failsafe state
+	sut.HeartbeatTimeout()
+	assert.Equal(t, usecases.ControlStateFailsafe, sut.State())
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: true, Value: 4200})
+	assert.Equal(t, usecases.ControlStateLimited, sut.State())
+
+	// the timer of the previous state is ignored
+	time.Sleep(time.Millisecond * 100)
+	assert.Equal(t, usecases.ControlStateLimited, sut.State())
+}
+
+func TestControlStateMachineInitWithHeartbeat(t *testing.T) {
+	sut := NewControlStateMachine(time.Millisecond*50, nil, func() bool { return true }, nil)
+
+	// a heartbeat timeout during init is ignored
+	sut.HeartbeatTimeout()
+	assert.Equal(t, usecases.ControlStateInit, sut.State())
+
+	sut.Start()
+	assert.Eventually(t, func() bool {
+		return sut.State() == usecases.ControlStateUnlimitedControlled
+	}, time.Second, time.Millisecond*10)
+}
+
+func TestControlStateMachineHeartbeatReceived(t *testing.T) {
+	recorder := &stateRecorder{}
+	duration := time.Hour
+	var durationMux sync.Mutex
+	failsafeDuration := func() time.Duration {
+		durationMux.Lock()
+		defer durationMux.Unlock()
+		return duration
+	}
+
+	sut := NewControlStateMachine(time.Millisecond*50, failsafeDuration, func() bool { return false }, recorder.callback)
+
+	// a heartbeat during init is ignored
+	sut.HeartbeatReceived()
+	assert.Equal(t, usecases.ControlStateInit, sut.State())
+
+	sut.Start()
+	assert.Eventually(t, func() bool {
+		return sut.State() == usecases.ControlStateUnlimitedAutonomous
+	}, time.Second, time.Millisecond*10)
+
+	// a heartbeat ends the autonomous state
+	sut.HeartbeatReceived()
+	assert.Equal(t, usecases.ControlStateUnlimitedControlled, sut.State())
+	assert.Equal(t, usecases.ControlStateUnlimitedControlled, recorder.last())
+
+	// a heartbeat in a controlled state is ignored
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: true, Value: 4200})
+	sut.HeartbeatReceived()
+	assert.Equal(t, usecases.ControlStateLimited, sut.State())
+
+	// the failsafe state is kept within the failsafe duration minimum
+	sut.HeartbeatTimeout()
+	assert.Equal(t, usecases.ControlStateFailsafe, sut.State())
+	sut.HeartbeatReceived()
+	assert.Equal(t, usecases.ControlStateFailsafe, sut.State())
+
+	// a heartbeat after the failsafe duration minimum ends the failsafe state
+	durationMux.Lock()
+	duration = 0
+	durationMux.Unlock()
+	sut.LimitUpdated(usecases.LoadLimit{IsActive: false})
+	sut.HeartbeatTimeout()
+	assert.Equal(t, usecases.ControlStateFailsafe, sut.State())
+	sut.HeartbeatReceived()
+	assert.Equal(t, usecases.ControlStateUnlimitedControlled, sut.State())
+	assert.Equal(t, usecases.ControlStateUnlimitedControlled, recorder.last())
+}
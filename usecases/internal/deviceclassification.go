@@ -0,0 +1,51 @@
+package internal
+
+import (
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	spineapi "github.com/enbility/spine-go/api"
+)
+
+// return the manufacturer details of a remote entity
+//
+// possible errors:
+//   - ErrDataNotAvailable if no manufacturer details are available
+//   - and others
+func ManufacturerData(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+) (usecases.ManufacturerData, error) {
+	deviceClassification, err := features.NewDeviceClassification(localEntity, remoteEntity)
+	if err != nil {
+		return usecases.ManufacturerData{}, err
+	}
+
+	data, err := deviceClassification.GetManufacturerDetails()
+	if err != nil {
+		return usecases.ManufacturerData{}, err
+	}
+
+	return usecases.ManufacturerData{
+		DeviceName:                     optionalString(data.DeviceName),
+		DeviceCode:                     optionalString(data.DeviceCode),
+		SerialNumber:                   optionalString(data.SerialNumber),
+		SoftwareRevision:               optionalString(data.SoftwareRevision),
+		HardwareRevision:               optionalString(data.HardwareRevision),
+		VendorName:                     optionalString(data.VendorName),
+		VendorCode:                     optionalString(data.VendorCode),
+		BrandName:                      optionalString(data.BrandName),
+		PowerSource:                    optionalString(data.PowerSource),
+		ManufacturerNodeIdentification: optionalString(data.ManufacturerNodeIdentification),
+		ManufacturerLabel:              optionalString(data.ManufacturerLabel),
+		ManufacturerDescription:        optionalString(data.ManufacturerDescription),
+	}, nil
+}
+
+// return the string value of an optional string based type, empty if not set
+func optionalString[T ~string](value *T) string {
+	if value == nil {
+		return ""
+	}
+
+	return string(*value)
+}
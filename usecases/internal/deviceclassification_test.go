@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManufacturerData(t *testing.T) {
+	localEntity, remoteEntity := testhelper.SetupEntities(t, &testhelper.WriteMessageHandler{},
+		model.EntityTypeTypeCEM, model.EntityTypeTypeEV,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeDeviceClassification,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceClassificationManufacturerData,
+				},
+			},
+		})
+	localEntity.GetOrAddFeature(model.FeatureTypeTypeDeviceClassification, model.RoleTypeClient)
+
+	_, err := ManufacturerData(localEntity, nil)
+	assert.NotNil(t, err)
+
+	_, err = ManufacturerData(localEntity, remoteEntity)
+	assert.NotNil(t, err)
+
+	testhelper.SetRemoteData(remoteEntity, model.FeatureTypeTypeDeviceClassification,
+		model.FunctionTypeDeviceClassificationManufacturerData,
+		&model.DeviceClassificationManufacturerDataType{
+			DeviceName:   util.Ptr(model.DeviceClassificationStringType("ev")),
+			SerialNumber: util.Ptr(model.DeviceClassificationStringType("1234")),
+			BrandName:    util.Ptr(model.DeviceClassificationStringType("brand")),
+			PowerSource:  util.Ptr(model.PowerSourceTypeMains3Phase),
+		})
+
+	data, err := ManufacturerData(localEntity, remoteEntity)
+	assert.Nil(t, err)
+	assert.Equal(t, "ev", data.DeviceName)
+	assert.Equal(t, "1234", data.SerialNumber)
+	assert.Equal(t, "brand", data.BrandName)
+	assert.Equal(t, string(model.PowerSourceTypeMains3Phase), data.PowerSource)
+	assert.Equal(t, "", data.VendorName)
+}
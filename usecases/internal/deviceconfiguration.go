@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"errors"
+	"time"
+
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the failsafe duration minimum has to be within this range
+const (
+	FailsafeDurationMinimumMin = time.Hour * 2
+	FailsafeDurationMinimumMax = time.Hour * 24
+)
+
+// return an error if the failsafe duration minimum is outside of the allowed range
+func ValidateFailsafeDurationMinimum(duration time.Duration) error {
+	if duration < FailsafeDurationMinimumMin || duration > FailsafeDurationMinimumMax {
+		return errors.New("duration outside of the allowed range")
+	}
+
+	return nil
+}
+
+// return the scaled number value of a key of a remote entity
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is described with a different value type
+//   - ErrDataNotAvailable if no value for the key is available
+//   - and others
+func KeyValueScaledNumber(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	keyName model.DeviceConfigurationKeyNameType,
+) (float64, error) {
+	deviceConfiguration, err := features.NewDeviceConfiguration(localEntity, remoteEntity)
+	if err != nil {
+		return 0, err
+	}
+
+	return deviceConfiguration.GetKeyValueScaledNumberForKeyName(keyName)
+}
+
+// return the duration value of a key of a remote entity
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is described with a different value type
+//   - ErrDataNotAvailable if no value for the key is available
+//   - and others
+func KeyValueDuration(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	keyName model.DeviceConfigurationKeyNameType,
+) (time.Duration, error) {
+	deviceConfiguration, err := features.NewDeviceConfiguration(localEntity, remoteEntity)
+	if err != nil {
+		return 0, err
+	}
+
+	return deviceConfiguration.GetKeyValueDurationForKeyName(keyName)
+}
+
+// return the string value of a key of a remote entity
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is described with a different value type
+//   - ErrDataNotAvailable if no value for the key is available
+//   - and others
+func KeyValueString(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	keyName model.DeviceConfigurationKeyNameType,
+) (model.DeviceConfigurationKeyValueStringType, error) {
+	deviceConfiguration, err := features.NewDeviceConfiguration(localEntity, remoteEntity)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := deviceConfiguration.GetKeyValueStringForKeyName(keyName)
+	if err != nil {
+		return "", err
+	}
+
+	return model.DeviceConfigurationKeyValueStringType(value), nil
+}
+
+// return the boolean value of a key of a remote entity
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the key is described with a different value type
+//   - ErrDataNotAvailable if no value for the key is available
+//   - and others
+func KeyValueBoolean(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	keyName model.DeviceConfigurationKeyNameType,
+) (bool, error) {
+	deviceConfiguration, err := features.NewDeviceConfiguration(localEntity, remoteEntity)
+	if err != nil {
+		return false, err
+	}
+
+	return deviceConfiguration.GetKeyValueBooleanForKeyName(keyName)
+}
+
+// write the value of a key of a remote entity
+//
+// the value is written partially, the values of all other keys are kept
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no description for the key is available
+//   - ErrValueTypeMismatch if the value does not match the value type of the description
+//   - ErrNotSupported if the value is not changeable
+//   - and others
+func WriteKeyValue(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	keyName model.DeviceConfigurationKeyNameType,
+	value model.DeviceConfigurationKeyValueValueType,
+) (*model.MsgCounterType, error) {
+	deviceConfiguration, err := features.NewDeviceConfiguration(localEntity, remoteEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	return deviceConfiguration.WriteKeyValueForKeyName(keyName, value)
+}
+
+// add a key to a local device configuration server feature and set its
+// initial value, which is changeable by remote clients
+//
+// keys may be shared by use cases, e.g. the failsafe duration minimum of
+// LPC and LPP, so the key id of an already described key is returned and
+// its value is kept
+func AddLocalKeyValue(
+	deviceConfiguration *features.DeviceConfigurationServer,
+	description model.DeviceConfigurationKeyValueDescriptionDataType,
+	value model.DeviceConfigurationKeyValueValueType,
+) (model.DeviceConfigurationKeyIdType, error) {
+	if description.KeyName != nil {
+		if existing, err := deviceConfiguration.GetKeyValueDescriptionForKeyName(*description.KeyName); err == nil {
+			return *existing.KeyId, nil
+		}
+	}
+
+	keyId, err := deviceConfiguration.AddKeyValueDescription(description)
+	if err != nil {
+		return 0, err
+	}
+
+	return keyId, deviceConfiguration.UpdateKeyValues(model.DeviceConfigurationKeyValueDataType{
+		KeyId:             util.Ptr(keyId),
+		Value:             util.Ptr(value),
+		IsValueChangeable: util.Ptr(true),
+	})
+}
@@ -0,0 +1,228 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDeviceConfigurationSuite(t *testing.T) {
+	suite.Run(t, new(DeviceConfigurationSuite))
+}
+
+type DeviceConfigurationSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+}
+
+func (s *DeviceConfigurationSuite) BeforeTest(suiteName, testName string) {
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEVSE,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+					model.FunctionTypeDeviceConfigurationKeyValueListData,
+				},
+			},
+		},
+	)
+	s.localEntity.GetOrAddFeature(model.FeatureTypeTypeDeviceConfiguration, model.RoleTypeClient)
+}
+
+func (s *DeviceConfigurationSuite) addDescription() {
+	descData := &model.DeviceConfigurationKeyValueDescriptionListDataType{
+		DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+			{
+				KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+				KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeConsumptionActivePowerLimit),
+				ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+			},
+			{
+				KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+				KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum),
+				ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+			},
+			{
+				KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(2)),
+				KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeCommunicationsStandard),
+				ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeString),
+			},
+			{
+				KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(3)),
+				KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeAsymmetricChargingSupported),
+				ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeBoolean),
+			},
+		},
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData, descData)
+}
+
+func (s *DeviceConfigurationSuite) addData() {
+	data := &model.DeviceConfigurationKeyValueListDataType{
+		DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+			{
+				KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+				Value: &model.DeviceConfigurationKeyValueValueType{
+					ScaledNumber: model.NewScaledNumberType(4200),
+				},
+			},
+			{
+				KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+				Value: &model.DeviceConfigurationKeyValueValueType{
+					Duration: model.NewDurationType(time.Hour * 2),
+				},
+			},
+			{
+				KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(2)),
+				Value: &model.DeviceConfigurationKeyValueValueType{
+					String: util.Ptr(model.DeviceConfigurationKeyValueStringTypeISO151182ED2),
+				},
+			},
+			{
+				KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(3)),
+				Value: &model.DeviceConfigurationKeyValueValueType{
+					Boolean: util.Ptr(true),
+				},
+			},
+		},
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData, data)
+}
+
+func (s *DeviceConfigurationSuite) Test_KeyValues() {
+	powerKey := model.DeviceConfigurationKeyNameTypeFailsafeConsumptionActivePowerLimit
+	durationKey := model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum
+
+	_, err := KeyValueScaledNumber(s.localEntity, nil, powerKey)
+	assert.NotNil(s.T(), err)
+	_, err = KeyValueDuration(s.localEntity, nil, durationKey)
+	assert.NotNil(s.T(), err)
+
+	_, err = KeyValueScaledNumber(s.localEntity, s.remoteEntity, powerKey)
+	assert.NotNil(s.T(), err)
+	_, err = KeyValueDuration(s.localEntity, s.remoteEntity, durationKey)
+	assert.NotNil(s.T(), err)
+
+	s.addDescription()
+	s.addData()
+
+	value, err := KeyValueScaledNumber(s.localEntity, s.remoteEntity, powerKey)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, value)
+
+	duration, err := KeyValueDuration(s.localEntity, s.remoteEntity, durationKey)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*2, duration)
+
+	// a key with a different value type
+	_, err = KeyValueScaledNumber(s.localEntity, s.remoteEntity, durationKey)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *DeviceConfigurationSuite) Test_KeyValuesStringBoolean() {
+	stringKey := model.DeviceConfigurationKeyNameTypeCommunicationsStandard
+	booleanKey := model.DeviceConfigurationKeyNameTypeAsymmetricChargingSupported
+
+	_, err := KeyValueString(s.localEntity, nil, stringKey)
+	assert.NotNil(s.T(), err)
+	_, err = KeyValueBoolean(s.localEntity, nil, booleanKey)
+	assert.NotNil(s.T(), err)
+
+	_, err = KeyValueString(s.localEntity, s.remoteEntity, stringKey)
+	assert.NotNil(s.T(), err)
+	_, err = KeyValueBoolean(s.localEntity, s.remoteEntity, booleanKey)
+	assert.NotNil(s.T(), err)
+
+	s.addDescription()
+	s.addData()
+
+	value, err := KeyValueString(s.localEntity, s.remoteEntity, stringKey)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), model.DeviceConfigurationKeyValueStringTypeISO151182ED2, value)
+
+	supported, err := KeyValueBoolean(s.localEntity, s.remoteEntity, booleanKey)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), supported)
+
+	// a key with a different value type
+	_, err = KeyValueBoolean(s.localEntity, s.remoteEntity, stringKey)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *DeviceConfigurationSuite) Test_WriteKeyValue() {
+	key := model.DeviceConfigurationKeyNameTypeFailsafeConsumptionActivePowerLimit
+	value := model.DeviceConfigurationKeyValueValueType{
+		ScaledNumber: model.NewScaledNumberType(5000),
+	}
+
+	_, err := WriteKeyValue(s.localEntity, nil, key, value)
+	assert.NotNil(s.T(), err)
+
+	_, err = WriteKeyValue(s.localEntity, s.remoteEntity, key, value)
+	assert.NotNil(s.T(), err)
+
+	s.addDescription()
+
+	msgCounter, err := WriteKeyValue(s.localEntity, s.remoteEntity, key, value)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	datagram := s.writeHandler.LastDatagram()
+	assert.NotNil(s.T(), datagram)
+	data := datagram.Payload.Cmd[0].DeviceConfigurationKeyValueListData
+	assert.NotNil(s.T(), data)
+	assert.Equal(s.T(), 5000.0, data.DeviceConfigurationKeyValueData[0].Value.ScaledNumber.GetValue())
+}
+
+func (s *DeviceConfigurationSuite) Test_AddLocalKeyValue() {
+	deviceConfiguration, err := features.NewDeviceConfigurationServer(s.localEntity)
+	assert.Nil(s.T(), err)
+
+	description := model.DeviceConfigurationKeyValueDescriptionDataType{
+		KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum),
+		ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+	}
+	keyId, err := AddLocalKeyValue(deviceConfiguration, description,
+		model.DeviceConfigurationKeyValueValueType{Duration: model.NewDurationType(time.Hour * 2)})
+	assert.Nil(s.T(), err)
+
+	keyValue, err := deviceConfiguration.GetKeyValueForKeyName(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), keyId, *keyValue.KeyId)
+	assert.True(s.T(), *keyValue.IsValueChangeable)
+
+	// the already described key and its value are kept
+	otherId, err := AddLocalKeyValue(deviceConfiguration, description,
+		model.DeviceConfigurationKeyValueValueType{Duration: model.NewDurationType(time.Hour * 3)})
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), keyId, otherId)
+
+	keyValue, err = deviceConfiguration.GetKeyValueForKeyName(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum)
+	assert.Nil(s.T(), err)
+	duration, err := keyValue.Value.Duration.GetTimeDuration()
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*2, duration)
+
+	_, err = AddLocalKeyValue(deviceConfiguration, model.DeviceConfigurationKeyValueDescriptionDataType{},
+		model.DeviceConfigurationKeyValueValueType{})
+	assert.NotNil(s.T(), err)
+}
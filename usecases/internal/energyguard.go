@@ -0,0 +1,344 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the events reported by the energy guard of LPC or LPP
+type EnergyGuardEvents struct {
+	// load control limit data was updated
+	DataUpdateLimit api.EventType
+
+	// a written limit was accepted by the controllable system
+	LimitAccepted api.EventType
+
+	// a written limit was rejected by the controllable system
+	LimitRejected api.EventType
+
+	// the failsafe active power limit was updated
+	DataUpdateFailsafeLimit api.EventType
+
+	// the failsafe duration minimum was updated
+	DataUpdateFailsafeDurationMinimum api.EventType
+
+	// the nominal maximum active power was updated
+	DataUpdateNominalMax api.EventType
+
+	// a heartbeat of the controllable system was received
+	DataUpdateHeartbeat api.EventType
+
+	// no heartbeat of the controllable system was received within the heartbeat timeout
+	HeartbeatTimeout api.EventType
+}
+
+// the actor Energy Guard of LPC and LPP
+type EnergyGuard struct {
+	*usecases.UseCase
+
+	scope  PowerLimitScope
+	events EnergyGuardEvents
+
+	heartbeatMonitor *HeartbeatMonitor
+}
+
+// creates a new energy guard of the use case of the scope for the local entity
+//
+// validEntityTypes are the entity types of the supported controllable systems,
+// eventCB is invoked for all events of remote controllable systems
+func NewEnergyGuard(
+	localEntity spineapi.EntityLocalInterface,
+	scope PowerLimitScope,
+	events EnergyGuardEvents,
+	validEntityTypes []model.EntityTypeType,
+	eventCB api.EntityEventCallback,
+) *EnergyGuard {
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeLoadControl,
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeDeviceDiagnosis,
+		model.FeatureTypeTypeElectricalConnection,
+	}
+
+	e := &EnergyGuard{
+		scope:  scope,
+		events: events,
+	}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeEnergyGuard,
+		scope.UseCaseName,
+		"1.0.0",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3, 4},
+		[]model.UseCaseActorType{model.UseCaseActorTypeControllableSystem},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+	e.heartbeatMonitor = NewHeartbeatMonitor(powerLimitHeartbeatTimeout, e.heartbeatTimeout)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*EnergyGuard)(nil)
+var _ usecases.EntityHandlerInterface = (*EnergyGuard)(nil)
+
+// add the client features and the device diagnosis server feature
+// providing the heartbeats of the energy guard
+func (e *EnergyGuard) AddFeatures() {
+	e.UseCase.AddFeatures()
+
+	feature := e.LocalEntity.GetOrAddFeature(model.FeatureTypeTypeDeviceDiagnosis, model.RoleTypeServer)
+	feature.AddFunctionType(model.FunctionTypeDeviceDiagnosisHeartbeatData, true, false)
+
+	e.LocalEntity.Device().HeartbeatManager().SetLocalFeature(e.LocalEntity, feature)
+}
+
+// bind to the features required for writing and request the initial data
+func (e *EnergyGuard) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if loadControl, err := features.NewLoadControl(e.LocalEntity, entity); err == nil {
+		if !loadControl.HasBinding() {
+			_, _ = loadControl.Bind()
+		}
+
+		if _, err := loadControl.RequestLimitDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, entity); err == nil {
+		if !deviceConfiguration.HasBinding() {
+			_, _ = deviceConfiguration.Bind()
+		}
+
+		if _, err := deviceConfiguration.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if deviceDiagnosis, err := features.NewDeviceDiagnosis(e.LocalEntity, entity); err == nil {
+		if _, err := deviceDiagnosis.RequestHeartbeat(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestCharacteristics(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	// the heartbeat has to be received within the timeout after connecting
+	e.heartbeatMonitor.Heartbeat(entity)
+}
+
+func (e *EnergyGuard) EntityDisconnected(entity spineapi.EntityRemoteInterface) {
+	e.heartbeatMonitor.Stop(entity)
+}
+
+func (e *EnergyGuard) HandleDataChange(payload spineapi.EventPayload) {
+	switch payload.Data.(type) {
+	case *model.LoadControlLimitDescriptionListDataType:
+		if loadControl, err := features.NewLoadControl(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := loadControl.RequestLimitValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.LoadControlLimitListDataType:
+		if _, err := e.Limit(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, e.events.DataUpdateLimit)
+		}
+
+	case *model.DeviceConfigurationKeyValueDescriptionListDataType:
+		if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := deviceConfiguration.RequestKeyValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.DeviceConfigurationKeyValueListDataType:
+		if _, err := e.FailsafeLimit(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, e.events.DataUpdateFailsafeLimit)
+		}
+
+		if _, err := e.FailsafeDurationMinimum(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, e.events.DataUpdateFailsafeDurationMinimum)
+		}
+
+	case *model.ElectricalConnectionCharacteristicListDataType:
+		if _, err := e.PowerNominalMax(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, e.events.DataUpdateNominalMax)
+		}
+
+	case *model.DeviceDiagnosisHeartbeatDataType:
+		e.heartbeatMonitor.Heartbeat(payload.Entity)
+		e.ReportEvent(payload.Entity, e.events.DataUpdateHeartbeat)
+	}
+}
+
+// invoked by the heartbeat monitor if no heartbeat was received in time
+func (e *EnergyGuard) heartbeatTimeout(entity spineapi.EntityRemoteInterface) {
+	e.ReportEvent(entity, e.events.HeartbeatTimeout)
+}
+
+// return the current limit data of a controllable system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no limit description is available
+//   - ErrDataNotAvailable if no limit value is available
+//   - and others
+func (e *EnergyGuard) Limit(entity spineapi.EntityRemoteInterface) (usecases.LoadLimit, error) {
+	if !e.HasRemoteEntity(entity) {
+		return usecases.LoadLimit{}, api.ErrUsecCaseNotSupported
+	}
+
+	return LoadLimit(e.LocalEntity, entity, e.scope.LimitFilter())
+}
+
+// send a new limit to a controllable system
+//
+// the result of the write is reported via the LimitAccepted or LimitRejected event
+func (e *EnergyGuard) WriteLimit(entity spineapi.EntityRemoteInterface, limit usecases.LoadLimit) (*model.MsgCounterType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	msgCounter, err := WriteLoadLimit(e.LocalEntity, entity, e.scope.LimitFilter(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if loadControl, err := features.NewLoadControl(e.LocalEntity, entity); err == nil && msgCounter != nil {
+		loadControl.AddResultCallback(*msgCounter, func(msg spineapi.ResultMessage) {
+			event := e.events.LimitAccepted
+			if msg.Result != nil && msg.Result.ErrorNumber != nil &&
+				*msg.Result.ErrorNumber != model.ErrorNumberTypeNoError {
+				event = e.events.LimitRejected
+			}
+			e.ReportEvent(entity, event)
+		})
+	}
+
+	return msgCounter, nil
+}
+
+// return the failsafe active power limit of a controllable system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no value is available
+//   - and others
+func (e *EnergyGuard) FailsafeLimit(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	return KeyValueScaledNumber(e.LocalEntity, entity, e.scope.FailsafeLimitKeyName)
+}
+
+// send a new failsafe active power limit in W to a controllable system
+func (e *EnergyGuard) WriteFailsafeLimit(entity spineapi.EntityRemoteInterface, value float64) (*model.MsgCounterType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	data := model.DeviceConfigurationKeyValueValueType{
+		ScaledNumber: model.NewScaledNumberType(value),
+	}
+
+	return WriteKeyValue(e.LocalEntity, entity, e.scope.FailsafeLimitKeyName, data)
+}
+
+// return the failsafe duration minimum of a controllable system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no value is available
+//   - and others
+func (e *EnergyGuard) FailsafeDurationMinimum(entity spineapi.EntityRemoteInterface) (time.Duration, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	return KeyValueDuration(e.LocalEntity, entity, model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum)
+}
+
+// send a new failsafe duration minimum to a controllable system,
+// the duration has to be >= 2h and <= 24h
+func (e *EnergyGuard) WriteFailsafeDurationMinimum(entity spineapi.EntityRemoteInterface, duration time.Duration) (*model.MsgCounterType, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	if err := ValidateFailsafeDurationMinimum(duration); err != nil {
+		return nil, err
+	}
+
+	data := model.DeviceConfigurationKeyValueValueType{
+		Duration: model.NewDurationType(duration),
+	}
+
+	return WriteKeyValue(e.LocalEntity, entity, model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum, data)
+}
+
+// start sending heartbeats to all remote entities subscribed to the local heartbeat
+func (e *EnergyGuard) StartHeartbeat() error {
+	return e.LocalEntity.Device().HeartbeatManager().StartHeartbeat()
+}
+
+// stop sending heartbeats
+func (e *EnergyGuard) StopHeartbeat() {
+	e.LocalEntity.Device().HeartbeatManager().StopHeartbeat()
+}
+
+// return if the last heartbeat of the controllable system was received
+// within the heartbeat timeout
+func (e *EnergyGuard) IsHeartbeatWithinDuration(entity spineapi.EntityRemoteInterface) bool {
+	if !e.HasRemoteEntity(entity) {
+		return false
+	}
+
+	deviceDiagnosis, err := features.NewDeviceDiagnosis(e.LocalEntity, entity)
+	if err != nil {
+		return false
+	}
+
+	return deviceDiagnosis.IsHeartbeatWithinDuration(powerLimitHeartbeatTimeout)
+}
+
+// return the nominal maximum active power of a controllable system
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no value is available
+//   - and others
+func (e *EnergyGuard) PowerNominalMax(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity)
+	if err != nil {
+		return 0, err
+	}
+
+	characteristic, err := electricalConnection.GetCharacteristicForContextType(
+		model.ElectricalConnectionCharacteristicContextTypeEntity,
+		e.scope.NominalMaxCharacteristic,
+	)
+	if err != nil || characteristic.Value == nil {
+		return 0, api.ErrDataNotAvailable
+	}
+
+	return characteristic.Value.GetValue(), nil
+}
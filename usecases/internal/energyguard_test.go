@@ -0,0 +1,483 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var testEnergyGuardEvents = EnergyGuardEvents{
+	DataUpdateLimit:                   "DataUpdateLimit",
+	LimitAccepted:                     "LimitAccepted",
+	LimitRejected:                     "LimitRejected",
+	DataUpdateFailsafeLimit:           "DataUpdateFailsafeLimit",
+	DataUpdateFailsafeDurationMinimum: "DataUpdateFailsafeDurationMinimum",
+	DataUpdateNominalMax:              "DataUpdateNominalMax",
+	DataUpdateHeartbeat:               "DataUpdateHeartbeat",
+	HeartbeatTimeout:                  "HeartbeatTimeout",
+}
+
+func TestEnergyGuardSuite(t *testing.T) {
+	suite.Run(t, new(EnergyGuardSuite))
+}
+
+type EnergyGuardSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *EnergyGuard
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *EnergyGuardSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *EnergyGuardSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *EnergyGuardSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEVSE,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeLoadControl,
+				Functions: []model.FunctionType{
+					model.FunctionTypeLoadControlLimitDescriptionListData,
+					model.FunctionTypeLoadControlLimitListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+					model.FunctionTypeDeviceConfigurationKeyValueListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeDeviceDiagnosis,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceDiagnosisHeartbeatData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionCharacteristicListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewEnergyGuard(s.localEntity, PowerConsumptionLimitScope, testEnergyGuardEvents,
+		[]model.EntityTypeType{model.EntityTypeTypeEVSE}, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+func (s *EnergyGuardSuite) AfterTest(suiteName, testName string) {
+	s.sut.StopHeartbeat()
+}
+
+// announce the use case support of the remote entity
+func (s *EnergyGuardSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeControllableSystem,
+		model.UseCaseNameTypeLimitationOfPowerConsumption, true)
+	s.sut.HandleEvent(payload)
+}
+
+func (s *EnergyGuardSuite) Test_AddFeatures() {
+	feature := s.localEntity.FeatureOfTypeAndRole(model.FeatureTypeTypeDeviceDiagnosis, model.RoleTypeServer)
+	assert.NotNil(s.T(), feature)
+	assert.True(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeLoadControl,
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeDeviceDiagnosis,
+		model.FeatureTypeTypeElectricalConnection,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeEnergyGuard, model.UseCaseNameTypeLimitationOfPowerConsumption))
+}
+
+func (s *EnergyGuardSuite) Test_EntityConnected() {
+	s.connect()
+
+	assert.True(s.T(), s.sut.HasRemoteEntity(s.remoteEntity))
+	assert.True(s.T(), s.hasEvent(api.UseCaseSupportUpdate))
+	// subscriptions, bindings and requests got sent
+	assert.True(s.T(), s.writeHandler.Count() >= 10)
+}
+
+func (s *EnergyGuardSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitDescriptionListData,
+		&model.LoadControlLimitDescriptionListDataType{
+			LoadControlLimitDescriptionData: []model.LoadControlLimitDescriptionDataType{
+				{
+					LimitId:        util.Ptr(model.LoadControlLimitIdType(0)),
+					LimitType:      util.Ptr(model.LoadControlLimitTypeTypeSignDependentAbsValueLimit),
+					LimitCategory:  util.Ptr(model.LoadControlCategoryTypeObligation),
+					LimitDirection: util.Ptr(model.EnergyDirectionTypeConsume),
+					ScopeType:      util.Ptr(model.ScopeTypeTypeActivePowerLimit),
+				},
+			},
+		})
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the limit values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	payload = testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitListData,
+		&model.LoadControlLimitListDataType{
+			LoadControlLimitData: []model.LoadControlLimitDataType{
+				{
+					LimitId: util.Ptr(model.LoadControlLimitIdType(0)),
+					Value:   model.NewScaledNumberType(4200),
+				},
+			},
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(testEnergyGuardEvents.DataUpdateLimit))
+
+	payload = testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+		&model.DeviceConfigurationKeyValueDescriptionListDataType{
+			DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeConsumptionActivePowerLimit),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+				},
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+				},
+			},
+		})
+	count = s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the key values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	payload = testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData,
+		&model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						ScaledNumber: model.NewScaledNumberType(4200),
+					},
+				},
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						Duration: model.NewDurationType(time.Hour * 2),
+					},
+				},
+			},
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(testEnergyGuardEvents.DataUpdateFailsafeLimit))
+	assert.True(s.T(), s.hasEvent(testEnergyGuardEvents.DataUpdateFailsafeDurationMinimum))
+
+	payload = testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionCharacteristicListData,
+		&model.ElectricalConnectionCharacteristicListDataType{
+			ElectricalConnectionCharacteristicListData: []model.ElectricalConnectionCharacteristicDataType{
+				{
+					CharacteristicId:      util.Ptr(model.ElectricalConnectionCharaceteristicIdType(0)),
+					CharacteristicContext: util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+					CharacteristicType:    util.Ptr(model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax),
+					Value:                 model.NewScaledNumberType(11000),
+				},
+			},
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(testEnergyGuardEvents.DataUpdateNominalMax))
+
+	payload = testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceDiagnosis,
+		model.FunctionTypeDeviceDiagnosisHeartbeatData,
+		&model.DeviceDiagnosisHeartbeatDataType{
+			Timestamp: model.NewAbsoluteOrRelativeTimeTypeFromTime(time.Now()),
+		})
+	s.sut.HandleEvent(payload)
+	assert.True(s.T(), s.hasEvent(testEnergyGuardEvents.DataUpdateHeartbeat))
+}
+
+func (s *EnergyGuardSuite) Test_HeartbeatTimeout() {
+	s.sut.heartbeatMonitor = NewHeartbeatMonitor(time.Millisecond*50, s.sut.heartbeatTimeout)
+
+	s.connect()
+
+	assert.Eventually(s.T(), func() bool {
+		return s.hasEvent(testEnergyGuardEvents.HeartbeatTimeout)
+	}, time.Second, time.Millisecond*10)
+}
+
+func (s *EnergyGuardSuite) Test_Limit() {
+	limit := usecases.LoadLimit{
+		Duration: time.Hour,
+		IsActive: true,
+		Value:    4200,
+	}
+
+	_, err := s.sut.Limit(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+	_, err = s.sut.WriteLimit(s.remoteEntity, limit)
+	assert.NotNil(s.T(), err)
+
+	s.connect()
+
+	_, err = s.sut.Limit(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+	_, err = s.sut.WriteLimit(s.remoteEntity, limit)
+	assert.NotNil(s.T(), err)
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitDescriptionListData,
+		&model.LoadControlLimitDescriptionListDataType{
+			LoadControlLimitDescriptionData: []model.LoadControlLimitDescriptionDataType{
+				{
+					LimitId:        util.Ptr(model.LoadControlLimitIdType(0)),
+					LimitType:      util.Ptr(model.LoadControlLimitTypeTypeSignDependentAbsValueLimit),
+					LimitCategory:  util.Ptr(model.LoadControlCategoryTypeObligation),
+					LimitDirection: util.Ptr(model.EnergyDirectionTypeConsume),
+					ScopeType:      util.Ptr(model.ScopeTypeTypeActivePowerLimit),
+				},
+			},
+		})
+
+	_, err = s.sut.Limit(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	msgCounter, err := s.sut.WriteLimit(s.remoteEntity, limit)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	testhelper.SetRemoteResult(s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		*msgCounter, model.ErrorNumberTypeNoError)
+	assert.Eventually(s.T(), func() bool {
+		return s.hasEvent(testEnergyGuardEvents.LimitAccepted)
+	}, time.Second, time.Millisecond*10)
+
+	msgCounter, err = s.sut.WriteLimit(s.remoteEntity, limit)
+	assert.Nil(s.T(), err)
+	testhelper.SetRemoteResult(s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		*msgCounter, model.ErrorNumberTypeCommandRejected)
+	assert.Eventually(s.T(), func() bool {
+		return s.hasEvent(testEnergyGuardEvents.LimitRejected)
+	}, time.Second, time.Millisecond*10)
+
+	// the event is also reported if the caller waits for the result,
+	// which arrived before the wait started
+	s.mux.Lock()
+	s.events = nil
+	s.mux.Unlock()
+	msgCounter, err = s.sut.WriteLimit(s.remoteEntity, limit)
+	assert.Nil(s.T(), err)
+	testhelper.SetRemoteResult(s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		*msgCounter, model.ErrorNumberTypeNoError)
+	loadControl, err := features.NewLoadControl(s.localEntity, s.remoteEntity)
+	assert.Nil(s.T(), err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = loadControl.WaitForResult(ctx, msgCounter)
+	assert.Nil(s.T(), err)
+	assert.Eventually(s.T(), func() bool {
+		return s.hasEvent(testEnergyGuardEvents.LimitAccepted)
+	}, time.Second, time.Millisecond*10)
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitListData,
+		&model.LoadControlLimitListDataType{
+			LoadControlLimitData: []model.LoadControlLimitDataType{
+				{
+					LimitId:           util.Ptr(model.LoadControlLimitIdType(0)),
+					IsLimitChangeable: util.Ptr(true),
+					IsLimitActive:     util.Ptr(true),
+					Value:             model.NewScaledNumberType(4200),
+				},
+			},
+		})
+
+	data, err := s.sut.Limit(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, data.Value)
+	assert.True(s.T(), data.IsActive)
+	assert.True(s.T(), data.IsChangeable)
+	assert.Equal(s.T(), time.Duration(0), data.Duration)
+}
+
+func (s *EnergyGuardSuite) Test_Failsafe() {
+	_, err := s.sut.FailsafeLimit(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+	_, err = s.sut.WriteFailsafeLimit(s.remoteEntity, 4200)
+	assert.NotNil(s.T(), err)
+	_, err = s.sut.FailsafeDurationMinimum(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+	_, err = s.sut.WriteFailsafeDurationMinimum(s.remoteEntity, time.Hour*2)
+	assert.NotNil(s.T(), err)
+
+	s.connect()
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+		&model.DeviceConfigurationKeyValueDescriptionListDataType{
+			DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeConsumptionActivePowerLimit),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+				},
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypeFailsafeDurationMinimum),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeDuration),
+				},
+			},
+		})
+
+	_, err = s.sut.FailsafeLimit(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	msgCounter, err := s.sut.WriteFailsafeLimit(s.remoteEntity, 4200)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	_, err = s.sut.WriteFailsafeDurationMinimum(s.remoteEntity, time.Hour)
+	assert.NotNil(s.T(), err)
+	_, err = s.sut.WriteFailsafeDurationMinimum(s.remoteEntity, time.Hour*25)
+	assert.NotNil(s.T(), err)
+
+	msgCounter, err = s.sut.WriteFailsafeDurationMinimum(s.remoteEntity, time.Hour*2)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData,
+		&model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						ScaledNumber: model.NewScaledNumberType(4200),
+					},
+				},
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(1)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						Duration: model.NewDurationType(time.Hour * 2),
+					},
+				},
+			},
+		})
+
+	value, err := s.sut.FailsafeLimit(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, value)
+
+	duration, err := s.sut.FailsafeDurationMinimum(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), time.Hour*2, duration)
+}
+
+func (s *EnergyGuardSuite) Test_Heartbeat() {
+	s.sut.StopHeartbeat()
+	assert.False(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	err := s.sut.StartHeartbeat()
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), s.localEntity.Device().HeartbeatManager().IsHeartbeatRunning())
+
+	assert.False(s.T(), s.sut.IsHeartbeatWithinDuration(s.remoteEntity))
+
+	s.connect()
+
+	assert.False(s.T(), s.sut.IsHeartbeatWithinDuration(s.remoteEntity))
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceDiagnosis,
+		model.FunctionTypeDeviceDiagnosisHeartbeatData,
+		&model.DeviceDiagnosisHeartbeatDataType{
+			Timestamp: model.NewAbsoluteOrRelativeTimeTypeFromTime(time.Now().Add(-time.Minute)),
+		})
+	assert.True(s.T(), s.sut.IsHeartbeatWithinDuration(s.remoteEntity))
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceDiagnosis,
+		model.FunctionTypeDeviceDiagnosisHeartbeatData,
+		&model.DeviceDiagnosisHeartbeatDataType{
+			Timestamp: model.NewAbsoluteOrRelativeTimeTypeFromTime(time.Now().Add(-time.Minute * 3)),
+		})
+	assert.False(s.T(), s.sut.IsHeartbeatWithinDuration(s.remoteEntity))
+}
+
+func (s *EnergyGuardSuite) Test_PowerNominalMax() {
+	_, err := s.sut.PowerNominalMax(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	s.connect()
+
+	_, err = s.sut.PowerNominalMax(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionCharacteristicListData,
+		&model.ElectricalConnectionCharacteristicListDataType{
+			ElectricalConnectionCharacteristicListData: []model.ElectricalConnectionCharacteristicDataType{
+				{
+					CharacteristicId:      util.Ptr(model.ElectricalConnectionCharaceteristicIdType(0)),
+					CharacteristicContext: util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+					CharacteristicType:    util.Ptr(model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax),
+					Value:                 model.NewScaledNumberType(11000),
+				},
+			},
+		})
+
+	value, err := s.sut.PowerNominalMax(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 11000.0, value)
+}
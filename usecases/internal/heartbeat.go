@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	spineapi "github.com/enbility/spine-go/api"
+)
+
+// monitors the heartbeats of remote entities
+//
+// the callback is invoked once no heartbeat of a monitored remote entity
+// was received within the timeout
+type HeartbeatMonitor struct {
+	timeout  time.Duration
+	callback func(entity spineapi.EntityRemoteInterface)
+
+	timers map[spineapi.EntityRemoteInterface]*time.Timer
+
+	mux sync.Mutex
+}
+
+// creates a new heartbeat monitor
+func NewHeartbeatMonitor(timeout time.Duration, callback func(entity spineapi.EntityRemoteInterface)) *HeartbeatMonitor {
+	return &HeartbeatMonitor{
+		timeout:  timeout,
+		callback: callback,
+		timers:   make(map[spineapi.EntityRemoteInterface]*time.Timer),
+	}
+}
+
+// start or restart waiting for the next heartbeat of a remote entity
+//
+// has to be invoked for every received heartbeat
+func (h *HeartbeatMonitor) Heartbeat(entity spineapi.EntityRemoteInterface) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if timer, ok := h.timers[entity]; ok {
+		timer.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(h.timeout, func() {
+		h.mux.Lock()
+		// ignore timers which got replaced or stopped in the meantime
+		if current, ok := h.timers[entity]; !ok || current != timer {
+			h.mux.Unlock()
+			return
+		}
+		delete(h.timers, entity)
+		h.mux.Unlock()
+
+		if h.callback != nil {
+			h.callback(entity)
+		}
+	})
+	h.timers[entity] = timer
+}
+
+// stop monitoring the heartbeats of a remote entity
+func (h *HeartbeatMonitor) Stop(entity spineapi.EntityRemoteInterface) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if timer, ok := h.timers[entity]; ok {
+		timer.Stop()
+		delete(h.timers, entity)
+	}
+}
+
+// return if the heartbeats of a remote entity are monitored and
+// the last heartbeat was received within the timeout
+func (h *HeartbeatMonitor) IsAlive(entity spineapi.EntityRemoteInterface) bool {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	_, ok := h.timers[entity]
+	return ok
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeatMonitor(t *testing.T) {
+	_, remoteEntity := testhelper.SetupEntities(t, &testhelper.WriteMessageHandler{},
+		model.EntityTypeTypeCEM, model.EntityTypeTypeEVSE, nil)
+
+	var mux sync.Mutex
+	var timeouts []spineapi.EntityRemoteInterface
+	monitor := NewHeartbeatMonitor(time.Millisecond*50, func(entity spineapi.EntityRemoteInterface) {
+		mux.Lock()
+		defer mux.Unlock()
+		timeouts = append(timeouts, entity)
+	})
+	count := func() int {
+		mux.Lock()
+		defer mux.Unlock()
+		return len(timeouts)
+	}
+
+	assert.False(t, monitor.IsAlive(remoteEntity))
+
+	monitor.Heartbeat(remoteEntity)
+	assert.True(t, monitor.IsAlive(remoteEntity))
+
+	// heartbeats within the timeout keep the entity alive
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Millisecond * 25)
+		monitor.Heartbeat(remoteEntity)
+	}
+	assert.Equal(t, 0, count())
+
+	time.Sleep(time.Millisecond * 100)
+	assert.Equal(t, 1, count())
+	assert.False(t, monitor.IsAlive(remoteEntity))
+
+	// stopped monitoring does not report a timeout
+	monitor.Heartbeat(remoteEntity)
+	monitor.Stop(remoteEntity)
+	assert.False(t, monitor.IsAlive(remoteEntity))
+
+	time.Sleep(time.Millisecond * 100)
+	assert.Equal(t, 1, count())
+}
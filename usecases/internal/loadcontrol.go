@@ -0,0 +1,387 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the filter describing a load control limit
+type LimitFilter struct {
+	LimitType model.LoadControlLimitTypeType
+	Category  model.LoadControlCategoryType
+	Direction model.EnergyDirectionType
+	Scope     model.ScopeTypeType
+}
+
+// return the limit description matching the filter
+func limitDescription(loadControl *features.LoadControl, filter LimitFilter) (*model.LoadControlLimitDescriptionDataType, error) {
+	descriptions, err := loadControl.GetLimitDescriptionsForCategoryTypeDirectionScope(
+		filter.LimitType, filter.Category, filter.Direction, filter.Scope)
+	if err != nil {
+		return nil, err
+	}
+	if len(descriptions) == 0 || descriptions[0].LimitId == nil {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	return &descriptions[0], nil
+}
+
+// return the load limit of a remote entity matching the filter
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no matching limit description is available
+//   - ErrDataNotAvailable if no value for the limit is available
+//   - and others
+func LoadLimit(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	filter LimitFilter,
+) (usecases.LoadLimit, error) {
+	loadControl, err := features.NewLoadControl(localEntity, remoteEntity)
+	if err != nil {
+		return usecases.LoadLimit{}, err
+	}
+
+	description, err := limitDescription(loadControl, filter)
+	if err != nil {
+		return usecases.LoadLimit{}, api.ErrMetadataNotAvailable
+	}
+
+	value, err := loadControl.GetLimitValueForLimitId(*description.LimitId)
+	if err != nil || value == nil || value.Value == nil {
+		return usecases.LoadLimit{}, api.ErrDataNotAvailable
+	}
+
+	return LoadLimitOfData(*value), nil
+}
+
+// return the load limit of the limit data
+func LoadLimitOfData(data model.LoadControlLimitDataType) usecases.LoadLimit {
+	limit := usecases.LoadLimit{
+		Value: data.Value.GetValue(),
+	}
+
+	if data.IsLimitChangeable != nil {
+		limit.IsChangeable = *data.IsLimitChangeable
+	}
+	if data.IsLimitActive != nil {
+		limit.IsActive = *data.IsLimitActive
+	}
+	if data.TimePeriod != nil && data.TimePeriod.EndTime != nil {
+		if endTime, err := data.TimePeriod.EndTime.GetTime(); err == nil {
+			limit.Duration = time.Until(endTime)
+		}
+	}
+
+	return limit
+}
+
+// return the limit data of a load limit provided by a local server feature
+//
+// the end time is absolute, as relative durations are relative to the time
+// the data is read
+func LocalLimitData(limitId model.LoadControlLimitIdType, limit usecases.LoadLimit) model.LoadControlLimitDataType {
+	data := model.LoadControlLimitDataType{
+		LimitId:           util.Ptr(limitId),
+		IsLimitChangeable: util.Ptr(limit.IsChangeable),
+		IsLimitActive:     util.Ptr(limit.IsActive),
+		Value:             model.NewScaledNumberType(limit.Value),
+	}
+	if limit.Duration > 0 {
+		data.TimePeriod = &model.TimePeriodType{
+			EndTime: model.NewAbsoluteOrRelativeTimeTypeFromTime(time.Now().Add(limit.Duration)),
+		}
+	}
+
+	return data
+}
+
+// write the load limit of a remote entity matching the filter
+//
+// the limit is written partially, the other limits of the remote entity are kept
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no matching limit description is available
+//   - ErrNotSupported if the remote entity does not allow to change the limit
+//   - and others
+func WriteLoadLimit(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	filter LimitFilter,
+	limit usecases.LoadLimit,
+) (*model.MsgCounterType, error) {
+	loadControl, err := features.NewLoadControl(localEntity, remoteEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	description, err := limitDescription(loadControl, filter)
+	if err != nil {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	if value, err := loadControl.GetLimitValueForLimitId(*description.LimitId); err == nil &&
+		value.IsLimitChangeable != nil && !*value.IsLimitChangeable {
+		return nil, api.ErrNotSupported
+	}
+
+	data := model.LoadControlLimitDataType{
+		LimitId:       description.LimitId,
+		IsLimitActive: util.Ptr(limit.IsActive),
+		Value:         model.NewScaledNumberType(limit.Value),
+	}
+	if limit.Duration > 0 {
+		data.TimePeriod = &model.TimePeriodType{
+			EndTime: model.NewAbsoluteOrRelativeTimeTypeFromDuration(limit.Duration),
+		}
+	}
+
+	return loadControl.WriteLimitValuesPartial([]model.LoadControlLimitDataType{data})
+}
+
+// the phases of phase specific limits
+var limitPhases = []model.ElectricalConnectionPhaseNameType{
+	model.ElectricalConnectionPhaseNameTypeA,
+	model.ElectricalConnectionPhaseNameTypeB,
+	model.ElectricalConnectionPhaseNameTypeC,
+}
+
+// a phase specific limit and the electrical connection parameter
+// of the measurement it refers to
+type phaseLimit struct {
+	phase       model.ElectricalConnectionPhaseNameType
+	limitId     model.LoadControlLimitIdType
+	parameterId model.ElectricalConnectionParameterIdType
+}
+
+// return the phase specific limits matching the filter, ordered by phase
+//
+// the phase of a limit is the measured phase of the electrical connection
+// parameter referring to the same measurement as the limit description
+func phaseLimitDescriptions(
+	loadControl *features.LoadControl,
+	electricalConnection *features.ElectricalConnection,
+	filter LimitFilter,
+) ([]phaseLimit, error) {
+	descriptions, err := loadControl.GetLimitDescriptionsForCategoryTypeDirectionScope(
+		filter.LimitType, filter.Category, filter.Direction, filter.Scope)
+	if err != nil {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	var result []phaseLimit
+	for _, phase := range limitPhases {
+		for _, description := range descriptions {
+			if description.LimitId == nil || description.MeasurementId == nil {
+				continue
+			}
+
+			param, err := electricalConnection.GetParameterDescriptionForMeasurementId(*description.MeasurementId)
+			if err != nil || param.ParameterId == nil ||
+				param.AcMeasuredPhases == nil || *param.AcMeasuredPhases != phase {
+				continue
+			}
+
+			result = append(result, phaseLimit{
+				phase:       phase,
+				limitId:     *description.LimitId,
+				parameterId: *param.ParameterId,
+			})
+			break
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, api.ErrMetadataNotAvailable
+	}
+
+	return result, nil
+}
+
+// return the phase specific limits of a remote entity matching the filter
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no matching limit or parameter description is available
+//   - ErrDataNotAvailable if no value for any of the limits is available
+//   - and others
+func PhaseLimits(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	filter LimitFilter,
+) ([]usecases.LoadLimitsPhase, error) {
+	loadControl, err := features.NewLoadControl(localEntity, remoteEntity)
+	if err != nil {
+		return nil, err
+	}
+	electricalConnection, err := features.NewElectricalConnection(localEntity, remoteEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := phaseLimitDescriptions(loadControl, electricalConnection, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []usecases.LoadLimitsPhase
+	for _, item := range limits {
+		value, err := loadControl.GetLimitValueForLimitId(item.limitId)
+		if err != nil || value == nil || value.Value == nil {
+			continue
+		}
+
+		limit := usecases.LoadLimitsPhase{
+			Phase: item.phase,
+			Value: value.Value.GetValue(),
+		}
+		if value.IsLimitChangeable != nil {
+			limit.IsChangeable = *value.IsLimitChangeable
+		}
+		if value.IsLimitActive != nil {
+			limit.IsActive = *value.IsLimitActive
+		}
+
+		result = append(result, limit)
+	}
+
+	if len(result) == 0 {
+		return nil, api.ErrDataNotAvailable
+	}
+
+	return result, nil
+}
+
+// return the permitted minimum, maximum and pause values of the phase
+// specific limits of a remote entity matching the filter, ordered by phase
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no matching limit or parameter description is available
+//   - ErrDataNotAvailable if no permitted values are available
+//   - and others
+func PhaseLimitRanges(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	filter LimitFilter,
+) ([]float64, []float64, []float64, error) {
+	loadControl, err := features.NewLoadControl(localEntity, remoteEntity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	electricalConnection, err := features.NewElectricalConnection(localEntity, remoteEntity)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	limits, err := phaseLimitDescriptions(loadControl, electricalConnection, filter)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var minimums, maximums, pauses []float64
+	for _, item := range limits {
+		if _, err := electricalConnection.GetPermittedValueSetForParameterId(item.parameterId); err != nil {
+			return nil, nil, nil, api.ErrDataNotAvailable
+		}
+
+		minimum, maximum, pause, err := electricalConnection.GetLimitsForParameterId(item.parameterId)
+		if err != nil {
+			return nil, nil, nil, api.ErrDataNotAvailable
+		}
+
+		minimums = append(minimums, minimum)
+		maximums = append(maximums, maximum)
+		pauses = append(pauses, pause)
+	}
+
+	return minimums, maximums, pauses, nil
+}
+
+// write phase specific limits of a remote entity matching the filter
+//
+// the values are adjusted to be within the permitted values of the
+// phases, limits of phases not provided or not supported by the remote
+// entity are not changed, as the limits are written partially
+//
+// resultCB is invoked with the result of the write and may be nil. It is
+// registered with the feature helper used for sending, which receives the
+// results from before the message is sent, so a fast result is reported as well
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no matching limit or parameter description is available
+//   - ErrNotSupported if the remote entity does not allow to change any of the limits
+//   - ErrMissingData if none of the limits is for a phase supported by the remote entity
+//   - and others
+func WritePhaseLimits(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	filter LimitFilter,
+	limits []usecases.LoadLimitsPhase,
+	resultCB func(result model.ResultDataType),
+) (*model.MsgCounterType, error) {
+	loadControl, err := features.NewLoadControl(localEntity, remoteEntity)
+	if err != nil {
+		return nil, err
+	}
+	electricalConnection, err := features.NewElectricalConnection(localEntity, remoteEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions, err := phaseLimitDescriptions(loadControl, electricalConnection, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []model.LoadControlLimitDataType
+	var notChangeable bool
+	for _, description := range descriptions {
+		for _, limit := range limits {
+			if limit.Phase != description.phase {
+				continue
+			}
+
+			if value, err := loadControl.GetLimitValueForLimitId(description.limitId); err == nil &&
+				value.IsLimitChangeable != nil && !*value.IsLimitChangeable {
+				notChangeable = true
+				break
+			}
+
+			value := electricalConnection.AdjustValueToBeWithinPermittedValuesForParameter(limit.Value, description.parameterId)
+			data = append(data, model.LoadControlLimitDataType{
+				LimitId:       util.Ptr(description.limitId),
+				IsLimitActive: util.Ptr(limit.IsActive),
+				Value:         model.NewScaledNumberType(value),
+			})
+			break
+		}
+	}
+
+	if len(data) == 0 {
+		if notChangeable {
+			return nil, api.ErrNotSupported
+		}
+		return nil, api.ErrMissingData
+	}
+
+	msgCounter, err := loadControl.WriteLimitValuesPartial(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if resultCB != nil && msgCounter != nil {
+		loadControl.AddResultCallback(*msgCounter, func(msg spineapi.ResultMessage) {
+			if msg.Result != nil {
+				resultCB(*msg.Result)
+			}
+		})
+	}
+
+	return msgCounter, nil
+}
@@ -0,0 +1,363 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestLoadControlSuite(t *testing.T) {
+	suite.Run(t, new(LoadControlSuite))
+}
+
+type LoadControlSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	filter LimitFilter
+}
+
+func (s *LoadControlSuite) BeforeTest(suiteName, testName string) {
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeEVSE,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeLoadControl,
+				Functions: []model.FunctionType{
+					model.FunctionTypeLoadControlLimitDescriptionListData,
+					model.FunctionTypeLoadControlLimitListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+					model.FunctionTypeElectricalConnectionPermittedValueSetListData,
+				},
+			},
+		},
+	)
+	s.localEntity.GetOrAddFeature(model.FeatureTypeTypeLoadControl, model.RoleTypeClient)
+	s.localEntity.GetOrAddFeature(model.FeatureTypeTypeElectricalConnection, model.RoleTypeClient)
+
+	s.filter = LimitFilter{
+		LimitType: model.LoadControlLimitTypeTypeSignDependentAbsValueLimit,
+		Category:  model.LoadControlCategoryTypeObligation,
+		Direction: model.EnergyDirectionTypeConsume,
+		Scope:     model.ScopeTypeTypeActivePowerLimit,
+	}
+}
+
+func (s *LoadControlSuite) addDescription() {
+	descData := &model.LoadControlLimitDescriptionListDataType{
+		LoadControlLimitDescriptionData: []model.LoadControlLimitDescriptionDataType{
+			{
+				LimitId:        util.Ptr(model.LoadControlLimitIdType(0)),
+				LimitType:      util.Ptr(s.filter.LimitType),
+				LimitCategory:  util.Ptr(s.filter.Category),
+				LimitDirection: util.Ptr(s.filter.Direction),
+				ScopeType:      util.Ptr(s.filter.Scope),
+			},
+		},
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitDescriptionListData, descData)
+}
+
+func (s *LoadControlSuite) addData(changeable bool) {
+	data := &model.LoadControlLimitListDataType{
+		LoadControlLimitData: []model.LoadControlLimitDataType{
+			{
+				LimitId:           util.Ptr(model.LoadControlLimitIdType(0)),
+				IsLimitChangeable: util.Ptr(changeable),
+				IsLimitActive:     util.Ptr(true),
+				Value:             model.NewScaledNumberType(4200),
+				TimePeriod: &model.TimePeriodType{
+					EndTime: model.NewAbsoluteOrRelativeTimeTypeFromDuration(time.Hour),
+				},
+			},
+		},
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitListData, data)
+}
+
+func (s *LoadControlSuite) Test_LoadLimit() {
+	_, err := LoadLimit(s.localEntity, nil, s.filter)
+	assert.NotNil(s.T(), err)
+
+	_, err = LoadLimit(s.localEntity, s.remoteEntity, s.filter)
+	assert.NotNil(s.T(), err)
+
+	s.addDescription()
+
+	_, err = LoadLimit(s.localEntity, s.remoteEntity, s.filter)
+	assert.NotNil(s.T(), err)
+
+	s.addData(true)
+
+	limit, err := LoadLimit(s.localEntity, s.remoteEntity, s.filter)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 4200.0, limit.Value)
+	assert.True(s.T(), limit.IsActive)
+	assert.True(s.T(), limit.IsChangeable)
+	assert.True(s.T(), limit.Duration > time.Minute*59 && limit.Duration <= time.Hour)
+
+	filter := s.filter
+	filter.Direction = model.EnergyDirectionTypeProduce
+	_, err = LoadLimit(s.localEntity, s.remoteEntity, filter)
+	assert.NotNil(s.T(), err)
+}
+
+func (s *LoadControlSuite) Test_LocalLimitData() {
+	data := LocalLimitData(1, usecases.LoadLimit{
+		IsChangeable: true,
+		IsActive:     true,
+		Value:        4200,
+		Duration:     time.Hour,
+	})
+	assert.Equal(s.T(), model.LoadControlLimitIdType(1), *data.LimitId)
+
+	// the end time is absolute
+	_, err := data.TimePeriod.EndTime.GetDateTimeType().GetTime()
+	assert.Nil(s.T(), err)
+
+	limit := LoadLimitOfData(data)
+	assert.Equal(s.T(), 4200.0, limit.Value)
+	assert.True(s.T(), limit.IsActive)
+	assert.True(s.T(), limit.IsChangeable)
+	assert.True(s.T(), limit.Duration > time.Minute*59 && limit.Duration <= time.Hour)
+
+	data = LocalLimitData(1, usecases.LoadLimit{})
+	assert.Nil(s.T(), data.TimePeriod)
+}
+
+func (s *LoadControlSuite) Test_WriteLoadLimit() {
+	limit := usecases.LoadLimit{
+		Duration: time.Hour,
+		IsActive: true,
+		Value:    5000,
+	}
+
+	_, err := WriteLoadLimit(s.localEntity, nil, s.filter, limit)
+	assert.NotNil(s.T(), err)
+
+	_, err = WriteLoadLimit(s.localEntity, s.remoteEntity, s.filter, limit)
+	assert.NotNil(s.T(), err)
+
+	s.addDescription()
+
+	msgCounter, err := WriteLoadLimit(s.localEntity, s.remoteEntity, s.filter, limit)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+
+	datagram := s.writeHandler.LastDatagram()
+	assert.NotNil(s.T(), datagram)
+	data := datagram.Payload.Cmd[0].LoadControlLimitListData
+	assert.NotNil(s.T(), data)
+	assert.Equal(s.T(), 5000.0, data.LoadControlLimitData[0].Value.GetValue())
+	assert.NotNil(s.T(), data.LoadControlLimitData[0].TimePeriod)
+	// the other limits of the remote entity are kept
+	assert.Equal(s.T(), 1, len(datagram.Payload.Cmd[0].Filter))
+	assert.NotNil(s.T(), datagram.Payload.Cmd[0].Filter[0].CmdControl.Partial)
+
+	s.addData(false)
+
+	_, err = WriteLoadLimit(s.localEntity, s.remoteEntity, s.filter, limit)
+	assert.NotNil(s.T(), err)
+}
+
+var phaseFilter = LimitFilter{
+	LimitType: model.LoadControlLimitTypeTypeMaxValueLimit,
+	Category:  model.LoadControlCategoryTypeObligation,
+	Direction: model.EnergyDirectionTypeConsume,
+	Scope:     model.ScopeTypeTypeOverloadProtection,
+}
+
+// add phase specific limit descriptions for the phases, the limit, measurement
+// and parameter ids are the index of the phase
+func (s *LoadControlSuite) addPhaseDescriptions(phases []model.ElectricalConnectionPhaseNameType) {
+	descData := &model.LoadControlLimitDescriptionListDataType{}
+	paramData := &model.ElectricalConnectionParameterDescriptionListDataType{}
+	for id, phase := range phases {
+		descData.LoadControlLimitDescriptionData = append(descData.LoadControlLimitDescriptionData,
+			model.LoadControlLimitDescriptionDataType{
+				LimitId:        util.Ptr(model.LoadControlLimitIdType(id)),
+				LimitType:      util.Ptr(phaseFilter.LimitType),
+				LimitCategory:  util.Ptr(phaseFilter.Category),
+				LimitDirection: util.Ptr(phaseFilter.Direction),
+				MeasurementId:  util.Ptr(model.MeasurementIdType(id)),
+				ScopeType:      util.Ptr(phaseFilter.Scope),
+			})
+		paramData.ElectricalConnectionParameterDescriptionData = append(paramData.ElectricalConnectionParameterDescriptionData,
+			model.ElectricalConnectionParameterDescriptionDataType{
+				ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				MeasurementId:          util.Ptr(model.MeasurementIdType(id)),
+				AcMeasuredPhases:       util.Ptr(phase),
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitDescriptionListData, descData)
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData, paramData)
+}
+
+// add the limit values for the first count phases
+func (s *LoadControlSuite) addPhaseData(count int, changeable bool) {
+	data := &model.LoadControlLimitListDataType{}
+	for id := 0; id < count; id++ {
+		data.LoadControlLimitData = append(data.LoadControlLimitData, model.LoadControlLimitDataType{
+			LimitId:           util.Ptr(model.LoadControlLimitIdType(id)),
+			IsLimitChangeable: util.Ptr(changeable),
+			IsLimitActive:     util.Ptr(true),
+			Value:             model.NewScaledNumberType(float64(16 - id)),
+		})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeLoadControl,
+		model.FunctionTypeLoadControlLimitListData, data)
+}
+
+// add permitted values of 6 to 16 A with a pause value of 0 A for the first count parameters
+func (s *LoadControlSuite) addPermittedValues(count int) {
+	data := &model.ElectricalConnectionPermittedValueSetListDataType{}
+	for id := 0; id < count; id++ {
+		data.ElectricalConnectionPermittedValueSetData = append(data.ElectricalConnectionPermittedValueSetData,
+			model.ElectricalConnectionPermittedValueSetDataType{
+				ElectricalConnectionId: util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				PermittedValueSet: []model.ScaledNumberSetType{
+					{
+						Value: []model.ScaledNumberType{*model.NewScaledNumberType(0)},
+						Range: []model.ScaledNumberRangeType{
+							{
+								Min: model.NewScaledNumberType(6),
+								Max: model.NewScaledNumberType(16),
+							},
+						},
+					},
+				},
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionPermittedValueSetListData, data)
+}
+
+func (s *LoadControlSuite) Test_PhaseLimits() {
+	_, err := PhaseLimits(s.localEntity, nil, phaseFilter)
+	assert.NotNil(s.T(), err)
+
+	_, err = PhaseLimits(s.localEntity, s.remoteEntity, phaseFilter)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	// descriptions are ordered by phase, independent of their ids
+	s.addPhaseDescriptions([]model.ElectricalConnectionPhaseNameType{
+		model.ElectricalConnectionPhaseNameTypeB,
+		model.ElectricalConnectionPhaseNameTypeA,
+	})
+
+	_, err = PhaseLimits(s.localEntity, s.remoteEntity, phaseFilter)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.addPhaseData(2, true)
+
+	limits, err := PhaseLimits(s.localEntity, s.remoteEntity, phaseFilter)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []usecases.LoadLimitsPhase{
+		{Phase: model.ElectricalConnectionPhaseNameTypeA, IsChangeable: true, IsActive: true, Value: 15},
+		{Phase: model.ElectricalConnectionPhaseNameTypeB, IsChangeable: true, IsActive: true, Value: 16},
+	}, limits)
+}
+
+func (s *LoadControlSuite) Test_PhaseLimitRanges() {
+	_, _, _, err := PhaseLimitRanges(s.localEntity, s.remoteEntity, phaseFilter)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.addPhaseDescriptions(limitPhases)
+
+	_, _, _, err = PhaseLimitRanges(s.localEntity, s.remoteEntity, phaseFilter)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.addPermittedValues(3)
+
+	minimums, maximums, pauses, err := PhaseLimitRanges(s.localEntity, s.remoteEntity, phaseFilter)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{6, 6, 6}, minimums)
+	assert.Equal(s.T(), []float64{16, 16, 16}, maximums)
+	assert.Equal(s.T(), []float64{0, 0, 0}, pauses)
+}
+
+func (s *LoadControlSuite) Test_WritePhaseLimits() {
+	limits := []usecases.LoadLimitsPhase{
+		{Phase: model.ElectricalConnectionPhaseNameTypeA, IsActive: true, Value: 20},
+		{Phase: model.ElectricalConnectionPhaseNameTypeB, IsActive: true, Value: 3},
+		{Phase: model.ElectricalConnectionPhaseNameTypeC, IsActive: true, Value: 10},
+	}
+
+	_, err := WritePhaseLimits(s.localEntity, nil, phaseFilter, limits, nil)
+	assert.NotNil(s.T(), err)
+
+	_, err = WritePhaseLimits(s.localEntity, s.remoteEntity, phaseFilter, limits, nil)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.addPhaseDescriptions(limitPhases[:2])
+	s.addPermittedValues(2)
+
+	_, err = WritePhaseLimits(s.localEntity, s.remoteEntity, phaseFilter, nil, nil)
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	// only the unsupported phase C is provided
+	_, err = WritePhaseLimits(s.localEntity, s.remoteEntity, phaseFilter, limits[2:], nil)
+	assert.Equal(s.T(), api.ErrMissingData, err)
+
+	// the result arrives before the write returned
+	s.writeHandler.SetOnSend(func(datagram model.DatagramType) {
+		testhelper.SetRemoteResult(s.localEntity, s.remoteEntity, model.FeatureTypeTypeLoadControl,
+			*datagram.Header.MsgCounter, model.ErrorNumberTypeNoError)
+	})
+	resultCh := make(chan model.ResultDataType, 1)
+	msgCounter, err := WritePhaseLimits(s.localEntity, s.remoteEntity, phaseFilter, limits, func(result model.ResultDataType) {
+		resultCh <- result
+	})
+	s.writeHandler.SetOnSend(nil)
+	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), msgCounter)
+	select {
+	case result := <-resultCh:
+		assert.Equal(s.T(), model.ErrorNumberTypeNoError, *result.ErrorNumber)
+	case <-time.After(time.Second):
+		assert.Fail(s.T(), "result callback not invoked")
+	}
+
+	// the values are clamped and the unsupported phase C is not written
+	datagram := s.writeHandler.LastDatagram()
+	assert.NotNil(s.T(), datagram)
+	data := datagram.Payload.Cmd[0].LoadControlLimitListData
+	assert.NotNil(s.T(), data)
+	assert.Equal(s.T(), 2, len(data.LoadControlLimitData))
+	assert.Equal(s.T(), 1, len(datagram.Payload.Cmd[0].Filter))
+	assert.NotNil(s.T(), datagram.Payload.Cmd[0].Filter[0].CmdControl.Partial)
+	assert.Equal(s.T(), 16.0, data.LoadControlLimitData[0].Value.GetValue())
+	assert.Equal(s.T(), 0.0, data.LoadControlLimitData[1].Value.GetValue())
+
+	s.addPhaseData(2, false)
+
+	_, err = WritePhaseLimits(s.localEntity, s.remoteEntity, phaseFilter, limits, nil)
+	assert.Equal(s.T(), api.ErrNotSupported, err)
+}
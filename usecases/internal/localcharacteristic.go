@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// add the electrical connection server feature providing the characteristics to the local entity
+//
+// an electrical connection is described for the characteristics, if none is described yet
+func AddLocalCharacteristics(localEntity spineapi.EntityLocalInterface) error {
+	electricalConnection, err := features.NewElectricalConnectionServer(localEntity)
+	if err != nil {
+		return err
+	}
+
+	if len(electricalConnection.GetDescriptions()) == 0 {
+		electricalConnection.AddDescription(model.ElectricalConnectionDescriptionDataType{})
+	}
+
+	return nil
+}
+
+// return the value of an entity characteristic of the local electrical connection server feature
+//
+// possible errors:
+//   - ErrDataNotAvailable if the characteristic is not set
+func LocalCharacteristic(
+	localEntity spineapi.EntityLocalInterface,
+	characteristicType model.ElectricalConnectionCharacteristicTypeType,
+) (float64, error) {
+	electricalConnection, err := localElectricalConnection(localEntity)
+	if err != nil {
+		return 0, api.ErrDataNotAvailable
+	}
+
+	characteristic, err := electricalConnection.GetCharacteristicForContextType(
+		model.ElectricalConnectionCharacteristicContextTypeEntity, characteristicType)
+	if err != nil || characteristic.Value == nil {
+		return 0, api.ErrDataNotAvailable
+	}
+
+	return characteristic.Value.GetValue(), nil
+}
+
+// set the value of an entity characteristic of the local electrical connection server feature
+//
+// the characteristic is added to the first described electrical connection if it does not exist yet
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the characteristics were not added to the local entity
+func SetLocalCharacteristic(
+	localEntity spineapi.EntityLocalInterface,
+	characteristicType model.ElectricalConnectionCharacteristicTypeType,
+	value float64,
+) error {
+	electricalConnection, err := localElectricalConnection(localEntity)
+	if err != nil {
+		return err
+	}
+
+	characteristic, err := electricalConnection.GetCharacteristicForContextType(
+		model.ElectricalConnectionCharacteristicContextTypeEntity, characteristicType)
+	if err == nil {
+		characteristic.Value = model.NewScaledNumberType(value)
+		return electricalConnection.UpdateCharacteristics(*characteristic)
+	}
+
+	descriptions := electricalConnection.GetDescriptions()
+	if len(descriptions) == 0 {
+		return api.ErrFunctionNotSupported
+	}
+
+	_, err = electricalConnection.AddCharacteristic(model.ElectricalConnectionCharacteristicDataType{
+		ElectricalConnectionId: descriptions[0].ElectricalConnectionId,
+		ParameterId:            util.Ptr(model.ElectricalConnectionParameterIdType(0)),
+		CharacteristicContext:  util.Ptr(model.ElectricalConnectionCharacteristicContextTypeEntity),
+		CharacteristicType:     util.Ptr(characteristicType),
+		Value:                  model.NewScaledNumberType(value),
+		Unit:                   util.Ptr(model.UnitOfMeasurementTypeW),
+	})
+
+	return err
+}
+
+// return the electrical connection server helper of the local entity
+//
+// possible errors:
+//   - ErrFunctionNotSupported if the characteristics were not added to the local entity
+func localElectricalConnection(localEntity spineapi.EntityLocalInterface) (*features.ElectricalConnectionServer, error) {
+	// the helper adds a missing feature, which is only done by AddLocalCharacteristics
+	if localEntity.FeatureOfTypeAndRole(model.FeatureTypeTypeElectricalConnection, model.RoleTypeServer) == nil {
+		return nil, api.ErrFunctionNotSupported
+	}
+
+	electricalConnection, err := features.NewElectricalConnectionServer(localEntity)
+	if err != nil {
+		return nil, api.ErrFunctionNotSupported
+	}
+
+	return electricalConnection, nil
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalCharacteristic(t *testing.T) {
+	localEntity, _ := testhelper.SetupEntities(t, &testhelper.WriteMessageHandler{},
+		model.EntityTypeTypeEVSE, model.EntityTypeTypeCEM, nil)
+
+	consumption := model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax
+	production := model.ElectricalConnectionCharacteristicTypeTypePowerProductionNominalMax
+
+	_, err := LocalCharacteristic(localEntity, consumption)
+	assert.NotNil(t, err)
+	err = SetLocalCharacteristic(localEntity, consumption, 11000)
+	assert.NotNil(t, err)
+
+	assert.Nil(t, AddLocalCharacteristics(localEntity))
+	// adding them again keeps the described electrical connection
+	assert.Nil(t, AddLocalCharacteristics(localEntity))
+
+	_, err = LocalCharacteristic(localEntity, consumption)
+	assert.NotNil(t, err)
+
+	err = SetLocalCharacteristic(localEntity, consumption, 11000)
+	assert.Nil(t, err)
+	err = SetLocalCharacteristic(localEntity, production, 5000)
+	assert.Nil(t, err)
+	err = SetLocalCharacteristic(localEntity, consumption, 22000)
+	assert.Nil(t, err)
+
+	value, err := LocalCharacteristic(localEntity, consumption)
+	assert.Nil(t, err)
+	assert.Equal(t, 22000.0, value)
+
+	value, err = LocalCharacteristic(localEntity, production)
+	assert.Nil(t, err)
+	assert.Equal(t, 5000.0, value)
+
+	// the feature is combined with the server feature helper
+	electricalConnection, err := features.NewElectricalConnectionServer(localEntity)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(electricalConnection.GetDescriptions()))
+	characteristic, err := electricalConnection.GetCharacteristicForContextType(
+		model.ElectricalConnectionCharacteristicContextTypeEntity, consumption)
+	assert.Nil(t, err)
+	assert.Equal(t, 22000.0, characteristic.Value.GetValue())
+}
+
+func TestLocalCharacteristic_PlainFeature(t *testing.T) {
+	localEntity, _ := testhelper.SetupEntities(t, &testhelper.WriteMessageHandler{},
+		model.EntityTypeTypeEVSE, model.EntityTypeTypeCEM, nil)
+
+	// a feature added without the server feature helper is not used
+	localEntity.GetOrAddFeature(model.FeatureTypeTypeElectricalConnection, model.RoleTypeServer)
+	assert.NotNil(t, AddLocalCharacteristics(localEntity))
+
+	err := SetLocalCharacteristic(localEntity,
+		model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax, 11000)
+	assert.Equal(t, api.ErrFunctionNotSupported, err)
+}
@@ -0,0 +1,237 @@
+package internal
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the filter describing a measurement
+type MeasurementFilter struct {
+	MeasurementType model.MeasurementTypeType
+
+	// empty if the commodity of the measurement is ignored, e.g. for distances
+	CommodityType model.CommodityTypeType
+
+	ScopeType model.ScopeTypeType
+}
+
+// return true if the measurement description matches the filter
+func (f MeasurementFilter) matches(description model.MeasurementDescriptionDataType) bool {
+	return description.MeasurementId != nil &&
+		description.MeasurementType != nil && *description.MeasurementType == f.MeasurementType &&
+		(f.CommodityType == "" || (description.CommodityType != nil && *description.CommodityType == f.CommodityType)) &&
+		description.ScopeType != nil && *description.ScopeType == f.ScopeType
+}
+
+// return the value of a measurement if it is usable
+func measurementValue(values []model.MeasurementDataType, id model.MeasurementIdType) (float64, bool) {
+	for _, item := range values {
+		if item.MeasurementId == nil || *item.MeasurementId != id || item.Value == nil {
+			continue
+		}
+
+		if item.ValueState != nil && *item.ValueState == model.MeasurementValueStateTypeError {
+			return 0, false
+		}
+
+		return item.Value.GetValue(), true
+	}
+
+	return 0, false
+}
+
+// invert the value of a measurement if its electrical connection has
+// the opposite positive energy direction
+func directedValue(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	measurementId model.MeasurementIdType,
+	value float64,
+	energyDirection model.EnergyDirectionType,
+) float64 {
+	if energyDirection == "" {
+		return value
+	}
+
+	electricalConnection, err := features.NewElectricalConnection(localEntity, remoteEntity)
+	if err != nil {
+		return value
+	}
+
+	connection, err := electricalConnection.GetDescriptionForMeasurementId(measurementId)
+	if err != nil || connection.PositiveEnergyDirection == nil || *connection.PositiveEnergyDirection == energyDirection {
+		return value
+	}
+
+	return -value
+}
+
+// return the descriptions and values of a remote entity matching the filter
+func measurementData(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	filter MeasurementFilter,
+) ([]model.MeasurementDescriptionDataType, []model.MeasurementDataType, error) {
+	measurement, err := features.NewMeasurement(localEntity, remoteEntity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	descriptions, err := measurement.GetDescriptions()
+	if err != nil {
+		return nil, nil, api.ErrMetadataNotAvailable
+	}
+
+	var result []model.MeasurementDescriptionDataType
+	for _, item := range descriptions {
+		if filter.matches(item) {
+			result = append(result, item)
+		}
+	}
+	if len(result) == 0 {
+		return nil, nil, api.ErrMetadataNotAvailable
+	}
+
+	values, err := measurement.GetValues()
+	if err != nil {
+		return nil, nil, api.ErrDataNotAvailable
+	}
+
+	return result, values, nil
+}
+
+// return the value of a single measurement of a remote entity matching the filter
+//
+// if energyDirection is set, the value is inverted if the electrical
+// connection has the opposite positive energy direction
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no matching measurement description is available
+//   - ErrDataNotAvailable if no usable value for the measurement is available
+//   - and others
+func MeasurementValue(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	filter MeasurementFilter,
+	energyDirection model.EnergyDirectionType,
+) (float64, error) {
+	descriptions, values, err := measurementData(localEntity, remoteEntity, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, description := range descriptions {
+		if value, ok := measurementValue(values, *description.MeasurementId); ok {
+			return directedValue(localEntity, remoteEntity, *description.MeasurementId, value, energyDirection), nil
+		}
+	}
+
+	return 0, api.ErrDataNotAvailable
+}
+
+// return the phase specific values of a remote entity matching the filter,
+// ordered by the given phases
+//
+// only measurements of the given phases which are measured in reference to
+// the given phase are returned, e.g. neutral for phase-to-neutral voltages.
+// use ElectricalConnectionPhaseNameTypeNone for the reference to ignore it.
+//
+// if energyDirection is set, values of electrical connections with the
+// opposite positive energy direction are inverted
+//
+// possible errors:
+//   - ErrMetadataNotAvailable if no matching measurement or parameter description is available
+//   - ErrDataNotAvailable if no usable value for the measurements is available
+//   - and others
+func MeasurementPhaseValues(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	filter MeasurementFilter,
+	phases []model.ElectricalConnectionPhaseNameType,
+	reference model.ElectricalConnectionPhaseNameType,
+	energyDirection model.EnergyDirectionType,
+) ([]float64, error) {
+	descriptions, values, err := measurementData(localEntity, remoteEntity, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	electricalConnection, err := features.NewElectricalConnection(localEntity, remoteEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []float64
+	for _, phase := range phases {
+		for _, description := range descriptions {
+			param, err := electricalConnection.GetParameterDescriptionForMeasurementId(*description.MeasurementId)
+			if err != nil || param.AcMeasuredPhases == nil || *param.AcMeasuredPhases != phase {
+				continue
+			}
+
+			if reference != model.ElectricalConnectionPhaseNameTypeNone &&
+				param.AcMeasuredInReferenceTo != nil && *param.AcMeasuredInReferenceTo != reference {
+				continue
+			}
+
+			value, ok := measurementValue(values, *description.MeasurementId)
+			if !ok {
+				continue
+			}
+
+			result = append(result, directedValue(localEntity, remoteEntity, *description.MeasurementId, value, energyDirection))
+			break
+		}
+	}
+
+	if len(result) == 0 {
+		if _, err := electricalConnection.GetParameterDescriptions(); err != nil {
+			return nil, api.ErrMetadataNotAvailable
+		}
+
+		return nil, api.ErrDataNotAvailable
+	}
+
+	return result, nil
+}
+
+// return the scope types of the measurements contained in the data
+//
+// used to find out which measurements were updated by a data change
+func MeasurementScopesOfData(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	data *model.MeasurementListDataType,
+) []model.ScopeTypeType {
+	measurement, err := features.NewMeasurement(localEntity, remoteEntity)
+	if err != nil || data == nil {
+		return nil
+	}
+
+	var result []model.ScopeTypeType
+	for _, item := range data.MeasurementData {
+		if item.MeasurementId == nil {
+			continue
+		}
+
+		description, err := measurement.GetDescriptionForMeasurementId(*item.MeasurementId)
+		if err != nil || description.ScopeType == nil {
+			continue
+		}
+
+		known := false
+		for _, scope := range result {
+			if scope == *description.ScopeType {
+				known = true
+				break
+			}
+		}
+		if !known {
+			result = append(result, *description.ScopeType)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMeasurementSuite(t *testing.T) {
+	suite.Run(t, new(MeasurementSuite))
+}
+
+type MeasurementSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	powerFilter   MeasurementFilter
+	voltageFilter MeasurementFilter
+	energyFilter  MeasurementFilter
+}
+
+func (s *MeasurementSuite) BeforeTest(suiteName, testName string) {
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		&testhelper.WriteMessageHandler{},
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeInverter,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeMeasurement,
+				Functions: []model.FunctionType{
+					model.FunctionTypeMeasurementDescriptionListData,
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionDescriptionListData,
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+				},
+			},
+		},
+	)
+	s.localEntity.GetOrAddFeature(model.FeatureTypeTypeMeasurement, model.RoleTypeClient)
+	s.localEntity.GetOrAddFeature(model.FeatureTypeTypeElectricalConnection, model.RoleTypeClient)
+
+	s.powerFilter = MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypePower,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACPower,
+	}
+	s.voltageFilter = MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeVoltage,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACVoltage,
+	}
+	s.energyFilter = MeasurementFilter{
+		MeasurementType: model.MeasurementTypeTypeEnergy,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       model.ScopeTypeTypeACEnergyConsumed,
+	}
+}
+
+func (s *MeasurementSuite) description(id uint, filter MeasurementFilter) model.MeasurementDescriptionDataType {
+	return model.MeasurementDescriptionDataType{
+		MeasurementId:   util.Ptr(model.MeasurementIdType(id)),
+		MeasurementType: util.Ptr(filter.MeasurementType),
+		CommodityType:   util.Ptr(filter.CommodityType),
+		ScopeType:       util.Ptr(filter.ScopeType),
+	}
+}
+
+func (s *MeasurementSuite) parameter(id uint, phase, reference model.ElectricalConnectionPhaseNameType) model.ElectricalConnectionParameterDescriptionDataType {
+	return model.ElectricalConnectionParameterDescriptionDataType{
+		ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+		ParameterId:             util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+		MeasurementId:           util.Ptr(model.MeasurementIdType(id)),
+		AcMeasuredPhases:        util.Ptr(phase),
+		AcMeasuredInReferenceTo: util.Ptr(reference),
+	}
+}
+
+func (s *MeasurementSuite) addDescriptions() {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementDescriptionListData,
+		&model.MeasurementDescriptionListDataType{
+			MeasurementDescriptionData: []model.MeasurementDescriptionDataType{
+				s.description(0, s.powerFilter),
+				s.description(1, s.powerFilter),
+				s.description(2, s.powerFilter),
+				s.description(3, s.voltageFilter),
+				s.description(4, s.voltageFilter),
+				s.description(5, s.energyFilter),
+			},
+		})
+}
+
+func (s *MeasurementSuite) addParameterDescriptions(direction model.EnergyDirectionType) {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionDescriptionListData,
+		&model.ElectricalConnectionDescriptionListDataType{
+			ElectricalConnectionDescriptionData: []model.ElectricalConnectionDescriptionDataType{
+				{
+					ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+					PositiveEnergyDirection: util.Ptr(direction),
+				},
+			},
+		})
+
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+		&model.ElectricalConnectionParameterDescriptionListDataType{
+			ElectricalConnectionParameterDescriptionData: []model.ElectricalConnectionParameterDescriptionDataType{
+				s.parameter(0, model.ElectricalConnectionPhaseNameTypeA, model.ElectricalConnectionPhaseNameTypeNeutral),
+				s.parameter(1, model.ElectricalConnectionPhaseNameTypeB, model.ElectricalConnectionPhaseNameTypeNeutral),
+				s.parameter(2, model.ElectricalConnectionPhaseNameTypeC, model.ElectricalConnectionPhaseNameTypeNeutral),
+				s.parameter(3, model.ElectricalConnectionPhaseNameTypeA, model.ElectricalConnectionPhaseNameTypeNeutral),
+				s.parameter(4, model.ElectricalConnectionPhaseNameTypeAb, model.ElectricalConnectionPhaseNameTypeNone),
+			},
+		})
+}
+
+func (s *MeasurementSuite) addValues() *model.MeasurementListDataType {
+	data := &model.MeasurementListDataType{
+		MeasurementData: []model.MeasurementDataType{
+			{
+				MeasurementId: util.Ptr(model.MeasurementIdType(0)),
+				Value:         model.NewScaledNumberType(1000),
+			},
+			{
+				MeasurementId: util.Ptr(model.MeasurementIdType(2)),
+				Value:         model.NewScaledNumberType(3000),
+			},
+			{
+				MeasurementId: util.Ptr(model.MeasurementIdType(3)),
+				Value:         model.NewScaledNumberType(230),
+			},
+			{
+				MeasurementId: util.Ptr(model.MeasurementIdType(4)),
+				Value:         model.NewScaledNumberType(400),
+			},
+			{
+				MeasurementId: util.Ptr(model.MeasurementIdType(5)),
+				Value:         model.NewScaledNumberType(5000),
+				ValueState:    util.Ptr(model.MeasurementValueStateTypeError),
+			},
+		},
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData, data)
+
+	return data
+}
+
+func (s *MeasurementSuite) Test_MeasurementValue() {
+	_, err := MeasurementValue(s.localEntity, nil, s.powerFilter, "")
+	assert.NotNil(s.T(), err)
+
+	_, err = MeasurementValue(s.localEntity, s.remoteEntity, s.powerFilter, "")
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.addDescriptions()
+
+	_, err = MeasurementValue(s.localEntity, s.remoteEntity, s.powerFilter, "")
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.addValues()
+
+	value, err := MeasurementValue(s.localEntity, s.remoteEntity, s.powerFilter, "")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1000.0, value)
+
+	// an empty commodity type matches all commodities
+	filter := s.powerFilter
+	filter.CommodityType = ""
+	value, err = MeasurementValue(s.localEntity, s.remoteEntity, filter, "")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1000.0, value)
+
+	filter.CommodityType = model.CommodityTypeTypeGas
+	_, err = MeasurementValue(s.localEntity, s.remoteEntity, filter, "")
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	// values with an error state are not usable
+	_, err = MeasurementValue(s.localEntity, s.remoteEntity, s.energyFilter, "")
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.addParameterDescriptions(model.EnergyDirectionTypeProduce)
+
+	value, err = MeasurementValue(s.localEntity, s.remoteEntity, s.powerFilter, model.EnergyDirectionTypeConsume)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), -1000.0, value)
+
+	value, err = MeasurementValue(s.localEntity, s.remoteEntity, s.powerFilter, model.EnergyDirectionTypeProduce)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1000.0, value)
+}
+
+func (s *MeasurementSuite) Test_MeasurementPhaseValues() {
+	phases := []model.ElectricalConnectionPhaseNameType{
+		model.ElectricalConnectionPhaseNameTypeA,
+		model.ElectricalConnectionPhaseNameTypeB,
+		model.ElectricalConnectionPhaseNameTypeC,
+	}
+
+	_, err := MeasurementPhaseValues(s.localEntity, s.remoteEntity, s.powerFilter, phases,
+		model.ElectricalConnectionPhaseNameTypeNone, "")
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.addDescriptions()
+	s.addValues()
+
+	_, err = MeasurementPhaseValues(s.localEntity, s.remoteEntity, s.powerFilter, phases,
+		model.ElectricalConnectionPhaseNameTypeNone, "")
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.addParameterDescriptions(model.EnergyDirectionTypeConsume)
+
+	// phase B has no value
+	values, err := MeasurementPhaseValues(s.localEntity, s.remoteEntity, s.powerFilter, phases,
+		model.ElectricalConnectionPhaseNameTypeNone, model.EnergyDirectionTypeConsume)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{1000, 3000}, values)
+
+	values, err = MeasurementPhaseValues(s.localEntity, s.remoteEntity, s.voltageFilter, phases,
+		model.ElectricalConnectionPhaseNameTypeNeutral, "")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{230}, values)
+
+	values, err = MeasurementPhaseValues(s.localEntity, s.remoteEntity, s.voltageFilter,
+		[]model.ElectricalConnectionPhaseNameType{model.ElectricalConnectionPhaseNameTypeAb},
+		model.ElectricalConnectionPhaseNameTypeNone, "")
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{400}, values)
+
+	_, err = MeasurementPhaseValues(s.localEntity, s.remoteEntity, s.energyFilter, phases,
+		model.ElectricalConnectionPhaseNameTypeNone, "")
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+}
+
+func (s *MeasurementSuite) Test_MeasurementScopesOfData() {
+	scopes := MeasurementScopesOfData(s.localEntity, nil, nil)
+	assert.Nil(s.T(), scopes)
+
+	s.addDescriptions()
+	data := s.addValues()
+
+	scopes = MeasurementScopesOfData(s.localEntity, s.remoteEntity, data)
+	assert.Equal(s.T(), []model.ScopeTypeType{
+		model.ScopeTypeTypeACPower,
+		model.ScopeTypeTypeACVoltage,
+		model.ScopeTypeTypeACEnergyConsumed,
+	}, scopes)
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"errors"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+const (
+	// the duration after which the remote entity is considered to be
+	// offline if no heartbeat was received
+	powerLimitHeartbeatTimeout = time.Minute * 2
+
+	// the duration after startup in which a controllable system has to
+	// receive a limit, otherwise it operates autonomously
+	powerLimitInitTimeout = time.Minute * 2
+
+	// the initial failsafe values of a controllable system, should be set by the application
+	defaultFailsafeActivePowerLimit = 0.0
+	defaultFailsafeDurationMinimum  = time.Hour * 2
+)
+
+// the direction specific parts of the Limitation of Power Consumption (LPC)
+// and Limitation of Power Production (LPP) use cases
+type PowerLimitScope struct {
+	// the name of the use case
+	UseCaseName model.UseCaseNameType
+
+	// the energy direction of the limit
+	Direction model.EnergyDirectionType
+
+	// the scope of the limit
+	Scope model.ScopeTypeType
+
+	// the device configuration key of the failsafe active power limit
+	FailsafeLimitKeyName model.DeviceConfigurationKeyNameType
+
+	// the electrical connection characteristic of the nominal maximum active power
+	NominalMaxCharacteristic model.ElectricalConnectionCharacteristicTypeType
+}
+
+// the scope of the LPC use case
+var PowerConsumptionLimitScope = PowerLimitScope{
+	UseCaseName:              model.UseCaseNameTypeLimitationOfPowerConsumption,
+	Direction:                model.EnergyDirectionTypeConsume,
+	Scope:                    model.ScopeTypeTypeActivePowerLimit,
+	FailsafeLimitKeyName:     model.DeviceConfigurationKeyNameTypeFailsafeConsumptionActivePowerLimit,
+	NominalMaxCharacteristic: model.ElectricalConnectionCharacteristicTypeTypePowerConsumptionNominalMax,
+}
+
+// the scope of the LPP use case
+var PowerProductionLimitScope = PowerLimitScope{
+	UseCaseName:              model.UseCaseNameTypeLimitationOfPowerProduction,
+	Direction:                model.EnergyDirectionTypeProduce,
+	Scope:                    model.ScopeTypeTypeActivePowerLimit,
+	FailsafeLimitKeyName:     model.DeviceConfigurationKeyNameTypeFailsafeProductionActivePowerLimit,
+	NominalMaxCharacteristic: model.ElectricalConnectionCharacteristicTypeTypePowerProductionNominalMax,
+}
+
+// return the filter of the load control limit of the use case
+func (s PowerLimitScope) LimitFilter() LimitFilter {
+	return LimitFilter{
+		LimitType: model.LoadControlLimitTypeTypeSignDependentAbsValueLimit,
+		Category:  model.LoadControlCategoryTypeObligation,
+		Direction: s.Direction,
+		Scope:     s.Scope,
+	}
+}
+
+// the limit has to have a non negative value
+func validatePowerLimit(limit model.LoadControlLimitDataType) error {
+	if limit.Value == nil {
+		return api.ErrMissingData
+	}
+
+	if limit.Value.GetValue() < 0 {
+		return errors.New("limit value must not be negative")
+	}
+
+	return nil
+}
+
+// the failsafe limit has to be a non negative scaled number
+func validateFailsafeActivePowerLimit(value model.DeviceConfigurationKeyValueValueType) error {
+	if value.ScaledNumber == nil {
+		return api.ErrMissingData
+	}
+
+	if value.ScaledNumber.GetValue() < 0 {
+		return errors.New("limit value must not be negative")
+	}
+
+	return nil
+}
+
+// the failsafe duration minimum key value has to be a duration within the allowed range
+func validateFailsafeDurationKeyValue(value model.DeviceConfigurationKeyValueValueType) error {
+	if value.Duration == nil {
+		return api.ErrMissingData
+	}
+
+	duration, err := value.Duration.GetTimeDuration()
+	if err != nil {
+		return err
+	}
+
+	return ValidateFailsafeDurationMinimum(duration)
+}
@@ -0,0 +1,329 @@
+// Package testhelper provides a local and a remote SPINE device setup
+// used by the tests of the use cases
+package testhelper
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/enbility/eebus-go/util"
+	shipapi "github.com/enbility/ship-go/api"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	RemoteSki        = "test"
+	remoteDeviceName = "remoteDevice"
+)
+
+// the functions a remote feature supports
+type FeatureFunctions struct {
+	FeatureType model.FeatureTypeType
+	// defaults to RoleTypeServer
+	Role      model.RoleType
+	Functions []model.FunctionType
+}
+
+// records all messages sent to the remote device
+type WriteMessageHandler struct {
+	sentMessages [][]byte
+
+	onSend func(datagram model.DatagramType)
+
+	mux sync.Mutex
+}
+
+var _ shipapi.ShipConnectionDataWriterInterface = (*WriteMessageHandler)(nil)
+
+func (t *WriteMessageHandler) WriteShipMessageWithPayload(message []byte) {
+	t.mux.Lock()
+	t.sentMessages = append(t.sentMessages, message)
+	onSend := t.onSend
+	t.mux.Unlock()
+
+	if onSend == nil {
+		return
+	}
+
+	var datagram model.Datagram
+	if err := json.Unmarshal(message, &datagram); err == nil {
+		onSend(datagram.Datagram)
+	}
+}
+
+// set a function invoked with each sent message before the send returns,
+// e.g. to deliver a result before the sender got the msg counter, nil to remove it
+func (t *WriteMessageHandler) SetOnSend(onSend func(datagram model.DatagramType)) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.onSend = onSend
+}
+
+// return the number of sent messages
+func (t *WriteMessageHandler) Count() int {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	return len(t.sentMessages)
+}
+
+// return the last sent message as a datagram
+func (t *WriteMessageHandler) LastDatagram() *model.DatagramType {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if len(t.sentMessages) == 0 {
+		return nil
+	}
+
+	var datagram model.Datagram
+	if err := json.Unmarshal(t.sentMessages[len(t.sentMessages)-1], &datagram); err != nil {
+		return nil
+	}
+
+	return &datagram.Datagram
+}
+
+// set up a local device with a local entity and a remote device with
+// a remote entity providing features with the given functions
+//
+// the remote device is connected to the local device
+func SetupEntities(
+	t assert.TestingT,
+	writer shipapi.ShipConnectionDataWriterInterface,
+	localEntityType model.EntityTypeType,
+	remoteEntityType model.EntityTypeType,
+	featureFunctions []FeatureFunctions,
+) (spineapi.EntityLocalInterface, spineapi.EntityRemoteInterface) {
+	localDevice := spine.NewDeviceLocal("TestBrandName", "TestDeviceModel", "TestSerialNumber", "TestDeviceCode",
+		"TestDeviceAddress", model.DeviceTypeTypeEnergyManagementSystem, model.NetworkManagementFeatureSetTypeSmart, time.Second*4)
+	localEntity := spine.NewEntityLocal(localDevice, localEntityType, spine.NewAddressEntityType([]uint{1}))
+	localDevice.AddEntity(localEntity)
+
+	remoteDevice := spine.NewDeviceRemote(localDevice, RemoteSki, spine.NewSender(writer))
+	data := &model.NodeManagementDetailedDiscoveryDataType{
+		DeviceInformation: &model.NodeManagementDetailedDiscoveryDeviceInformationType{
+			Description: &model.NetworkManagementDeviceDescriptionDataType{
+				DeviceAddress: &model.DeviceAddressType{
+					Device: util.Ptr(model.AddressDeviceType(remoteDeviceName)),
+				},
+			},
+		},
+		EntityInformation: []model.NodeManagementDetailedDiscoveryEntityInformationType{
+			{
+				Description: &model.NetworkManagementEntityDescriptionDataType{
+					EntityAddress: &model.EntityAddressType{
+						Device: util.Ptr(model.AddressDeviceType(remoteDeviceName)),
+						Entity: []model.AddressEntityType{1},
+					},
+					EntityType: util.Ptr(remoteEntityType),
+				},
+			},
+		},
+	}
+
+	for i, item := range featureFunctions {
+		role := item.Role
+		if role == "" {
+			role = model.RoleTypeServer
+		}
+
+		feature := model.NodeManagementDetailedDiscoveryFeatureInformationType{
+			Description: &model.NetworkManagementFeatureDescriptionDataType{
+				FeatureAddress: &model.FeatureAddressType{
+					Device:  util.Ptr(model.AddressDeviceType(remoteDeviceName)),
+					Entity:  []model.AddressEntityType{1},
+					Feature: util.Ptr(model.AddressFeatureType(i + 1)),
+				},
+				FeatureType: util.Ptr(item.FeatureType),
+				Role:        util.Ptr(role),
+			},
+		}
+		for _, function := range item.Functions {
+			feature.Description.SupportedFunction = append(feature.Description.SupportedFunction,
+				model.FunctionPropertyType{
+					Function: util.Ptr(function),
+					PossibleOperations: &model.PossibleOperationsType{
+						Read:  &model.PossibleOperationsReadType{},
+						Write: &model.PossibleOperationsWriteType{},
+					},
+				})
+		}
+		data.FeatureInformation = append(data.FeatureInformation, feature)
+	}
+
+	remoteEntities, err := remoteDevice.AddEntityAndFeatures(true, data)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(remoteEntities))
+	remoteDevice.UpdateDevice(data.DeviceInformation.Description)
+
+	localDevice.AddRemoteDeviceForSki(RemoteSki, remoteDevice)
+
+	return localEntity, remoteEntities[0]
+}
+
+// announce the support of a use case for the remote entity and
+// return the event payload informing about the change
+func SetRemoteUseCase(
+	remoteEntity spineapi.EntityRemoteInterface,
+	actor model.UseCaseActorType,
+	name model.UseCaseNameType,
+	available bool,
+) spineapi.EventPayload {
+	return SetRemoteUseCaseScenarios(remoteEntity, actor, name, available, nil)
+}
+
+// announce the support of a use case with the given scenarios for the
+// remote entity and return the event payload informing about the change
+func SetRemoteUseCaseScenarios(
+	remoteEntity spineapi.EntityRemoteInterface,
+	actor model.UseCaseActorType,
+	name model.UseCaseNameType,
+	available bool,
+	scenarios []model.UseCaseScenarioSupportType,
+) spineapi.EventPayload {
+	remoteDevice := remoteEntity.Device()
+
+	data := &model.NodeManagementUseCaseDataType{
+		UseCaseInformation: []model.UseCaseInformationDataType{
+			{
+				Address: &model.FeatureAddressType{
+					Device: util.Ptr(model.AddressDeviceType(remoteDeviceName)),
+					Entity: remoteEntity.Address().Entity,
+				},
+				Actor: util.Ptr(actor),
+				UseCaseSupport: []model.UseCaseSupportType{
+					{
+						UseCaseName:      util.Ptr(name),
+						UseCaseAvailable: util.Ptr(available),
+						ScenarioSupport:  scenarios,
+					},
+				},
+			},
+		},
+	}
+
+	nodeMgmt := remoteDevice.FeatureByEntityTypeAndRole(
+		remoteDevice.Entity(spine.DeviceInformationAddressEntity),
+		model.FeatureTypeTypeNodeManagement,
+		model.RoleTypeSpecial)
+	nodeMgmt.UpdateData(model.FunctionTypeNodeManagementUseCaseData, data, nil, nil)
+
+	return spineapi.EventPayload{
+		Ski:        RemoteSki,
+		EventType:  spineapi.EventTypeDataChange,
+		ChangeType: spineapi.ElementChangeUpdate,
+		Device:     remoteDevice,
+		Entity:     nodeMgmt.Entity(),
+		Feature:    nodeMgmt,
+		Data:       data,
+	}
+}
+
+// set the data of a remote server feature and return the event payload
+// informing about the change
+func SetRemoteData(
+	remoteEntity spineapi.EntityRemoteInterface,
+	featureType model.FeatureTypeType,
+	function model.FunctionType,
+	data any,
+) spineapi.EventPayload {
+	remoteDevice := remoteEntity.Device()
+
+	feature := remoteDevice.FeatureByEntityTypeAndRole(remoteEntity, featureType, model.RoleTypeServer)
+	feature.UpdateData(function, data, nil, nil)
+
+	return spineapi.EventPayload{
+		Ski:           RemoteSki,
+		EventType:     spineapi.EventTypeDataChange,
+		ChangeType:    spineapi.ElementChangeUpdate,
+		Device:        remoteDevice,
+		Entity:        remoteEntity,
+		Feature:       feature,
+		Function:      function,
+		CmdClassifier: util.Ptr(model.CmdClassifierTypeNotify),
+		Data:          data,
+	}
+}
+
+// deliver a result for a message sent by the local client feature
+// to the remote server feature
+func SetRemoteResult(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	featureType model.FeatureTypeType,
+	msgCounter model.MsgCounterType,
+	errorNumber model.ErrorNumberType,
+) {
+	remoteDevice := remoteEntity.Device()
+
+	localFeature := localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient)
+	remoteFeature := remoteDevice.FeatureByEntityTypeAndRole(remoteEntity, featureType, model.RoleTypeServer)
+
+	message := &spineapi.Message{
+		RequestHeader: &model.HeaderType{
+			MsgCounterReference: util.Ptr(msgCounter),
+		},
+		CmdClassifier: model.CmdClassifierTypeResult,
+		Cmd: model.CmdType{
+			ResultData: &model.ResultDataType{
+				ErrorNumber: util.Ptr(errorNumber),
+			},
+		},
+		FeatureRemote: remoteFeature,
+		EntityRemote:  remoteEntity,
+		DeviceRemote:  remoteDevice,
+	}
+
+	_ = localFeature.HandleMessage(message)
+}
+
+// let the local server feature handle a partial write of the remote client feature
+//
+// returns the event payload informing about the change, or the error
+// the write got answered with
+func WriteLocalData(
+	localEntity spineapi.EntityLocalInterface,
+	remoteEntity spineapi.EntityRemoteInterface,
+	featureType model.FeatureTypeType,
+	cmd model.CmdType,
+) (spineapi.EventPayload, *model.ErrorType) {
+	remoteDevice := remoteEntity.Device()
+
+	localFeature := localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeServer)
+	remoteFeature := remoteDevice.FeatureByEntityTypeAndRole(remoteEntity, featureType, model.RoleTypeClient)
+
+	message := &spineapi.Message{
+		RequestHeader: &model.HeaderType{},
+		CmdClassifier: model.CmdClassifierTypeWrite,
+		Cmd:           cmd,
+		FilterPartial: model.NewFilterTypePartial(),
+		FeatureRemote: remoteFeature,
+		EntityRemote:  remoteEntity,
+		DeviceRemote:  remoteDevice,
+	}
+
+	if err := localFeature.HandleMessage(message); err != nil {
+		return spineapi.EventPayload{}, err
+	}
+
+	cmdData, _ := cmd.Data()
+
+	return spineapi.EventPayload{
+		Ski:           RemoteSki,
+		EventType:     spineapi.EventTypeDataChange,
+		ChangeType:    spineapi.ElementChangeUpdate,
+		Device:        remoteDevice,
+		Entity:        remoteEntity,
+		Feature:       remoteFeature,
+		LocalFeature:  localFeature,
+		Function:      *cmdData.Function,
+		CmdClassifier: util.Ptr(model.CmdClassifierTypeWrite),
+		Data:          cmdData.Value,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package mgcp
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// Grid maximum allowed feed-in power as percentage value of the cumulated
+	// nominal peak power of all electricity producing PV systems was updated
+	//
+	// Use `PowerLimitationFactor` to get the current data
+	DataUpdatePowerLimitationFactor api.EventType = "ma-mgcp-DataUpdatePowerLimitationFactor"
+
+	// Momentary total power consumption or feed-in was updated
+	//
+	// Use `Power` to get the current data
+	DataUpdatePower api.EventType = "ma-mgcp-DataUpdatePower"
+
+	// Total feed-in energy was updated
+	//
+	// Use `EnergyFeedIn` to get the current data
+	DataUpdateEnergyFeedIn api.EventType = "ma-mgcp-DataUpdateEnergyFeedIn"
+
+	// Total consumed energy was updated
+	//
+	// Use `EnergyConsumed` to get the current data
+	DataUpdateEnergyConsumed api.EventType = "ma-mgcp-DataUpdateEnergyConsumed"
+
+	// Phase specific momentary current consumption or feed-in was updated
+	//
+	// Use `CurrentPerPhase` to get the current data
+	DataUpdateCurrentPerPhase api.EventType = "ma-mgcp-DataUpdateCurrentPerPhase"
+
+	// Phase specific voltage was updated
+	//
+	// Use `VoltagePerPhase` to get the current data
+	DataUpdateVoltagePerPhase api.EventType = "ma-mgcp-DataUpdateVoltagePerPhase"
+
+	// Grid frequency was updated
+	//
+	// Use `Frequency` to get the current data
+	DataUpdateFrequency api.EventType = "ma-mgcp-DataUpdateFrequency"
+)
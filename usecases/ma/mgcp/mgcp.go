@@ -0,0 +1,146 @@
+package mgcp
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Monitoring of Grid Connection Point, actor Monitoring Appliance
+//
+// Used e.g. by a HEMS to monitor the grid connection point of the premises
+// provided by a smart meter gateway
+type MGCP struct {
+	*usecases.UseCase
+}
+
+// creates a new MGCP monitoring appliance use case for the local entity
+//
+// eventCB is invoked for all events of remote grid connection points
+func NewMGCP(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *MGCP {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeGridConnectionPointOfPremises,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	}
+
+	e := &MGCP{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeMonitoringAppliance,
+		model.UseCaseNameTypeMonitoringOfGridConnectionPoint,
+		"1.0.0",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3, 4, 5, 6, 7},
+		[]model.UseCaseActorType{model.UseCaseActorTypeGridConnectionPoint},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*MGCP)(nil)
+var _ usecases.EntityHandlerInterface = (*MGCP)(nil)
+
+// request the descriptions required to interpret the key values and measurements
+func (e *MGCP) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, entity); err == nil {
+		if _, err := deviceConfiguration.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := electricalConnection.RequestParameterDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if measurement, err := features.NewMeasurement(e.LocalEntity, entity); err == nil {
+		if _, err := measurement.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := measurement.RequestConstraints(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *MGCP) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *MGCP) HandleDataChange(payload spineapi.EventPayload) {
+	switch data := payload.Data.(type) {
+	case *model.DeviceConfigurationKeyValueDescriptionListDataType:
+		if deviceConfiguration, err := features.NewDeviceConfiguration(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := deviceConfiguration.RequestKeyValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.DeviceConfigurationKeyValueListDataType:
+		if _, err := e.PowerLimitationFactor(payload.Entity); err == nil {
+			e.ReportEvent(payload.Entity, DataUpdatePowerLimitationFactor)
+		}
+
+	case *model.MeasurementDescriptionListDataType:
+		if measurement, err := features.NewMeasurement(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := measurement.RequestValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.MeasurementListDataType:
+		e.handleMeasurements(payload.Entity, data)
+	}
+}
+
+// report the events for the updated measurements
+func (e *MGCP) handleMeasurements(entity spineapi.EntityRemoteInterface, data *model.MeasurementListDataType) {
+	for _, scope := range internal.MeasurementScopesOfData(e.LocalEntity, entity, data) {
+		var err error
+		var event api.EventType
+
+		switch scope {
+		case model.ScopeTypeTypeACPowerTotal:
+			_, err = e.Power(entity)
+			event = DataUpdatePower
+		case model.ScopeTypeTypeGridFeedIn:
+			_, err = e.EnergyFeedIn(entity)
+			event = DataUpdateEnergyFeedIn
+		case model.ScopeTypeTypeGridConsumption:
+			_, err = e.EnergyConsumed(entity)
+			event = DataUpdateEnergyConsumed
+		case model.ScopeTypeTypeACCurrent:
+			_, err = e.CurrentPerPhase(entity)
+			event = DataUpdateCurrentPerPhase
+		case model.ScopeTypeTypeACVoltage:
+			_, err = e.VoltagePerPhase(entity)
+			event = DataUpdateVoltagePerPhase
+		case model.ScopeTypeTypeACFrequency:
+			_, err = e.Frequency(entity)
+			event = DataUpdateFrequency
+		default:
+			continue
+		}
+
+		if err == nil {
+			e.ReportEvent(entity, event)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package mgcp
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMaMGCPSuite(t *testing.T) {
+	suite.Run(t, new(MaMGCPSuite))
+}
+
+type MaMGCPSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *MGCP
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *MaMGCPSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *MaMGCPSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *MaMGCPSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeGridConnectionPointOfPremises,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeDeviceConfiguration,
+				Functions: []model.FunctionType{
+					model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+					model.FunctionTypeDeviceConfigurationKeyValueListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeMeasurement,
+				Functions: []model.FunctionType{
+					model.FunctionTypeMeasurementDescriptionListData,
+					model.FunctionTypeMeasurementConstraintsListData,
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionDescriptionListData,
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewMGCP(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *MaMGCPSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeGridConnectionPoint,
+		model.UseCaseNameTypeMonitoringOfGridConnectionPoint, true)
+	s.sut.HandleEvent(payload)
+}
+
+// the measurements of the remote entity, ordered by their ids
+var measurements = []struct {
+	measurementType model.MeasurementTypeType
+	scope           model.ScopeTypeType
+	phase           model.ElectricalConnectionPhaseNameType
+	reference       model.ElectricalConnectionPhaseNameType
+	value           float64
+}{
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPowerTotal, model.ElectricalConnectionPhaseNameTypeAbc, model.ElectricalConnectionPhaseNameTypeNeutral, -3000},
+	{model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeGridFeedIn, model.ElectricalConnectionPhaseNameTypeAbc, model.ElectricalConnectionPhaseNameTypeNeutral, 1500},
+	{model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeGridConsumption, model.ElectricalConnectionPhaseNameTypeAbc, model.ElectricalConnectionPhaseNameTypeNeutral, 500},
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeA, model.ElectricalConnectionPhaseNameTypeNeutral, -4.3},
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeB, model.ElectricalConnectionPhaseNameTypeNeutral, -4.4},
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeC, model.ElectricalConnectionPhaseNameTypeNeutral, -4.5},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeA, model.ElectricalConnectionPhaseNameTypeNeutral, 230},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeB, model.ElectricalConnectionPhaseNameTypeNeutral, 231},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeC, model.ElectricalConnectionPhaseNameTypeNeutral, 232},
+	{model.MeasurementTypeTypeFrequency, model.ScopeTypeTypeACFrequency, model.ElectricalConnectionPhaseNameTypeNone, model.ElectricalConnectionPhaseNameTypeNone, 50},
+}
+
+func (s *MaMGCPSuite) descriptions() spineapi.EventPayload {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionDescriptionListData,
+		&model.ElectricalConnectionDescriptionListDataType{
+			ElectricalConnectionDescriptionData: []model.ElectricalConnectionDescriptionDataType{
+				{
+					ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+					PositiveEnergyDirection: util.Ptr(model.EnergyDirectionTypeConsume),
+				},
+			},
+		})
+
+	params := &model.ElectricalConnectionParameterDescriptionListDataType{}
+	descriptions := &model.MeasurementDescriptionListDataType{}
+	for id, item := range measurements {
+		params.ElectricalConnectionParameterDescriptionData = append(params.ElectricalConnectionParameterDescriptionData,
+			model.ElectricalConnectionParameterDescriptionDataType{
+				ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:             util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				MeasurementId:           util.Ptr(model.MeasurementIdType(id)),
+				AcMeasuredPhases:        util.Ptr(item.phase),
+				AcMeasuredInReferenceTo: util.Ptr(item.reference),
+			})
+		descriptions.MeasurementDescriptionData = append(descriptions.MeasurementDescriptionData,
+			model.MeasurementDescriptionDataType{
+				MeasurementId:   util.Ptr(model.MeasurementIdType(id)),
+				MeasurementType: util.Ptr(item.measurementType),
+				CommodityType:   util.Ptr(model.CommodityTypeTypeElectricity),
+				ScopeType:       util.Ptr(item.scope),
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData, params)
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementDescriptionListData, descriptions)
+}
+
+func (s *MaMGCPSuite) keyValueDescriptions() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueDescriptionListData,
+		&model.DeviceConfigurationKeyValueDescriptionListDataType{
+			DeviceConfigurationKeyValueDescriptionData: []model.DeviceConfigurationKeyValueDescriptionDataType{
+				{
+					KeyId:     util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					KeyName:   util.Ptr(model.DeviceConfigurationKeyNameTypePvCurtailmentLimitFactor),
+					ValueType: util.Ptr(model.DeviceConfigurationKeyValueTypeTypeScaledNumber),
+				},
+			},
+		})
+}
+
+func (s *MaMGCPSuite) keyValues() spineapi.EventPayload {
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeDeviceConfiguration,
+		model.FunctionTypeDeviceConfigurationKeyValueListData,
+		&model.DeviceConfigurationKeyValueListDataType{
+			DeviceConfigurationKeyValueData: []model.DeviceConfigurationKeyValueDataType{
+				{
+					KeyId: util.Ptr(model.DeviceConfigurationKeyIdType(0)),
+					Value: &model.DeviceConfigurationKeyValueValueType{
+						ScaledNumber: model.NewScaledNumberType(70),
+					},
+				},
+			},
+		})
+}
+
+func (s *MaMGCPSuite) values() spineapi.EventPayload {
+	data := &model.MeasurementListDataType{}
+	for id, item := range measurements {
+		data.MeasurementData = append(data.MeasurementData, model.MeasurementDataType{
+			MeasurementId: util.Ptr(model.MeasurementIdType(id)),
+			Value:         model.NewScaledNumberType(item.value),
+		})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData, data)
+}
+
+func (s *MaMGCPSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeDeviceConfiguration,
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeMonitoringAppliance, model.UseCaseNameTypeMonitoringOfGridConnectionPoint))
+}
+
+func (s *MaMGCPSuite) Test_EntityConnected() {
+	s.connect()
+
+	assert.True(s.T(), s.sut.HasRemoteEntity(s.remoteEntity))
+	assert.True(s.T(), s.hasEvent(api.UseCaseSupportUpdate))
+	// subscriptions and requests got sent
+	assert.True(s.T(), s.writeHandler.Count() >= 8)
+}
+
+func (s *MaMGCPSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := s.descriptions()
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the measurement values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.values())
+	for _, event := range []api.EventType{
+		DataUpdatePower,
+		DataUpdateEnergyFeedIn,
+		DataUpdateEnergyConsumed,
+		DataUpdateCurrentPerPhase,
+		DataUpdateVoltagePerPhase,
+		DataUpdateFrequency,
+	} {
+		assert.True(s.T(), s.hasEvent(event), event)
+	}
+}
+
+func (s *MaMGCPSuite) Test_HandleDataChange_KeyValues() {
+	s.connect()
+
+	payload := s.keyValueDescriptions()
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the key values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.keyValues())
+	assert.True(s.T(), s.hasEvent(DataUpdatePowerLimitationFactor))
+}
+
+func (s *MaMGCPSuite) Test_HandleDataChange_Partial() {
+	s.connect()
+	s.descriptions()
+
+	payload := testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData,
+		&model.MeasurementListDataType{
+			MeasurementData: []model.MeasurementDataType{
+				{
+					MeasurementId: util.Ptr(model.MeasurementIdType(9)),
+					Value:         model.NewScaledNumberType(50),
+				},
+			},
+		})
+	s.sut.HandleEvent(payload)
+
+	assert.True(s.T(), s.hasEvent(DataUpdateFrequency))
+	assert.False(s.T(), s.hasEvent(DataUpdatePower))
+}
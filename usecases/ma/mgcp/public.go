@@ -0,0 +1,187 @@
+package mgcp
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the phases of phase specific measurements
+var phases = []model.ElectricalConnectionPhaseNameType{
+	model.ElectricalConnectionPhaseNameTypeA,
+	model.ElectricalConnectionPhaseNameTypeB,
+	model.ElectricalConnectionPhaseNameTypeC,
+}
+
+// return the value of a single electricity measurement
+func (e *MGCP) value(
+	entity spineapi.EntityRemoteInterface,
+	measurementType model.MeasurementTypeType,
+	scope model.ScopeTypeType,
+	energyDirection model.EnergyDirectionType,
+) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: measurementType,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       scope,
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, energyDirection)
+}
+
+// return the values of phase specific electricity measurements
+func (e *MGCP) phaseValues(
+	entity spineapi.EntityRemoteInterface,
+	measurementType model.MeasurementTypeType,
+	scope model.ScopeTypeType,
+	reference model.ElectricalConnectionPhaseNameType,
+	energyDirection model.EnergyDirectionType,
+) ([]float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: measurementType,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       scope,
+	}
+
+	return internal.MeasurementPhaseValues(e.LocalEntity, entity, filter, phases, reference, energyDirection)
+}
+
+// Scenario 1
+
+// return the current power limitation factor
+//
+// parameters:
+//   - entity: the entity of the grid connection point
+//
+// return values:
+//   - the grid maximum allowed feed-in power as percentage value of the
+//     cumulated nominal peak power of all electricity producing PV systems
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrDataNotAvailable if no description or value is available
+//   - and others
+func (e *MGCP) PowerLimitationFactor(entity spineapi.EntityRemoteInterface) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	return internal.KeyValueScaledNumber(e.LocalEntity, entity, model.DeviceConfigurationKeyNameTypePvCurtailmentLimitFactor)
+}
+
+// Scenario 2
+
+// return the momentary total power consumption or feed-in
+//
+// parameters:
+//   - entity: the entity of the grid connection point
+//
+// return values:
+//   - positive values are used for consumption, negative values for feed-in
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MGCP) Power(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.value(entity, model.MeasurementTypeTypePower, model.ScopeTypeTypeACPowerTotal, model.EnergyDirectionTypeConsume)
+}
+
+// Scenario 3
+
+// return the total feed-in energy in Wh
+//
+// parameters:
+//   - entity: the entity of the grid connection point
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MGCP) EnergyFeedIn(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.value(entity, model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeGridFeedIn, "")
+}
+
+// Scenario 4
+
+// return the total consumed energy in Wh
+//
+// parameters:
+//   - entity: the entity of the grid connection point
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MGCP) EnergyConsumed(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.value(entity, model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeGridConsumption, "")
+}
+
+// Scenario 5
+
+// return the momentary phase specific current consumption or feed-in
+//
+// parameters:
+//   - entity: the entity of the grid connection point
+//
+// return values:
+//   - the values of the available phases, ordered by phase A, B and C
+//   - positive values are used for consumption, negative values for feed-in
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MGCP) CurrentPerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	return e.phaseValues(entity, model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent,
+		model.ElectricalConnectionPhaseNameTypeNone, model.EnergyDirectionTypeConsume)
+}
+
+// Scenario 6
+
+// return the phase specific voltages measured in reference to neutral
+//
+// parameters:
+//   - entity: the entity of the grid connection point
+//
+// return values:
+//   - the values of the available phases, ordered by phase A, B and C
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MGCP) VoltagePerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	return e.phaseValues(entity, model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage,
+		model.ElectricalConnectionPhaseNameTypeNeutral, "")
+}
+
+// Scenario 7
+
+// return the grid frequency in Hz
+//
+// parameters:
+//   - entity: the entity of the grid connection point
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MGCP) Frequency(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.value(entity, model.MeasurementTypeTypeFrequency, model.ScopeTypeTypeACFrequency, "")
+}
@@ -0,0 +1,106 @@
+package mgcp
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *MaMGCPSuite) Test_PowerLimitationFactor() {
+	_, err := s.sut.PowerLimitationFactor(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.PowerLimitationFactor(s.remoteEntity)
+	assert.NotNil(s.T(), err)
+
+	s.keyValueDescriptions()
+	s.keyValues()
+
+	value, err := s.sut.PowerLimitationFactor(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 70.0, value)
+}
+
+func (s *MaMGCPSuite) Test_Power() {
+	_, err := s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions()
+
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values()
+
+	value, err := s.sut.Power(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), -3000.0, value)
+}
+
+func (s *MaMGCPSuite) Test_Energy() {
+	_, err := s.sut.EnergyFeedIn(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+	_, err = s.sut.EnergyConsumed(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	value, err := s.sut.EnergyFeedIn(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1500.0, value)
+
+	value, err = s.sut.EnergyConsumed(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 500.0, value)
+}
+
+func (s *MaMGCPSuite) Test_CurrentPerPhase() {
+	_, err := s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+
+	_, err = s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values()
+
+	values, err := s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{-4.3, -4.4, -4.5}, values)
+}
+
+func (s *MaMGCPSuite) Test_VoltagePerPhase() {
+	_, err := s.sut.VoltagePerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	values, err := s.sut.VoltagePerPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{230, 231, 232}, values)
+}
+
+func (s *MaMGCPSuite) Test_Frequency() {
+	_, err := s.sut.Frequency(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	value, err := s.sut.Frequency(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 50.0, value)
+}
@@ -0,0 +1,40 @@
+package mpc
+
+import "github.com/enbility/eebus-go/api"
+
+const (
+	// Total momentary active power consumption or production
+	//
+	// Use `Power` to get the current data
+	DataUpdatePower api.EventType = "ma-mpc-DataUpdatePower"
+
+	// Phase specific momentary active power consumption or production
+	//
+	// Use `PowerPerPhase` to get the current data
+	DataUpdatePowerPerPhase api.EventType = "ma-mpc-DataUpdatePowerPerPhase"
+
+	// Total energy consumed
+	//
+	// Use `EnergyConsumed` to get the current data
+	DataUpdateEnergyConsumed api.EventType = "ma-mpc-DataUpdateEnergyConsumed"
+
+	// Total energy produced
+	//
+	// Use `EnergyProduced` to get the current data
+	DataUpdateEnergyProduced api.EventType = "ma-mpc-DataUpdateEnergyProduced"
+
+	// Phase specific momentary current consumption or production
+	//
+	// Use `CurrentPerPhase` to get the current data
+	DataUpdateCurrentsPerPhase api.EventType = "ma-mpc-DataUpdateCurrentsPerPhase"
+
+	// Phase specific voltage details
+	//
+	// Use `VoltagePerPhase` and `VoltagePhaseToPhase` to get the current data
+	DataUpdateVoltagePerPhase api.EventType = "ma-mpc-DataUpdateVoltagePerPhase"
+
+	// Grid frequency
+	//
+	// Use `Frequency` to get the current data
+	DataUpdateFrequency api.EventType = "ma-mpc-DataUpdateFrequency"
+)
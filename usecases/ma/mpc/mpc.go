@@ -0,0 +1,137 @@
+package mpc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/eebus-go/usecases"
+	"github.com/enbility/eebus-go/usecases/internal"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// Monitoring of Power Consumption, actor Monitoring Appliance
+//
+// Used e.g. by a HEMS to monitor the power, energy, current, voltage and
+// frequency measurements of a monitored unit
+type MPC struct {
+	*usecases.UseCase
+}
+
+// creates a new MPC monitoring appliance use case for the local entity
+//
+// eventCB is invoked for all events of remote monitored units
+func NewMPC(localEntity spineapi.EntityLocalInterface, eventCB api.EntityEventCallback) *MPC {
+	validEntityTypes := []model.EntityTypeType{
+		model.EntityTypeTypeCompressor,
+		model.EntityTypeTypeEVSE,
+		model.EntityTypeTypeHeatPumpAppliance,
+		model.EntityTypeTypeInverter,
+		model.EntityTypeTypeSmartEnergyAppliance,
+		model.EntityTypeTypeSubMeterElectricity,
+	}
+
+	featureTypes := []model.FeatureTypeType{
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	}
+
+	e := &MPC{}
+	e.UseCase = usecases.NewUseCase(
+		localEntity,
+		model.UseCaseActorTypeMonitoringAppliance,
+		model.UseCaseNameTypeMonitoringOfPowerConsumption,
+		"1.0.0",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3, 4, 5},
+		[]model.UseCaseActorType{model.UseCaseActorTypeMonitoredUnit},
+		validEntityTypes,
+		featureTypes,
+		eventCB,
+		e,
+	)
+
+	return e
+}
+
+var _ api.UseCaseInterface = (*MPC)(nil)
+var _ usecases.EntityHandlerInterface = (*MPC)(nil)
+
+// request the descriptions required to interpret the measurements
+func (e *MPC) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	if electricalConnection, err := features.NewElectricalConnection(e.LocalEntity, entity); err == nil {
+		if _, err := electricalConnection.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := electricalConnection.RequestParameterDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if measurement, err := features.NewMeasurement(e.LocalEntity, entity); err == nil {
+		if _, err := measurement.RequestDescriptions(); err != nil {
+			logging.Log().Debug(err)
+		}
+
+		if _, err := measurement.RequestConstraints(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+}
+
+func (e *MPC) EntityDisconnected(entity spineapi.EntityRemoteInterface) {}
+
+func (e *MPC) HandleDataChange(payload spineapi.EventPayload) {
+	switch data := payload.Data.(type) {
+	case *model.MeasurementDescriptionListDataType:
+		if measurement, err := features.NewMeasurement(e.LocalEntity, payload.Entity); err == nil {
+			if _, err := measurement.RequestValues(); err != nil {
+				logging.Log().Debug(err)
+			}
+		}
+
+	case *model.MeasurementListDataType:
+		e.handleMeasurements(payload.Entity, data)
+	}
+}
+
+// report the events for the updated measurements
+func (e *MPC) handleMeasurements(entity spineapi.EntityRemoteInterface, data *model.MeasurementListDataType) {
+	for _, scope := range internal.MeasurementScopesOfData(e.LocalEntity, entity, data) {
+		var err error
+		var event api.EventType
+
+		switch scope {
+		case model.ScopeTypeTypeACPowerTotal:
+			_, err = e.Power(entity)
+			event = DataUpdatePower
+		case model.ScopeTypeTypeACPower:
+			_, err = e.PowerPerPhase(entity)
+			event = DataUpdatePowerPerPhase
+		case model.ScopeTypeTypeACEnergyConsumed:
+			_, err = e.EnergyConsumed(entity)
+			event = DataUpdateEnergyConsumed
+		case model.ScopeTypeTypeACEnergyProduced:
+			_, err = e.EnergyProduced(entity)
+			event = DataUpdateEnergyProduced
+		case model.ScopeTypeTypeACCurrent:
+			_, err = e.CurrentPerPhase(entity)
+			event = DataUpdateCurrentsPerPhase
+		case model.ScopeTypeTypeACVoltage:
+			if _, err = e.VoltagePerPhase(entity); err != nil {
+				_, err = e.VoltagePhaseToPhase(entity)
+			}
+			event = DataUpdateVoltagePerPhase
+		case model.ScopeTypeTypeACFrequency:
+			_, err = e.Frequency(entity)
+			event = DataUpdateFrequency
+		default:
+			continue
+		}
+
+		if err == nil {
+			e.ReportEvent(entity, event)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package mpc
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestMaMPCSuite(t *testing.T) {
+	suite.Run(t, new(MaMPCSuite))
+}
+
+type MaMPCSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	writeHandler *testhelper.WriteMessageHandler
+
+	sut *MPC
+
+	events []api.EventType
+	mux    sync.Mutex
+}
+
+func (s *MaMPCSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.events = append(s.events, event)
+}
+
+func (s *MaMPCSuite) hasEvent(event api.EventType) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for _, item := range s.events {
+		if item == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *MaMPCSuite) BeforeTest(suiteName, testName string) {
+	s.events = nil
+	s.writeHandler = &testhelper.WriteMessageHandler{}
+	s.localEntity, s.remoteEntity = testhelper.SetupEntities(
+		s.T(),
+		s.writeHandler,
+		model.EntityTypeTypeCEM,
+		model.EntityTypeTypeHeatPumpAppliance,
+		[]testhelper.FeatureFunctions{
+			{
+				FeatureType: model.FeatureTypeTypeMeasurement,
+				Functions: []model.FunctionType{
+					model.FunctionTypeMeasurementDescriptionListData,
+					model.FunctionTypeMeasurementConstraintsListData,
+					model.FunctionTypeMeasurementListData,
+				},
+			},
+			{
+				FeatureType: model.FeatureTypeTypeElectricalConnection,
+				Functions: []model.FunctionType{
+					model.FunctionTypeElectricalConnectionDescriptionListData,
+					model.FunctionTypeElectricalConnectionParameterDescriptionListData,
+				},
+			},
+		},
+	)
+
+	s.sut = NewMPC(s.localEntity, s.Event)
+	s.sut.AddFeatures()
+	s.sut.AddUseCase()
+}
+
+// announce the use case support of the remote entity
+func (s *MaMPCSuite) connect() {
+	payload := testhelper.SetRemoteUseCase(s.remoteEntity, model.UseCaseActorTypeMonitoredUnit,
+		model.UseCaseNameTypeMonitoringOfPowerConsumption, true)
+	s.sut.HandleEvent(payload)
+}
+
+// the measurements of the remote entity, ordered by their ids
+var measurements = []struct {
+	measurementType model.MeasurementTypeType
+	scope           model.ScopeTypeType
+	phase           model.ElectricalConnectionPhaseNameType
+	reference       model.ElectricalConnectionPhaseNameType
+	value           float64
+}{
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPowerTotal, model.ElectricalConnectionPhaseNameTypeAbc, model.ElectricalConnectionPhaseNameTypeNeutral, 3000},
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPower, model.ElectricalConnectionPhaseNameTypeA, model.ElectricalConnectionPhaseNameTypeNeutral, 1000},
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPower, model.ElectricalConnectionPhaseNameTypeB, model.ElectricalConnectionPhaseNameTypeNeutral, 1000},
+	{model.MeasurementTypeTypePower, model.ScopeTypeTypeACPower, model.ElectricalConnectionPhaseNameTypeC, model.ElectricalConnectionPhaseNameTypeNeutral, 1000},
+	{model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeACEnergyConsumed, model.ElectricalConnectionPhaseNameTypeAbc, model.ElectricalConnectionPhaseNameTypeNeutral, 1500},
+	{model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeACEnergyProduced, model.ElectricalConnectionPhaseNameTypeAbc, model.ElectricalConnectionPhaseNameTypeNeutral, 500},
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeA, model.ElectricalConnectionPhaseNameTypeNeutral, 4.3},
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeB, model.ElectricalConnectionPhaseNameTypeNeutral, 4.4},
+	{model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent, model.ElectricalConnectionPhaseNameTypeC, model.ElectricalConnectionPhaseNameTypeNeutral, 4.5},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeA, model.ElectricalConnectionPhaseNameTypeNeutral, 230},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeB, model.ElectricalConnectionPhaseNameTypeNeutral, 231},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeC, model.ElectricalConnectionPhaseNameTypeNeutral, 232},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeAb, model.ElectricalConnectionPhaseNameTypeNone, 400},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeBc, model.ElectricalConnectionPhaseNameTypeNone, 401},
+	{model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage, model.ElectricalConnectionPhaseNameTypeAc, model.ElectricalConnectionPhaseNameTypeNone, 402},
+	{model.MeasurementTypeTypeFrequency, model.ScopeTypeTypeACFrequency, model.ElectricalConnectionPhaseNameTypeNone, model.ElectricalConnectionPhaseNameTypeNone, 50},
+}
+
+func (s *MaMPCSuite) descriptions() spineapi.EventPayload {
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionDescriptionListData,
+		&model.ElectricalConnectionDescriptionListDataType{
+			ElectricalConnectionDescriptionData: []model.ElectricalConnectionDescriptionDataType{
+				{
+					ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+					PositiveEnergyDirection: util.Ptr(model.EnergyDirectionTypeConsume),
+				},
+			},
+		})
+
+	params := &model.ElectricalConnectionParameterDescriptionListDataType{}
+	descriptions := &model.MeasurementDescriptionListDataType{}
+	for id, item := range measurements {
+		params.ElectricalConnectionParameterDescriptionData = append(params.ElectricalConnectionParameterDescriptionData,
+			model.ElectricalConnectionParameterDescriptionDataType{
+				ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+				ParameterId:             util.Ptr(model.ElectricalConnectionParameterIdType(id)),
+				MeasurementId:           util.Ptr(model.MeasurementIdType(id)),
+				AcMeasuredPhases:        util.Ptr(item.phase),
+				AcMeasuredInReferenceTo: util.Ptr(item.reference),
+			})
+		descriptions.MeasurementDescriptionData = append(descriptions.MeasurementDescriptionData,
+			model.MeasurementDescriptionDataType{
+				MeasurementId:   util.Ptr(model.MeasurementIdType(id)),
+				MeasurementType: util.Ptr(item.measurementType),
+				CommodityType:   util.Ptr(model.CommodityTypeTypeElectricity),
+				ScopeType:       util.Ptr(item.scope),
+			})
+	}
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionParameterDescriptionListData, params)
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementDescriptionListData, descriptions)
+}
+
+func (s *MaMPCSuite) values() spineapi.EventPayload {
+	data := &model.MeasurementListDataType{}
+	for id, item := range measurements {
+		data.MeasurementData = append(data.MeasurementData, model.MeasurementDataType{
+			MeasurementId: util.Ptr(model.MeasurementIdType(id)),
+			Value:         model.NewScaledNumberType(item.value),
+		})
+	}
+
+	return testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData, data)
+}
+
+func (s *MaMPCSuite) Test_AddFeatures() {
+	for _, featureType := range []model.FeatureTypeType{
+		model.FeatureTypeTypeElectricalConnection,
+		model.FeatureTypeTypeMeasurement,
+	} {
+		assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(featureType, model.RoleTypeClient))
+	}
+
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(
+		model.UseCaseActorTypeMonitoringAppliance, model.UseCaseNameTypeMonitoringOfPowerConsumption))
+}
+
+func (s *MaMPCSuite) Test_EntityConnected() {
+	s.connect()
+
+	assert.True(s.T(), s.sut.HasRemoteEntity(s.remoteEntity))
+	assert.True(s.T(), s.hasEvent(api.UseCaseSupportUpdate))
+	// subscriptions and requests got sent
+	assert.True(s.T(), s.writeHandler.Count() >= 6)
+}
+
+func (s *MaMPCSuite) Test_HandleDataChange() {
+	s.connect()
+
+	payload := s.descriptions()
+	count := s.writeHandler.Count()
+	s.sut.HandleEvent(payload)
+	// the measurement values got requested
+	assert.Equal(s.T(), count+1, s.writeHandler.Count())
+
+	s.sut.HandleEvent(s.values())
+	for _, event := range []api.EventType{
+		DataUpdatePower,
+		DataUpdatePowerPerPhase,
+		DataUpdateEnergyConsumed,
+		DataUpdateEnergyProduced,
+		DataUpdateCurrentsPerPhase,
+		DataUpdateVoltagePerPhase,
+		DataUpdateFrequency,
+	} {
+		assert.True(s.T(), s.hasEvent(event), event)
+	}
+}
+
+func (s *MaMPCSuite) Test_HandleDataChange_Partial() {
+	s.connect()
+	s.descriptions()
+
+	payload := testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeMeasurement,
+		model.FunctionTypeMeasurementListData,
+		&model.MeasurementListDataType{
+			MeasurementData: []model.MeasurementDataType{
+				{
+					MeasurementId: util.Ptr(model.MeasurementIdType(15)),
+					Value:         model.NewScaledNumberType(50),
+				},
+			},
+		})
+	s.sut.HandleEvent(payload)
+
+	assert.True(s.T(), s.hasEvent(DataUpdateFrequency))
+	assert.False(s.T(), s.hasEvent(DataUpdatePower))
+}
@@ -0,0 +1,207 @@
+package mpc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// the phases of phase specific measurements
+var phases = []model.ElectricalConnectionPhaseNameType{
+	model.ElectricalConnectionPhaseNameTypeA,
+	model.ElectricalConnectionPhaseNameTypeB,
+	model.ElectricalConnectionPhaseNameTypeC,
+}
+
+// the phase pairs of phase-to-phase voltage measurements
+var phasePairs = []model.ElectricalConnectionPhaseNameType{
+	model.ElectricalConnectionPhaseNameTypeAb,
+	model.ElectricalConnectionPhaseNameTypeBc,
+	model.ElectricalConnectionPhaseNameTypeAc,
+}
+
+// return the value of a single electricity measurement
+func (e *MPC) value(
+	entity spineapi.EntityRemoteInterface,
+	measurementType model.MeasurementTypeType,
+	scope model.ScopeTypeType,
+	energyDirection model.EnergyDirectionType,
+) (float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return 0, api.ErrUsecCaseNotSupported
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: measurementType,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       scope,
+	}
+
+	return internal.MeasurementValue(e.LocalEntity, entity, filter, energyDirection)
+}
+
+// return the values of phase specific electricity measurements
+func (e *MPC) phaseValues(
+	entity spineapi.EntityRemoteInterface,
+	measurementType model.MeasurementTypeType,
+	scope model.ScopeTypeType,
+	phases []model.ElectricalConnectionPhaseNameType,
+	reference model.ElectricalConnectionPhaseNameType,
+	energyDirection model.EnergyDirectionType,
+) ([]float64, error) {
+	if !e.HasRemoteEntity(entity) {
+		return nil, api.ErrUsecCaseNotSupported
+	}
+
+	filter := internal.MeasurementFilter{
+		MeasurementType: measurementType,
+		CommodityType:   model.CommodityTypeTypeElectricity,
+		ScopeType:       scope,
+	}
+
+	return internal.MeasurementPhaseValues(e.LocalEntity, entity, filter, phases, reference, energyDirection)
+}
+
+// Scenario 1
+
+// return the momentary total active power consumption or production
+//
+// parameters:
+//   - entity: the entity of the monitored unit
+//
+// return values:
+//   - positive values are used for consumption, negative values for production
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MPC) Power(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.value(entity, model.MeasurementTypeTypePower, model.ScopeTypeTypeACPowerTotal, model.EnergyDirectionTypeConsume)
+}
+
+// return the momentary phase specific active power consumption or production
+//
+// parameters:
+//   - entity: the entity of the monitored unit
+//
+// return values:
+//   - the values of the available phases, ordered by phase A, B and C
+//   - positive values are used for consumption, negative values for production
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MPC) PowerPerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	return e.phaseValues(entity, model.MeasurementTypeTypePower, model.ScopeTypeTypeACPower,
+		phases, model.ElectricalConnectionPhaseNameTypeNone, model.EnergyDirectionTypeConsume)
+}
+
+// Scenario 2
+
+// return the total consumed energy in Wh
+//
+// parameters:
+//   - entity: the entity of the monitored unit
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MPC) EnergyConsumed(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.value(entity, model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeACEnergyConsumed, "")
+}
+
+// return the total produced energy in Wh
+//
+// parameters:
+//   - entity: the entity of the monitored unit
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MPC) EnergyProduced(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.value(entity, model.MeasurementTypeTypeEnergy, model.ScopeTypeTypeACEnergyProduced, "")
+}
+
+// Scenario 3
+
+// return the momentary phase specific current consumption or production
+//
+// parameters:
+//   - entity: the entity of the monitored unit
+//
+// return values:
+//   - the values of the available phases, ordered by phase A, B and C
+//   - positive values are used for consumption, negative values for production
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MPC) CurrentPerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	return e.phaseValues(entity, model.MeasurementTypeTypeCurrent, model.ScopeTypeTypeACCurrent,
+		phases, model.ElectricalConnectionPhaseNameTypeNone, model.EnergyDirectionTypeConsume)
+}
+
+// Scenario 4
+
+// return the phase specific voltages measured in reference to neutral
+//
+// parameters:
+//   - entity: the entity of the monitored unit
+//
+// return values:
+//   - the values of the available phases, ordered by phase A, B and C
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MPC) VoltagePerPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	return e.phaseValues(entity, model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage,
+		phases, model.ElectricalConnectionPhaseNameTypeNeutral, "")
+}
+
+// return the voltages measured between the phases
+//
+// parameters:
+//   - entity: the entity of the monitored unit
+//
+// return values:
+//   - the values of the available phase pairs, ordered by AB, BC and AC
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement or parameter description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MPC) VoltagePhaseToPhase(entity spineapi.EntityRemoteInterface) ([]float64, error) {
+	return e.phaseValues(entity, model.MeasurementTypeTypeVoltage, model.ScopeTypeTypeACVoltage,
+		phasePairs, model.ElectricalConnectionPhaseNameTypeNone, "")
+}
+
+// Scenario 5
+
+// return the grid frequency in Hz
+//
+// parameters:
+//   - entity: the entity of the monitored unit
+//
+// possible errors:
+//   - ErrUsecCaseNotSupported if the entity does not support the use case
+//   - ErrMetadataNotAvailable if no measurement description is available
+//   - ErrDataNotAvailable if no measurement value is available
+//   - and others
+func (e *MPC) Frequency(entity spineapi.EntityRemoteInterface) (float64, error) {
+	return e.value(entity, model.MeasurementTypeTypeFrequency, model.ScopeTypeTypeACFrequency, "")
+}
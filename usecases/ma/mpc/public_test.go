@@ -0,0 +1,114 @@
+package mpc
+
+import (
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/usecases/internal/testhelper"
+	"github.com/enbility/eebus-go/util"
+	"github.com/enbility/spine-go/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *MaMPCSuite) Test_Power() {
+	_, err := s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrMetadataNotAvailable, err)
+
+	s.descriptions()
+
+	_, err = s.sut.Power(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrDataNotAvailable, err)
+
+	s.values()
+
+	value, err := s.sut.Power(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 3000.0, value)
+
+	values, err := s.sut.PowerPerPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{1000, 1000, 1000}, values)
+
+	// production is reported as negative values
+	testhelper.SetRemoteData(s.remoteEntity, model.FeatureTypeTypeElectricalConnection,
+		model.FunctionTypeElectricalConnectionDescriptionListData,
+		&model.ElectricalConnectionDescriptionListDataType{
+			ElectricalConnectionDescriptionData: []model.ElectricalConnectionDescriptionDataType{
+				{
+					ElectricalConnectionId:  util.Ptr(model.ElectricalConnectionIdType(0)),
+					PositiveEnergyDirection: util.Ptr(model.EnergyDirectionTypeProduce),
+				},
+			},
+		})
+
+	value, err = s.sut.Power(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), -3000.0, value)
+}
+
+func (s *MaMPCSuite) Test_Energy() {
+	_, err := s.sut.EnergyConsumed(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+	_, err = s.sut.EnergyProduced(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	value, err := s.sut.EnergyConsumed(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1500.0, value)
+
+	value, err = s.sut.EnergyProduced(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 500.0, value)
+}
+
+func (s *MaMPCSuite) Test_CurrentPerPhase() {
+	_, err := s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	values, err := s.sut.CurrentPerPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{4.3, 4.4, 4.5}, values)
+}
+
+func (s *MaMPCSuite) Test_Voltage() {
+	_, err := s.sut.VoltagePerPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+	_, err = s.sut.VoltagePhaseToPhase(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	values, err := s.sut.VoltagePerPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{230, 231, 232}, values)
+
+	values, err = s.sut.VoltagePhaseToPhase(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []float64{400, 401, 402}, values)
+}
+
+func (s *MaMPCSuite) Test_Frequency() {
+	_, err := s.sut.Frequency(s.remoteEntity)
+	assert.Equal(s.T(), api.ErrUsecCaseNotSupported, err)
+
+	s.connect()
+	s.descriptions()
+	s.values()
+
+	value, err := s.sut.Frequency(s.remoteEntity)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 50.0, value)
+}
@@ -0,0 +1,171 @@
+package usecases
+
+import (
+	"time"
+
+	"github.com/enbility/spine-go/model"
+)
+
+// details about a power limit of a load control
+type LoadLimit struct {
+	// the duration the limit is active for, 0 if the limit has no end
+	Duration time.Duration
+
+	// if the limit can be changed via a write, ignored when writing
+	IsChangeable bool
+
+	// if the limit is active
+	IsActive bool
+
+	// the value of the limit in W
+	Value float64
+}
+
+// the state of a controllable system whose power consumption or production
+// can be limited by an energy guard
+type ControlState string
+
+const (
+	// the state after startup until a limit is received or the init timeout elapsed,
+	// the failsafe limit applies
+	ControlStateInit ControlState = "Init"
+
+	// the energy guard is connected and did not set an active limit
+	ControlStateUnlimitedControlled ControlState = "UnlimitedControlled"
+
+	// the energy guard is connected and set an active limit which applies
+	ControlStateLimited ControlState = "Limited"
+
+	// the heartbeat of the energy guard timed out, the failsafe limit applies
+	// at least for the failsafe duration minimum
+	ControlStateFailsafe ControlState = "Failsafe"
+
+	// no energy guard is connected, no limit applies
+	ControlStateUnlimitedAutonomous ControlState = "UnlimitedAutonomous"
+)
+
+// invoked whenever the control state of a controllable system changed
+type ControlStateCallback func(state ControlState)
+
+// an identification of a remote entity, e.g. the MAC address of an EV
+type IdentificationItem struct {
+	// the identification value
+	Value string
+
+	// the type of the identification value, e.g. eui48
+	ValueType model.IdentificationTypeType
+}
+
+// the manufacturer details of a remote entity, empty if not provided
+type ManufacturerData struct {
+	DeviceName                     string
+	DeviceCode                     string
+	SerialNumber                   string
+	SoftwareRevision               string
+	HardwareRevision               string
+	VendorName                     string
+	VendorCode                     string
+	BrandName                      string
+	PowerSource                    string
+	ManufacturerNodeIdentification string
+	ManufacturerLabel              string
+	ManufacturerDescription        string
+}
+
+// details about a phase specific current limit of a load control
+type LoadLimitsPhase struct {
+	// the phase the limit applies to
+	Phase model.ElectricalConnectionPhaseNameType
+
+	// if the limit can be changed via a write, ignored when writing
+	IsChangeable bool
+
+	// if the limit is active
+	IsActive bool
+
+	// the value of the limit in A
+	Value float64
+}
+
+// the energy demand of an EV
+type Demand struct {
+	// the minimum demand in Wh to reach the minimum SoC setting, 0 if not set
+	MinDemand float64
+
+	// the demand in Wh to reach the timer SoC setting
+	OptDemand float64
+
+	// the maximum demand in Wh until the battery is full
+	MaxDemand float64
+
+	// the duration from now until charging starts, 0 if charging starts now
+	DurationUntilStart time.Duration
+
+	// the duration from now until the demand has to be reached, i.e. the
+	// departure time, 0 if no departure time is set
+	DurationUntilEnd time.Duration
+}
+
+// a slot of a time series, starting at the end of the previous slot
+type DurationSlotValue struct {
+	// the duration of the slot
+	Duration time.Duration
+
+	// the value of the slot, e.g. the maximum power in W
+	Value float64
+}
+
+// the constraints of the slots of a time series, 0 if not limited
+type TimeSlotConstraints struct {
+	MinSlots             uint
+	MaxSlots             uint
+	MinSlotDuration      time.Duration
+	MaxSlotDuration      time.Duration
+	SlotDurationStepSize time.Duration
+}
+
+// the constraints of the slots of an incentive table, 0 if not limited
+type IncentiveSlotConstraints struct {
+	MinSlots uint
+	MaxSlots uint
+}
+
+// the description of a tier of an incentive table
+type IncentiveTableDescriptionTier struct {
+	Id         model.TierIdType
+	Type       model.TierTypeType
+	Boundaries []TierBoundaryDescription
+	Incentives []IncentiveDescription
+}
+
+// the description of a boundary of an incentive table tier
+type TierBoundaryDescription struct {
+	Id   model.TierBoundaryIdType
+	Type model.TierBoundaryTypeType
+	Unit model.UnitOfMeasurementType
+}
+
+// the description of an incentive of an incentive table tier
+type IncentiveDescription struct {
+	Id       model.IncentiveIdType
+	Type     model.IncentiveTypeType
+	Currency model.CurrencyType
+}
+
+// the charge plan of an EV
+type ChargePlan struct {
+	Slots []ChargePlanSlotValue
+}
+
+// a slot of the charge plan of an EV
+type ChargePlanSlotValue struct {
+	Start time.Time
+	End   time.Time
+
+	// the planned power in W
+	Value float64
+
+	// the minimum and maximum power in W, 0 if not provided
+	MinValue float64
+	MaxValue float64
+}
@@ -0,0 +1,336 @@
+package usecases
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/features"
+	"github.com/enbility/ship-go/logging"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+)
+
+// interface for the use case specific handling of remote entities
+//
+// implemented by the use case implementations, used by UseCase
+type EntityHandlerInterface interface {
+	// invoked once a remote entity supports the use case,
+	// the subscriptions to the remote features are already requested
+	//
+	// used to request the initial data, e.g. descriptions and constraints
+	EntityConnected(entity spineapi.EntityRemoteInterface)
+
+	// invoked once a remote entity does not support the use case anymore
+	// or got disconnected
+	EntityDisconnected(entity spineapi.EntityRemoteInterface)
+
+	// invoked for every data change of a remote entity supporting the use case
+	//
+	// this includes notifications and replies of remote server features
+	// as well as writes of remote client features on local server features
+	HandleDataChange(payload spineapi.EventPayload)
+}
+
+// UseCase implements the common parts of all use cases
+//
+// It adds the use case support to the local entity, detects remote entities
+// supporting the use case, subscribes to their server features and forwards
+// their data changes to the use case specific EntityHandlerInterface
+type UseCase struct {
+	LocalEntity spineapi.EntityLocalInterface
+
+	actor       model.UseCaseActorType
+	name        model.UseCaseNameType
+	version     model.SpecificationVersionType
+	subRevision string
+	scenarios   []model.UseCaseScenarioSupportType
+
+	// the actors and entity types of remote entities supporting the use case
+	remoteActors      []model.UseCaseActorType
+	remoteEntityTypes []model.EntityTypeType
+
+	// the server feature types a remote entity has to provide,
+	// a local client feature is added for each of them
+	featureTypes []model.FeatureTypeType
+
+	eventCB api.EntityEventCallback
+	handler EntityHandlerInterface
+
+	// the remote entities currently supporting the use case
+	remoteEntities []spineapi.EntityRemoteInterface
+
+	mux sync.Mutex
+}
+
+// creates a new use case for the given local entity
+//
+// remoteActors and remoteEntityTypes define the remote entities which
+// may support the use case, featureTypes the server features a remote
+// entity has to provide. eventCB is optional and invoked for all events
+// reported for a remote entity
+func NewUseCase(
+	localEntity spineapi.EntityLocalInterface,
+	actor model.UseCaseActorType,
+	name model.UseCaseNameType,
+	version model.SpecificationVersionType,
+	subRevision string,
+	scenarios []model.UseCaseScenarioSupportType,
+	remoteActors []model.UseCaseActorType,
+	remoteEntityTypes []model.EntityTypeType,
+	featureTypes []model.FeatureTypeType,
+	eventCB api.EntityEventCallback,
+	handler EntityHandlerInterface,
+) *UseCase {
+	return &UseCase{
+		LocalEntity:       localEntity,
+		actor:             actor,
+		name:              name,
+		version:           version,
+		subRevision:       subRevision,
+		scenarios:         scenarios,
+		remoteActors:      remoteActors,
+		remoteEntityTypes: remoteEntityTypes,
+		featureTypes:      featureTypes,
+		eventCB:           eventCB,
+		handler:           handler,
+	}
+}
+
+var _ api.UseCaseInterface = (*UseCase)(nil)
+
+func (u *UseCase) UseCaseActor() model.UseCaseActorType {
+	return u.actor
+}
+
+func (u *UseCase) UseCaseName() model.UseCaseNameType {
+	return u.name
+}
+
+// add a client feature for each required remote server feature
+//
+// use cases providing server features have to add them themselves
+func (u *UseCase) AddFeatures() {
+	for _, featureType := range u.featureTypes {
+		_ = u.LocalEntity.GetOrAddFeature(featureType, model.RoleTypeClient)
+	}
+}
+
+func (u *UseCase) AddUseCase() {
+	u.LocalEntity.AddUseCaseSupport(
+		u.actor,
+		u.name,
+		u.version,
+		u.subRevision,
+		true,
+		u.scenarios)
+}
+
+func (u *UseCase) UpdateUseCaseAvailability(available bool) {
+	u.LocalEntity.SetUseCaseAvailability(u.actor, u.name, available)
+}
+
+// return the use case support announced by the remote device for the entity,
+// nil if the use case is not announced as available
+func (u *UseCase) remoteUseCaseSupport(remoteEntity spineapi.EntityRemoteInterface) *model.UseCaseSupportType {
+	for _, item := range remoteEntity.Device().UseCases() {
+		if item.Actor == nil || !slices.Contains(u.remoteActors, *item.Actor) {
+			continue
+		}
+
+		if item.Address != nil && item.Address.Entity != nil &&
+			!slices.Equal(item.Address.Entity, remoteEntity.Address().Entity) {
+			continue
+		}
+
+		for _, support := range item.UseCaseSupport {
+			if support.UseCaseName == nil || *support.UseCaseName != u.name {
+				continue
+			}
+
+			if support.UseCaseAvailable != nil && !*support.UseCaseAvailable {
+				continue
+			}
+
+			return &support
+		}
+	}
+
+	return nil
+}
+
+// return if the remote entity supports the use case
+//
+// the entity has to be of a valid type, the remote device has to announce
+// the use case for the entity and the entity has to provide all required
+// server features
+func (u *UseCase) IsUseCaseSupported(remoteEntity spineapi.EntityRemoteInterface) bool {
+	if remoteEntity == nil || remoteEntity.Device() == nil ||
+		!slices.Contains(u.remoteEntityTypes, remoteEntity.EntityType()) {
+		return false
+	}
+
+	if u.remoteUseCaseSupport(remoteEntity) == nil {
+		return false
+	}
+
+	remoteDevice := remoteEntity.Device()
+	for _, featureType := range u.featureTypes {
+		if remoteDevice.FeatureByEntityTypeAndRole(remoteEntity, featureType, model.RoleTypeServer) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// return if the remote entity supports the use case and announced
+// support for the scenario
+//
+// remote entities which do not announce their supported scenarios
+// are considered to support all of them
+func (u *UseCase) IsScenarioAvailableAtEntity(
+	remoteEntity spineapi.EntityRemoteInterface,
+	scenario model.UseCaseScenarioSupportType,
+) bool {
+	if !u.HasRemoteEntity(remoteEntity) {
+		return false
+	}
+
+	support := u.remoteUseCaseSupport(remoteEntity)
+	if support == nil {
+		return false
+	}
+
+	return len(support.ScenarioSupport) == 0 || slices.Contains(support.ScenarioSupport, scenario)
+}
+
+func (u *UseCase) RemoteEntities() []spineapi.EntityRemoteInterface {
+	u.mux.Lock()
+	defer u.mux.Unlock()
+
+	return slices.Clone(u.remoteEntities)
+}
+
+// return if the remote entity is currently known to support the use case
+func (u *UseCase) HasRemoteEntity(remoteEntity spineapi.EntityRemoteInterface) bool {
+	u.mux.Lock()
+	defer u.mux.Unlock()
+
+	return slices.Contains(u.remoteEntities, remoteEntity)
+}
+
+// report an event for a remote entity to the event callback
+func (u *UseCase) ReportEvent(remoteEntity spineapi.EntityRemoteInterface, event api.EventType) {
+	if u.eventCB == nil || remoteEntity == nil || remoteEntity.Device() == nil {
+		return
+	}
+
+	remoteDevice := remoteEntity.Device()
+	u.eventCB(remoteDevice.Ski(), remoteDevice, remoteEntity, event)
+}
+
+// handle SPINE events
+//
+// remote entities are checked for use case support whenever entities or
+// the announced use cases of a remote device change
+func (u *UseCase) HandleEvent(payload spineapi.EventPayload) {
+	switch payload.EventType {
+	case spineapi.EventTypeDeviceChange:
+		if payload.ChangeType == spineapi.ElementChangeRemove {
+			u.removeEntitiesOfSki(payload.Ski)
+		}
+
+	case spineapi.EventTypeEntityChange:
+		if payload.ChangeType == spineapi.ElementChangeRemove {
+			u.removeEntity(payload.Entity)
+			return
+		}
+		u.checkRemoteDevice(payload.Device)
+
+	case spineapi.EventTypeDataChange:
+		if _, ok := payload.Data.(*model.NodeManagementUseCaseDataType); ok {
+			u.checkRemoteDevice(payload.Device)
+			return
+		}
+
+		if u.handler != nil && u.HasRemoteEntity(payload.Entity) {
+			u.handler.HandleDataChange(payload)
+		}
+	}
+}
+
+// check all entities of a remote device for use case support
+func (u *UseCase) checkRemoteDevice(remoteDevice spineapi.DeviceRemoteInterface) {
+	if remoteDevice == nil {
+		return
+	}
+
+	for _, entity := range remoteDevice.Entities() {
+		supported := u.IsUseCaseSupported(entity)
+		known := u.HasRemoteEntity(entity)
+
+		switch {
+		case supported && !known:
+			u.addEntity(entity)
+		case !supported && known:
+			u.removeEntity(entity)
+		}
+	}
+}
+
+// add a remote entity supporting the use case and subscribe to its server features
+func (u *UseCase) addEntity(remoteEntity spineapi.EntityRemoteInterface) {
+	u.mux.Lock()
+	u.remoteEntities = append(u.remoteEntities, remoteEntity)
+	u.mux.Unlock()
+
+	for _, featureType := range u.featureTypes {
+		feature, err := features.NewFeature(featureType, u.LocalEntity, remoteEntity)
+		if err != nil {
+			logging.Log().Debug(err)
+			continue
+		}
+
+		if feature.HasSubscription() {
+			continue
+		}
+
+		if _, err := feature.Subscribe(); err != nil {
+			logging.Log().Debug(err)
+		}
+	}
+
+	if u.handler != nil {
+		u.handler.EntityConnected(remoteEntity)
+	}
+
+	u.ReportEvent(remoteEntity, api.UseCaseSupportUpdate)
+}
+
+// remove a remote entity not supporting the use case anymore
+func (u *UseCase) removeEntity(remoteEntity spineapi.EntityRemoteInterface) {
+	u.mux.Lock()
+	index := slices.Index(u.remoteEntities, remoteEntity)
+	if index < 0 {
+		u.mux.Unlock()
+		return
+	}
+	u.remoteEntities = slices.Delete(u.remoteEntities, index, index+1)
+	u.mux.Unlock()
+
+	if u.handler != nil {
+		u.handler.EntityDisconnected(remoteEntity)
+	}
+
+	u.ReportEvent(remoteEntity, api.UseCaseSupportUpdate)
+}
+
+// remove all remote entities of a disconnected remote device
+func (u *UseCase) removeEntitiesOfSki(ski string) {
+	for _, entity := range u.RemoteEntities() {
+		if entity.Device() != nil && entity.Device().Ski() == ski {
+			u.removeEntity(entity)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	"github.com/enbility/eebus-go/api"
+	"github.com/enbility/eebus-go/util"
+	shipapi "github.com/enbility/ship-go/api"
+	spineapi "github.com/enbility/spine-go/api"
+	"github.com/enbility/spine-go/model"
+	"github.com/enbility/spine-go/spine"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestUseCaseSuite(t *testing.T) {
+	suite.Run(t, new(UseCaseSuite))
+}
+
+type UseCaseSuite struct {
+	suite.Suite
+
+	localEntity  spineapi.EntityLocalInterface
+	remoteDevice spineapi.DeviceRemoteInterface
+	remoteEntity spineapi.EntityRemoteInterface
+
+	sut *UseCase
+
+	sentMessage  []byte
+	events       []api.EventType
+	connected    []spineapi.EntityRemoteInterface
+	disconnected []spineapi.EntityRemoteInterface
+	dataChanges  []spineapi.EventPayload
+}
+
+var _ shipapi.ShipConnectionDataWriterInterface = (*UseCaseSuite)(nil)
+var _ EntityHandlerInterface = (*UseCaseSuite)(nil)
+
+func (s *UseCaseSuite) WriteShipMessageWithPayload(message []byte) {
+	s.sentMessage = message
+}
+
+func (s *UseCaseSuite) EntityConnected(entity spineapi.EntityRemoteInterface) {
+	s.connected = append(s.connected, entity)
+}
+
+func (s *UseCaseSuite) EntityDisconnected(entity spineapi.EntityRemoteInterface) {
+	s.disconnected = append(s.disconnected, entity)
+}
+
+func (s *UseCaseSuite) HandleDataChange(payload spineapi.EventPayload) {
+	s.dataChanges = append(s.dataChanges, payload)
+}
+
+func (s *UseCaseSuite) Event(ski string, device spineapi.DeviceRemoteInterface, entity spineapi.EntityRemoteInterface, event api.EventType) {
+	s.events = append(s.events, event)
+}
+
+func (s *UseCaseSuite) BeforeTest(suiteName, testName string) {
+	s.sentMessage = nil
+	s.events = nil
+	s.connected = nil
+	s.disconnected = nil
+	s.dataChanges = nil
+
+	localDevice := spine.NewDeviceLocal("TestBrandName", "TestDeviceModel", "TestSerialNumber", "TestDeviceCode",
+		"TestDeviceAddress", model.DeviceTypeTypeEnergyManagementSystem, model.NetworkManagementFeatureSetTypeSmart, time.Second*4)
+	s.localEntity = spine.NewEntityLocal(localDevice, model.EntityTypeTypeCEM, spine.NewAddressEntityType([]uint{1}))
+	localDevice.AddEntity(s.localEntity)
+
+	remoteDeviceName := "remoteDevice"
+	s.remoteDevice = spine.NewDeviceRemote(localDevice, "test", spine.NewSender(s))
+	data := &model.NodeManagementDetailedDiscoveryDataType{
+		DeviceInformation: &model.NodeManagementDetailedDiscoveryDeviceInformationType{
+			Description: &model.NetworkManagementDeviceDescriptionDataType{
+				DeviceAddress: &model.DeviceAddressType{
+					Device: util.Ptr(model.AddressDeviceType(remoteDeviceName)),
+				},
+			},
+		},
+		EntityInformation: []model.NodeManagementDetailedDiscoveryEntityInformationType{
+			{
+				Description: &model.NetworkManagementEntityDescriptionDataType{
+					EntityAddress: &model.EntityAddressType{
+						Device: util.Ptr(model.AddressDeviceType(remoteDeviceName)),
+						Entity: []model.AddressEntityType{1},
+					},
+					EntityType: util.Ptr(model.EntityTypeTypeEVSE),
+				},
+			},
+		},
+		FeatureInformation: []model.NodeManagementDetailedDiscoveryFeatureInformationType{
+			{
+				Description: &model.NetworkManagementFeatureDescriptionDataType{
+					FeatureAddress: &model.FeatureAddressType{
+						Device:  util.Ptr(model.AddressDeviceType(remoteDeviceName)),
+						Entity:  []model.AddressEntityType{1},
+						Feature: util.Ptr(model.AddressFeatureType(1)),
+					},
+					FeatureType: util.Ptr(model.FeatureTypeTypeLoadControl),
+					Role:        util.Ptr(model.RoleTypeServer),
+				},
+			},
+		},
+	}
+
+	remoteEntities, err := s.remoteDevice.AddEntityAndFeatures(true, data)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), 1, len(remoteEntities))
+	s.remoteDevice.UpdateDevice(data.DeviceInformation.Description)
+	s.remoteEntity = remoteEntities[0]
+
+	localDevice.AddRemoteDeviceForSki("test", s.remoteDevice)
+
+	s.sut = NewUseCase(
+		s.localEntity,
+		model.UseCaseActorTypeEnergyGuard,
+		model.UseCaseNameTypeLimitationOfPowerConsumption,
+		"1.0.0",
+		"release",
+		[]model.UseCaseScenarioSupportType{1, 2, 3, 4},
+		[]model.UseCaseActorType{model.UseCaseActorTypeControllableSystem},
+		[]model.EntityTypeType{model.EntityTypeTypeEVSE},
+		[]model.FeatureTypeType{model.FeatureTypeTypeLoadControl},
+		s.Event,
+		s,
+	)
+}
+
+// set the use cases the remote device announces
+func (s *UseCaseSuite) setRemoteUseCase(available bool) spineapi.EventPayload {
+	data := &model.NodeManagementUseCaseDataType{
+		UseCaseInformation: []model.UseCaseInformationDataType{
+			{
+				Address: &model.FeatureAddressType{
+					Device: util.Ptr(model.AddressDeviceType("remoteDevice")),
+					Entity: []model.AddressEntityType{1},
+				},
+				Actor: util.Ptr(model.UseCaseActorTypeControllableSystem),
+				UseCaseSupport: []model.UseCaseSupportType{
+					{
+						UseCaseName:      util.Ptr(model.UseCaseNameTypeLimitationOfPowerConsumption),
+						UseCaseAvailable: util.Ptr(available),
+						ScenarioSupport:  []model.UseCaseScenarioSupportType{1, 2, 3, 4},
+					},
+				},
+			},
+		},
+	}
+
+	nodeMgmt := s.remoteDevice.FeatureByEntityTypeAndRole(
+		s.remoteDevice.Entity(spine.DeviceInformationAddressEntity),
+		model.FeatureTypeTypeNodeManagement,
+		model.RoleTypeSpecial)
+	nodeMgmt.UpdateData(model.FunctionTypeNodeManagementUseCaseData, data, nil, nil)
+
+	return spineapi.EventPayload{
+		Ski:        "test",
+		EventType:  spineapi.EventTypeDataChange,
+		ChangeType: spineapi.ElementChangeUpdate,
+		Device:     s.remoteDevice,
+		Entity:     nodeMgmt.Entity(),
+		Feature:    nodeMgmt,
+		Data:       data,
+	}
+}
+
+func (s *UseCaseSuite) Test_UseCase() {
+	assert.Equal(s.T(), model.UseCaseActorTypeEnergyGuard, s.sut.UseCaseActor())
+	assert.Equal(s.T(), model.UseCaseNameTypeLimitationOfPowerConsumption, s.sut.UseCaseName())
+
+	s.sut.AddFeatures()
+	assert.NotNil(s.T(), s.localEntity.FeatureOfTypeAndRole(model.FeatureTypeTypeLoadControl, model.RoleTypeClient))
+
+	s.sut.AddUseCase()
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(s.sut.UseCaseActor(), s.sut.UseCaseName()))
+
+	s.sut.UpdateUseCaseAvailability(false)
+	assert.True(s.T(), s.localEntity.HasUseCaseSupport(s.sut.UseCaseActor(), s.sut.UseCaseName()))
+}
+
+func (s *UseCaseSuite) Test_IsUseCaseSupported() {
+	assert.False(s.T(), s.sut.IsUseCaseSupported(nil))
+	assert.False(s.T(), s.sut.IsUseCaseSupported(s.remoteEntity))
+
+	s.setRemoteUseCase(false)
+	assert.False(s.T(), s.sut.IsUseCaseSupported(s.remoteEntity))
+
+	s.setRemoteUseCase(true)
+	assert.True(s.T(), s.sut.IsUseCaseSupported(s.remoteEntity))
+
+	deviceInformation := s.remoteDevice.Entity(spine.DeviceInformationAddressEntity)
+	assert.False(s.T(), s.sut.IsUseCaseSupported(deviceInformation))
+}
+
+func (s *UseCaseSuite) Test_IsScenarioAvailableAtEntity() {
+	s.sut.AddFeatures()
+
+	assert.False(s.T(), s.sut.IsScenarioAvailableAtEntity(s.remoteEntity, 1))
+
+	s.sut.HandleEvent(s.setRemoteUseCase(true))
+	assert.True(s.T(), s.sut.IsScenarioAvailableAtEntity(s.remoteEntity, 1))
+	assert.True(s.T(), s.sut.IsScenarioAvailableAtEntity(s.remoteEntity, 4))
+	assert.False(s.T(), s.sut.IsScenarioAvailableAtEntity(s.remoteEntity, 5))
+
+	s.sut.HandleEvent(s.setRemoteUseCase(false))
+	assert.False(s.T(), s.sut.IsScenarioAvailableAtEntity(s.remoteEntity, 1))
+}
+
+func (s *UseCaseSuite) Test_HandleEvent() {
+	s.sut.AddFeatures()
+
+	// the use case is not yet announced
+	payload := spineapi.EventPayload{
+		Ski:        "test",
+		EventType:  spineapi.EventTypeEntityChange,
+		ChangeType: spineapi.ElementChangeAdd,
+		Device:     s.remoteDevice,
+		Entity:     s.remoteEntity,
+	}
+	s.sut.HandleEvent(payload)
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+	assert.Equal(s.T(), 0, len(s.connected))
+
+	// data changes of unsupported entities are ignored
+	dataPayload := spineapi.EventPayload{
+		Ski:        "test",
+		EventType:  spineapi.EventTypeDataChange,
+		ChangeType: spineapi.ElementChangeUpdate,
+		Device:     s.remoteDevice,
+		Entity:     s.remoteEntity,
+	}
+	s.sut.HandleEvent(dataPayload)
+	assert.Equal(s.T(), 0, len(s.dataChanges))
+
+	// the use case got announced
+	s.sut.HandleEvent(s.setRemoteUseCase(true))
+	assert.Equal(s.T(), []spineapi.EntityRemoteInterface{s.remoteEntity}, s.sut.RemoteEntities())
+	assert.True(s.T(), s.sut.HasRemoteEntity(s.remoteEntity))
+	assert.Equal(s.T(), 1, len(s.connected))
+	assert.Equal(s.T(), []api.EventType{api.UseCaseSupportUpdate}, s.events)
+	// the subscription got requested
+	assert.NotNil(s.T(), s.sentMessage)
+
+	// the entity is only connected once
+	s.sut.HandleEvent(payload)
+	assert.Equal(s.T(), 1, len(s.connected))
+
+	s.sut.HandleEvent(dataPayload)
+	assert.Equal(s.T(), 1, len(s.dataChanges))
+
+	// the use case is not available anymore
+	s.sut.HandleEvent(s.setRemoteUseCase(false))
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+	assert.Equal(s.T(), 1, len(s.disconnected))
+	assert.Equal(s.T(), 2, len(s.events))
+
+	s.sut.HandleEvent(s.setRemoteUseCase(true))
+	assert.Equal(s.T(), 1, len(s.sut.RemoteEntities()))
+
+	// the entity got removed
+	payload.ChangeType = spineapi.ElementChangeRemove
+	s.sut.HandleEvent(payload)
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+	assert.Equal(s.T(), 2, len(s.disconnected))
+
+	s.sut.HandleEvent(s.setRemoteUseCase(true))
+	assert.Equal(s.T(), 1, len(s.sut.RemoteEntities()))
+
+	// the device got disconnected
+	payload = spineapi.EventPayload{
+		Ski:        "test",
+		EventType:  spineapi.EventTypeDeviceChange,
+		ChangeType: spineapi.ElementChangeRemove,
+	}
+	s.sut.HandleEvent(payload)
+	assert.Equal(s.T(), 0, len(s.sut.RemoteEntities()))
+	assert.Equal(s.T(), 3, len(s.disconnected))
+	assert.Equal(s.T(), 6, len(s.events))
+}